@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"ggltask/pkg/auth"
+)
+
+// runKeygen runs the `keygen` subcommand: it prints a new API key and the hash to put in
+// `custom.auth.apiKeys`, e.g. to bootstrap the first admin key.
+func runKeygen(w io.Writer) error {
+	key, err := auth.GenerateAPIKey()
+	if err != nil {
+		return fmt.Errorf("generate api key failed: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "key:  %s\nhash: %s\n", key, auth.HashAPIKey(key)); err != nil {
+		return fmt.Errorf("write api key failed: %w", err)
+	}
+
+	return nil
+}
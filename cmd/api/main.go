@@ -5,6 +5,8 @@ import (
 	"os"
 	"syscall"
 	"time"
+	// the final image has no zoneinfo, and recurring tasks resolve their time zone by name
+	_ "time/tzdata"
 
 	"ggltask/internal/api"
 	apiCfg "ggltask/internal/api/config"
@@ -29,6 +31,11 @@ const shutdownGracePeriod = 30 * time.Second
 
 // @host      localhost:8080
 // @BasePath  /api/v1
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 An API key or a JWT, as `Bearer <key or token>`.
 func main() {
 	mainCtx, mainStopCtx := context.WithCancel(context.Background())
 
@@ -43,6 +50,25 @@ func main() {
 		logger = logger.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			if err := runMigrate(mainCtx, cfg, &logger, os.Args[2:]); err != nil {
+				logger.Fatal().Err(err).Msg("migrate failed")
+			}
+		case "keygen":
+			if err := runKeygen(os.Stdout); err != nil {
+				logger.Fatal().Err(err).Msg("keygen failed")
+			}
+		default:
+			logger.Fatal().Str("command", os.Args[1]).Msg("unknown command")
+		}
+
+		mainStopCtx()
+
+		return
+	}
+
 	shutdownHandler := shutdown.New(
 		&logger,
 		shutdown.WithGracePeriodDuration(shutdownGracePeriod),
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	apiCfg "ggltask/internal/api/config"
+	apiRepo "ggltask/internal/api/repository"
+	"ggltask/pkg/config"
+
+	"github.com/rs/zerolog"
+)
+
+var errMigrateUsage = errors.New("usage: api migrate up|down [steps]|status")
+
+// runMigrate runs the `migrate` subcommand against the database configured under `custom.db`.
+func runMigrate(ctx context.Context, cfg *config.Config[apiCfg.Config], logger *zerolog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	db, dialect, err := apiRepo.OpenDatabase(ctx, cfg.CustomConfig.DB)
+	if err != nil {
+		return fmt.Errorf("open database failed: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := apiRepo.NewMigrator(db, dialect)
+	if err != nil {
+		return fmt.Errorf("load migrations failed: %w", err)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			logger.Info().Uint("version", m.Version).Str("name", m.Name).Msg("migration applied")
+		}
+
+		if err != nil {
+			return fmt.Errorf("migrate up failed: %w", err)
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errMigrateUsage
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			logger.Info().Uint("version", m.Version).Str("name", m.Name).Msg("migration rolled back")
+		}
+
+		if err != nil {
+			return fmt.Errorf("migrate down failed: %w", err)
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("migrate status failed: %w", err)
+		}
+
+		for _, s := range statuses {
+			logger.Info().Uint("version", s.Version).Str("name", s.Name).Bool("applied", s.Applied).Msg("migration status")
+		}
+	default:
+		return errMigrateUsage
+	}
+
+	return nil
+}
@@ -1 +1 @@
-CREATE DATABASE IF NOT EXISTS ggl_task;
\ No newline at end of file
+CREATE DATABASE IF NOT EXISTS ggl_task;
@@ -0,0 +1,25 @@
+// Package migrations embeds the versioned schema migrations of every supported SQL dialect.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed postgres/*.sql mysql/*.sql
+var files embed.FS
+
+// ForDialect returns the migrations written for the given dialect, e.g. "postgres" or "mysql".
+func ForDialect(dialect string) (fs.FS, error) {
+	if _, err := fs.Stat(files, dialect); err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q: %w", dialect, err)
+	}
+
+	sub, err := fs.Sub(files, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("fs.Sub error: %w", err)
+	}
+
+	return sub, nil
+}
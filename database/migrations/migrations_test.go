@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"testing"
+
+	"ggltask/pkg/migrate"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// every dialect must ship the same migration versions so deployments can switch dialect safely.
+func TestForDialect(t *testing.T) {
+	t.Parallel()
+
+	var want []uint
+
+	for _, dialect := range []string{"postgres", "mysql"} {
+		fsys, err := ForDialect(dialect)
+		require.NoError(t, err)
+
+		ms, err := migrate.Load(fsys)
+		require.NoError(t, err)
+		require.NotEmpty(t, ms)
+
+		versions := make([]uint, 0, len(ms))
+		for _, m := range ms {
+			versions = append(versions, m.Version)
+		}
+
+		if want == nil {
+			want = versions
+		}
+
+		assert.Equal(t, want, versions, dialect)
+	}
+
+	_, err := ForDialect("sqlite")
+	assert.Error(t, err)
+}
@@ -0,0 +1,8 @@
+CREATE TABLE tasks (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name       VARCHAR(50)     NOT NULL,
+    status     TINYINT         NOT NULL DEFAULT 0,
+    created_at DATETIME(6)     NOT NULL,
+    updated_at DATETIME(6)     NOT NULL,
+    PRIMARY KEY (id)
+);
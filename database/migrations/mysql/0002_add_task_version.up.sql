@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN version BIGINT UNSIGNED NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+DROP INDEX tasks_deleted_at_idx ON tasks;
+ALTER TABLE tasks DROP COLUMN deleted_at;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks ADD COLUMN deleted_at DATETIME(6) NULL;
+CREATE INDEX tasks_deleted_at_idx ON tasks (deleted_at);
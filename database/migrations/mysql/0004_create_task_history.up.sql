@@ -0,0 +1,10 @@
+CREATE TABLE task_history (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    task_id    BIGINT UNSIGNED NOT NULL,
+    action     VARCHAR(16)     NOT NULL,
+    actor      VARCHAR(255)    NOT NULL,
+    changes    TEXT            NOT NULL,
+    created_at DATETIME(6)     NOT NULL,
+    PRIMARY KEY (id),
+    INDEX task_history_task_id_idx (task_id, id)
+);
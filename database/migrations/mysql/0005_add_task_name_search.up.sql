@@ -0,0 +1,2 @@
+-- InnoDB skips words shorter than innodb_ft_min_token_size (3 by default) and its stopwords
+CREATE FULLTEXT INDEX tasks_name_search_idx ON tasks (name);
@@ -0,0 +1,10 @@
+-- a TEXT column cannot have a default before MySQL 8.0.13; existing rows get the empty string
+ALTER TABLE tasks ADD COLUMN description TEXT         NOT NULL;
+ALTER TABLE tasks ADD COLUMN priority    TINYINT      NOT NULL DEFAULT 0;
+-- due_at holds the instant, due_offset the time zone offset it was given with, in seconds east of UTC
+ALTER TABLE tasks ADD COLUMN due_at      DATETIME(6)  NULL;
+ALTER TABLE tasks ADD COLUMN due_offset  INT          NOT NULL DEFAULT 0;
+-- tags are stored as `,a,b,` so that a tag filter is a LIKE on `%,tag,%`
+ALTER TABLE tasks ADD COLUMN tags        VARCHAR(700) NOT NULL DEFAULT '';
+CREATE INDEX tasks_due_at_idx ON tasks (due_at);
+CREATE INDEX tasks_priority_idx ON tasks (priority);
@@ -0,0 +1,2 @@
+DROP INDEX tasks_parent_id_idx ON tasks;
+ALTER TABLE tasks DROP COLUMN parent_id;
@@ -0,0 +1,2 @@
+-- blocked_by is stored as `,1,2,`, the way tags are
+ALTER TABLE tasks ADD COLUMN blocked_by VARCHAR(500) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX tasks_list_id_idx ON tasks;
+ALTER TABLE tasks DROP COLUMN list_id;
+DROP TABLE lists;
@@ -0,0 +1,14 @@
+-- a TEXT column cannot have a default before MySQL 8.0.13
+CREATE TABLE lists (
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name        VARCHAR(50)     NOT NULL,
+    description TEXT            NOT NULL,
+    created_at  DATETIME(6)     NOT NULL,
+    updated_at  DATETIME(6)     NOT NULL,
+    PRIMARY KEY (id)
+);
+-- the default list, which cannot be deleted and which existing tasks join
+INSERT INTO lists (id, name, description, created_at, updated_at) VALUES (1, 'Inbox', '', UTC_TIMESTAMP(6), UTC_TIMESTAMP(6));
+-- no foreign key: a list is deleted once its tasks are moved or trashed, while trashed tasks may still point to it
+ALTER TABLE tasks ADD COLUMN list_id BIGINT UNSIGNED NOT NULL DEFAULT 1;
+CREATE INDEX tasks_list_id_idx ON tasks (list_id);
@@ -0,0 +1,20 @@
+-- only the default workspace fits the single-tenant schema
+DELETE FROM tasks WHERE workspace_id <> 'default';
+DELETE FROM lists WHERE workspace_id <> 'default';
+DELETE FROM task_history WHERE workspace_id <> 'default';
+ALTER TABLE task_history
+    DROP INDEX task_history_task_id_idx,
+    DROP COLUMN workspace_id,
+    ADD INDEX task_history_task_id_idx (task_id, id);
+-- the auto increments restart after the largest id
+ALTER TABLE lists
+    DROP PRIMARY KEY,
+    DROP COLUMN workspace_id,
+    ADD PRIMARY KEY (id),
+    MODIFY id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;
+ALTER TABLE tasks
+    DROP PRIMARY KEY,
+    DROP COLUMN workspace_id,
+    ADD PRIMARY KEY (id),
+    MODIFY id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;
+DROP TABLE workspace_sequences;
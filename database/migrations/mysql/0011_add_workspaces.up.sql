@@ -0,0 +1,31 @@
+-- ids are allocated per workspace from workspace_sequences, so the auto increments go; they carry on from where those stopped
+CREATE TABLE workspace_sequences (
+    workspace_id VARCHAR(64)     NOT NULL,
+    name         VARCHAR(16)     NOT NULL,
+    last_id      BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (workspace_id, name)
+);
+INSERT INTO workspace_sequences (workspace_id, name, last_id) SELECT 'default', 'tasks', GREATEST(
+    COALESCE(MAX(id), 0),
+    (SELECT COALESCE(AUTO_INCREMENT, 1) - 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'tasks')
+) FROM tasks;
+INSERT INTO workspace_sequences (workspace_id, name, last_id) SELECT 'default', 'lists', GREATEST(
+    COALESCE(MAX(id), 1),
+    (SELECT COALESCE(AUTO_INCREMENT, 1) - 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'lists')
+) FROM lists;
+-- existing rows belong to the default workspace
+ALTER TABLE tasks
+    ADD COLUMN workspace_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    MODIFY id BIGINT UNSIGNED NOT NULL,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (workspace_id, id);
+ALTER TABLE lists
+    ADD COLUMN workspace_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    MODIFY id BIGINT UNSIGNED NOT NULL,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (workspace_id, id);
+-- history entries keep their global id
+ALTER TABLE task_history
+    ADD COLUMN workspace_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    DROP INDEX task_history_task_id_idx,
+    ADD INDEX task_history_task_id_idx (workspace_id, task_id, id);
@@ -0,0 +1,7 @@
+CREATE TABLE tasks (
+    id         BIGSERIAL    PRIMARY KEY,
+    name       VARCHAR(50)  NOT NULL,
+    status     SMALLINT     NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ  NOT NULL,
+    updated_at TIMESTAMPTZ  NOT NULL
+);
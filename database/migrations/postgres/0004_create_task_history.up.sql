@@ -0,0 +1,9 @@
+CREATE TABLE task_history (
+    id         BIGSERIAL    PRIMARY KEY,
+    task_id    BIGINT       NOT NULL,
+    action     VARCHAR(16)  NOT NULL,
+    actor      VARCHAR(255) NOT NULL,
+    changes    TEXT         NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL
+);
+CREATE INDEX task_history_task_id_idx ON task_history (task_id, id);
@@ -0,0 +1 @@
+DROP INDEX tasks_name_search_idx;
@@ -0,0 +1,2 @@
+-- the 'simple' configuration lowercases words without stemming or stopwords, like the in-memory index
+CREATE INDEX tasks_name_search_idx ON tasks USING GIN (to_tsvector('simple', name));
@@ -0,0 +1,7 @@
+DROP INDEX tasks_priority_idx;
+DROP INDEX tasks_due_at_idx;
+ALTER TABLE tasks DROP COLUMN tags;
+ALTER TABLE tasks DROP COLUMN due_offset;
+ALTER TABLE tasks DROP COLUMN due_at;
+ALTER TABLE tasks DROP COLUMN priority;
+ALTER TABLE tasks DROP COLUMN description;
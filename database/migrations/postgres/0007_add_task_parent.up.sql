@@ -0,0 +1,3 @@
+-- no foreign key: a parent may be purged from the trash while its trashed subtasks still point to it
+ALTER TABLE tasks ADD COLUMN parent_id BIGINT NULL;
+CREATE INDEX tasks_parent_id_idx ON tasks (parent_id);
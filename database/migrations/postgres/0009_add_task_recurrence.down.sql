@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN recurrence_time_zone;
+ALTER TABLE tasks DROP COLUMN recurrence_rule;
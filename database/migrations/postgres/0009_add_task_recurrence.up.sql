@@ -0,0 +1,3 @@
+-- an empty recurrence_rule is a task that does not recur
+ALTER TABLE tasks ADD COLUMN recurrence_rule      VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN recurrence_time_zone VARCHAR(64)  NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+CREATE TABLE lists (
+    id          BIGSERIAL    PRIMARY KEY,
+    name        VARCHAR(50)  NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ  NOT NULL,
+    updated_at  TIMESTAMPTZ  NOT NULL
+);
+-- the default list, which cannot be deleted and which existing tasks join
+INSERT INTO lists (id, name, description, created_at, updated_at) VALUES (1, 'Inbox', '', NOW(), NOW());
+SELECT setval(pg_get_serial_sequence('lists', 'id'), 1);
+-- no foreign key: a list is deleted once its tasks are moved or trashed, while trashed tasks may still point to it
+ALTER TABLE tasks ADD COLUMN list_id BIGINT NOT NULL DEFAULT 1;
+CREATE INDEX tasks_list_id_idx ON tasks (list_id);
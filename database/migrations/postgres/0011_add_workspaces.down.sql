@@ -0,0 +1,20 @@
+-- only the default workspace fits the single-tenant schema
+DELETE FROM tasks WHERE workspace_id <> 'default';
+DELETE FROM lists WHERE workspace_id <> 'default';
+DELETE FROM task_history WHERE workspace_id <> 'default';
+DROP INDEX task_history_task_id_idx;
+CREATE INDEX task_history_task_id_idx ON task_history (task_id, id);
+ALTER TABLE task_history DROP COLUMN workspace_id;
+ALTER TABLE lists DROP CONSTRAINT lists_pkey;
+ALTER TABLE lists DROP COLUMN workspace_id;
+ALTER TABLE lists ADD PRIMARY KEY (id);
+CREATE SEQUENCE lists_id_seq OWNED BY lists.id;
+ALTER TABLE lists ALTER COLUMN id SET DEFAULT nextval('lists_id_seq');
+SELECT setval('lists_id_seq', COALESCE(MAX(last_id), 0) + 1, false) FROM workspace_sequences WHERE workspace_id = 'default' AND name = 'lists';
+ALTER TABLE tasks DROP CONSTRAINT tasks_pkey;
+ALTER TABLE tasks DROP COLUMN workspace_id;
+ALTER TABLE tasks ADD PRIMARY KEY (id);
+CREATE SEQUENCE tasks_id_seq OWNED BY tasks.id;
+ALTER TABLE tasks ALTER COLUMN id SET DEFAULT nextval('tasks_id_seq');
+SELECT setval('tasks_id_seq', COALESCE(MAX(last_id), 0) + 1, false) FROM workspace_sequences WHERE workspace_id = 'default' AND name = 'tasks';
+DROP TABLE workspace_sequences;
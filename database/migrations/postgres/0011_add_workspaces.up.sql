@@ -0,0 +1,24 @@
+-- ids are allocated per workspace from workspace_sequences, so the serials go; they carry on from where the serials stopped
+CREATE TABLE workspace_sequences (
+    workspace_id VARCHAR(64) NOT NULL,
+    name         VARCHAR(16) NOT NULL,
+    last_id      BIGINT      NOT NULL,
+    PRIMARY KEY (workspace_id, name)
+);
+INSERT INTO workspace_sequences (workspace_id, name, last_id) SELECT 'default', 'tasks', CASE WHEN is_called THEN last_value ELSE 0 END FROM tasks_id_seq;
+INSERT INTO workspace_sequences (workspace_id, name, last_id) SELECT 'default', 'lists', GREATEST(last_value, 1) FROM lists_id_seq;
+-- existing rows belong to the default workspace
+ALTER TABLE tasks ADD COLUMN workspace_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE tasks ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE tasks_id_seq;
+ALTER TABLE tasks DROP CONSTRAINT tasks_pkey;
+ALTER TABLE tasks ADD PRIMARY KEY (workspace_id, id);
+ALTER TABLE lists ADD COLUMN workspace_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE lists ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE lists_id_seq;
+ALTER TABLE lists DROP CONSTRAINT lists_pkey;
+ALTER TABLE lists ADD PRIMARY KEY (workspace_id, id);
+-- history entries keep their global id
+ALTER TABLE task_history ADD COLUMN workspace_id VARCHAR(64) NOT NULL DEFAULT 'default';
+DROP INDEX task_history_task_id_idx;
+CREATE INDEX task_history_task_id_idx ON task_history (workspace_id, task_id, id);
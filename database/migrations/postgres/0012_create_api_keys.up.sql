@@ -0,0 +1,11 @@
+-- api keys belong to no workspace; only the SHA-256 of a key is stored
+CREATE TABLE api_keys (
+    id          BIGSERIAL    PRIMARY KEY,
+    name        VARCHAR(50)  NOT NULL,
+    prefix      VARCHAR(16)  NOT NULL,
+    hash        CHAR(64)     NOT NULL,
+    scopes      TEXT         NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL,
+    revoked_at  TIMESTAMPTZ  NULL
+);
+CREATE UNIQUE INDEX api_keys_hash_idx ON api_keys (hash);
@@ -0,0 +1,2 @@
+-- the actor who created the task; empty for tasks created before it was recorded, which only admins can delete
+ALTER TABLE tasks ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- the task policy roles of the key, separated by spaces; keys without roles take the default role of the policy
+ALTER TABLE api_keys ADD COLUMN roles VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- the workspaces the key may work in, separated by spaces, * for all; keys without workspaces work in the default one
+ALTER TABLE api_keys ADD COLUMN workspaces VARCHAR(1024) NOT NULL DEFAULT '';
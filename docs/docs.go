@@ -22,9 +22,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/tasks": {
+        "/api/v1/admin/keys": {
             "get": {
-                "description": "List tasks",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the API keys by page, ordered by id, revoked ones included. The keys from the config are not listed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -32,9 +37,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "task"
+                    "admin"
                 ],
-                "summary": "List tasks",
+                "summary": "List API keys",
                 "parameters": [
                     {
                         "minimum": 1,
@@ -54,27 +59,50 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "List tasks response",
+                        "description": "List keys response",
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.ListTasksResponse"
+                            "$ref": "#/definitions/auth_delivery_http.ListKeysResponse"
                         }
                     },
                     "400": {
                         "description": "invalid request",
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the keys:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new task",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue a new API key with the given scopes. The key is in the response only: it is stored hashed. Its scopes, roles and workspaces must all be granted to the principal creating it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -82,45 +110,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "task"
+                    "admin"
                 ],
-                "summary": "Create task",
+                "summary": "Create API key",
                 "parameters": [
                     {
-                        "description": "Create task request",
+                        "description": "Create key request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.CreateTaskRequest"
+                            "$ref": "#/definitions/auth_delivery_http.CreateKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Create task response",
+                        "description": "Create key response",
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.CreateTaskResponse"
+                            "$ref": "#/definitions/auth_delivery_http.CreateKeyResponse"
                         }
                     },
                     "400": {
                         "description": "invalid request",
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the keys:admin scope, or granting more than the principal holds",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "internal error",
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/tasks/{id}": {
-            "put": {
-                "description": "Update a task",
+        "/api/v1/admin/keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke an API key: requests sending it are rejected from then on. Revoking a revoked key changes nothing.",
                 "consumes": [
                     "application/json"
                 ],
@@ -128,32 +179,238 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "task"
+                    "admin"
                 ],
-                "summary": "Update task",
+                "summary": "Revoke API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
+                        "description": "Key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revoke key response",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.RevokeKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the keys:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/auth_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/lists": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the lists of tasks by page, ordered by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "List lists",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "description": "Update task request",
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List lists response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ListListsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new list of tasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Create list",
+                "parameters": [
+                    {
+                        "description": "Create list request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.UpdateTaskRequest"
+                            "$ref": "#/definitions/task_delivery_http.CreateListRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Update task response",
+                        "description": "Create list response",
                         "schema": {
-                            "$ref": "#/definitions/task_delivery_http.UpdateTaskResponse"
+                            "$ref": "#/definitions/task_delivery_http.CreateListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/lists/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Get list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get list response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.GetListResponse"
                         }
                     },
                     "400": {
@@ -162,12 +419,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/task_delivery_http.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
                             "$ref": "#/definitions/task_delivery_http.ErrorResponse"
                         }
                     },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "internal error",
                         "schema": {
@@ -176,8 +451,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a task",
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the name and description of a list",
                 "consumes": [
                     "application/json"
                 ],
@@ -185,21 +465,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "task"
+                    "list"
                 ],
-                "summary": "Delete task",
+                "summary": "Update list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
+                        "description": "List ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update list request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.UpdateListRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "empty result"
+                        "description": "Update list response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.UpdateListResponse"
+                        }
                     },
                     "400": {
                         "description": "invalid request",
@@ -207,12 +499,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/task_delivery_http.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "not found",
                         "schema": {
                             "$ref": "#/definitions/task_delivery_http.ErrorResponse"
                         }
                     },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "internal error",
                         "schema": {
@@ -220,54 +530,2391 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a list. Its tasks are archived into the default list or, with tasks=cascade, moved to the trash\nalong with their subtasks. Its trashed tasks go to the default list when restored. The default list cannot be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "Delete list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "archive",
+                            "cascade"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "ListTasksPolicyArchive",
+                            "ListTasksPolicyCascade"
+                        ],
+                        "description": "Tasks is what becomes of the tasks of the list: archive moves them to the default list and archives them,\ncascade moves them to the trash.",
+                        "name": "tasks",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "empty result"
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "the list is the default list, or the workflow forbids archiving one of its tasks",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/lists/{id}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the tasks of a list, with the filters, sorting and paging of GET /tasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "List tasks of list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The since bounds are inclusive, the before bounds exclusive, all in RFC 3339.",
+                        "name": "created_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor is the next_cursor or prev_cursor of an earlier response; it takes precedence over page_index.",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "due_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IDs is a comma-separated list of task ids, e.g. ` + "`" + `1,2,3` + "`" + `.",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ListID lists the tasks of a list, as GET /lists/{id}/tasks does.",
+                        "name": "list_id",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 50,
+                        "type": "string",
+                        "description": "Name matches a case-insensitive substring of the task name.",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Overdue lists the incomplete tasks whose due date has passed.",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Priority is a priority, optionally prefixed with a comparison among ` + "`" + `\u003e=` + "`" + `, ` + "`" + `\u003e` + "`" + `, ` + "`" + `\u003c=` + "`" + ` and ` + "`" + `\u003c` + "`" + `, e.g. ` + "`" + `\u003e=high` + "`" + `.",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Ready lists the incomplete tasks that no incomplete task blocks.",
+                        "name": "ready",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort is a comma-separated list of id, name, status, priority, created_at and updated_at,\neach optionally prefixed with ` + "`" + `-` + "`" + ` for descending order, e.g. ` + "`" + `created_at,-updated_at` + "`" + `.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status is a status of the workflow, by name or by code.",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maxItems": 20,
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Tag lists the tasks having every given tag; repeat it for several tags, e.g. ` + "`" + `tag=ops\u0026tag=db` + "`" + `.",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "updated_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "updated_since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List tasks response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ListTasksResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the next and previous pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "list not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List tasks, optionally filtered and sorted, by page_index or by cursor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "List tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The since bounds are inclusive, the before bounds exclusive, all in RFC 3339.",
+                        "name": "created_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor is the next_cursor or prev_cursor of an earlier response; it takes precedence over page_index.",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "due_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IDs is a comma-separated list of task ids, e.g. ` + "`" + `1,2,3` + "`" + `.",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ListID lists the tasks of a list, as GET /lists/{id}/tasks does.",
+                        "name": "list_id",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 50,
+                        "type": "string",
+                        "description": "Name matches a case-insensitive substring of the task name.",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Overdue lists the incomplete tasks whose due date has passed.",
+                        "name": "overdue",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Priority is a priority, optionally prefixed with a comparison among ` + "`" + `\u003e=` + "`" + `, ` + "`" + `\u003e` + "`" + `, ` + "`" + `\u003c=` + "`" + ` and ` + "`" + `\u003c` + "`" + `, e.g. ` + "`" + `\u003e=high` + "`" + `.",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Ready lists the incomplete tasks that no incomplete task blocks.",
+                        "name": "ready",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort is a comma-separated list of id, name, status, priority, created_at and updated_at,\neach optionally prefixed with ` + "`" + `-` + "`" + ` for descending order, e.g. ` + "`" + `created_at,-updated_at` + "`" + `.",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status is a status of the workflow, by name or by code.",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maxItems": 20,
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Tag lists the tasks having every given tag; repeat it for several tags, e.g. ` + "`" + `tag=ops\u0026tag=db` + "`" + `.",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "updated_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "updated_since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List tasks response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ListTasksResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "RFC 8288 links to the next and previous pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "list not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Create task",
+                "parameters": [
+                    {
+                        "description": "Create task request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.CreateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Create task response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.CreateTaskResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search tasks by name, most relevant first. Matching is case-insensitive word by word,\nand the last word of the query also matches as a prefix, for autocomplete.\nEach hit carries its name as an HTML fragment with the matched words wrapped in \u003cmark\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Search tasks",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 200,
+                        "type": "string",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Search tasks response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.SearchTasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a task by id. A request whose If-None-Match lists the current ETag, or whose\nIf-Modified-Since is not older than Last-Modified, gets 304 Not Modified without a body.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Get task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the task versions the client has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the task version the client has",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get task response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.GetTaskResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "task version"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "time of the last change of the task"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "task not modified",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "task version"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "time of the last change of the task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a task. Its status can only change along the transitions of the workflow. Completing a recurring task creates its next occurrence, which takes the recurrence over.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Update task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the task versions the update applies to",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Update task request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.UpdateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update task response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.UpdateTaskResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "task has incomplete subtasks or blockers, would be a subtask of itself, or the workflow forbids its new status",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "task has been modified since the If-Match version",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a task to the trash. Its subtasks are trashed along with it or handed over to its parent,\ndepending on the server configuration.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Delete task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "empty result"
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Partially update a task with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902)",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Patch task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "apply the patch only if the task is still at one of these versions",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "merge patch document or array of JSON Patch operations",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Patch task response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.UpdateTaskResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "a JSON Patch test operation failed, or the subtask rules are broken",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "task has been modified since the If-Match version",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/dependencies": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Make a task depend on another one, which must be completed first. Adding an existing dependency\nchanges nothing; a dependency that would create a cycle is rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Add task dependency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Add dependency request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.AddDependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task with its dependencies",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.UpdateTaskResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "task not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "the dependency would create a cycle",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a dependency of a task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Remove task dependency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "blocker_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Task with its remaining dependencies",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.UpdateTaskResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "task or dependency not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the changes of a task, newest first. The actor of a change is the authenticated subject; without credentials, it is the X-Actor header, prefixed with unverified:.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "List task history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List task history response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ListTaskHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/occurrences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Preview the next occurrences of a recurring task, after its current due date.\nA task that does not recur has none.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "List task occurrences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "count",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List occurrences response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ListOccurrencesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "task not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a task out of the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restore task response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.RestoreTaskResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "task version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/subtasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the direct subtasks of a task, ordered by id, with the progress of its subtasks at every depth.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "List subtasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List subtasks response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ListSubtasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "task not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks:batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply a batch of create, update and delete operations, in order. An atomic batch applies\nevery operation or none of them: when one fails, the others report ABORTED.\nOtherwise each operation is applied on its own. Either way the response holds a result per operation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "Batch tasks",
+                "parameters": [
+                    {
+                        "description": "Batch tasks request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.BatchTasksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch tasks response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.BatchTasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List trashed tasks, most recently deleted first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List trash",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List trashed tasks response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ListTasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/trash/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permanently remove a trashed task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "empty result"
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/workflow": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the workflow of the task statuses: the statuses, the initial status of new tasks,\nand the statuses a task can move to from each status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "Get workflow",
+                "responses": {
+                    "200": {
+                        "description": "Get workflow response",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.GetWorkflowResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing the scope of the route",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
     "definitions": {
+        "auth_delivery_http.CreateKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "roles": {
+                    "description": "Roles are the roles of the key in the task policy, such as viewer, editor or admin. Without roles, the key\nhas the default role of the policy.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "description": "Scopes are what the key grants, among tasks:read, tasks:write, keys:admin and * for all of them.",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "workspaces": {
+                    "description": "Workspaces are the workspaces the key may work in, or * for all of them. Without workspaces, the key works\nin the default workspace only.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auth_delivery_http.CreateKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "$ref": "#/definitions/ggltask_internal_auth_domain_entities.APIKey"
+                },
+                "secret": {
+                    "description": "Secret is the key to send as ` + "`" + `Authorization: Bearer \u003csecret\u003e` + "`" + `. It is not stored and cannot be read again.",
+                    "type": "string"
+                }
+            }
+        },
+        "auth_delivery_http.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error_code": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID is the id of the request, for clients to quote when reporting an error.",
+                    "type": "string"
+                }
+            }
+        },
+        "auth_delivery_http.ListKeysResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ggltask_internal_auth_domain_entities.APIKey"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "auth_delivery_http.RevokeKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "$ref": "#/definitions/ggltask_internal_auth_domain_entities.APIKey"
+                }
+            }
+        },
+        "ggltask_internal_auth_domain_entities.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix is the start of the key, enough for its owner to tell it apart from their other keys.",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Roles are the roles the key has in the task policy. A key without roles has the default role of the policy.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "workspaces": {
+                    "description": "Workspaces are the workspaces the key may work in, ` + "`" + `*` + "`" + ` for all. A key without workspaces works in the\ndefault workspace only.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.HistoryAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore"
+            ],
+            "x-enum-varnames": [
+                "HistoryActionCreate",
+                "HistoryActionUpdate",
+                "HistoryActionDelete",
+                "HistoryActionRestore"
+            ]
+        },
+        "ggltask_internal_task_domain_entities.List": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.Recurrence": {
+            "type": "object",
+            "properties": {
+                "rule": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO,WE"
+                },
+                "time_zone": {
+                    "description": "TimeZone is an IANA time zone name, such as Asia/Taipei.",
+                    "type": "string",
+                    "example": "Asia/Taipei"
+                }
+            }
+        },
         "ggltask_internal_task_domain_entities.Task": {
             "type": "object",
             "properties": {
+                "blocked_by": {
+                    "description": "BlockedBy holds the ids of the tasks to complete before this one, sorted.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "created_by": {
+                    "description": "CreatedBy is the actor who created the task, empty when it is unknown.",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "description": "DueAt keeps the time zone offset it was set with, so the due date reads the same as it was entered.",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "list_id": {
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
+                "parent_id": {
+                    "description": "ParentID is the task this one is a subtask of, nil for a top-level task.",
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "recurrence": {
+                    "description": "Recurrence repeats the task from its due date: completing it creates the next occurrence,\nwhich takes the recurrence over.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ggltask_internal_task_domain_entities.Recurrence"
+                        }
+                    ]
+                },
                 "status": {
                     "$ref": "#/definitions/task.TaskStatus"
                 },
+                "tags": {
+                    "description": "Tags is a set of lowercase labels, sorted and without duplicates.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.TaskHistory": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.HistoryAction"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ggltask_internal_task_domain_entities.FieldChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "task_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.TaskProgress": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.TaskSearchHit": {
+            "type": "object",
+            "properties": {
+                "highlight": {
+                    "description": "Highlight is the task name as an HTML fragment with the matched words wrapped in \u003cmark\u003e.",
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Score ranks the hit; it is only comparable between hits of the same query and backend.",
+                    "type": "number"
+                },
+                "task": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.Task"
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.Workflow": {
+            "type": "object",
+            "properties": {
+                "initial": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.WorkflowStatus"
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ggltask_internal_task_domain_entities.WorkflowStatus"
+                    }
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ggltask_internal_task_domain_entities.WorkflowTransition"
+                    }
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.WorkflowStatus": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/task.TaskStatus"
+                        }
+                    ],
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "in_progress"
+                }
+            }
+        },
+        "ggltask_internal_task_domain_entities.WorkflowTransition": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/task.TaskStatus"
+                },
+                "to": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/task.TaskStatus"
+                    }
                 }
             }
         },
+        "ggltask_internal_task_domain_usecase.BatchOperationType": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "BatchOperationCreate",
+                "BatchOperationUpdate",
+                "BatchOperationDelete"
+            ]
+        },
+        "ggltask_internal_task_domain_usecase.ListTasksPolicy": {
+            "type": "string",
+            "enum": [
+                "archive",
+                "cascade"
+            ],
+            "x-enum-varnames": [
+                "ListTasksPolicyArchive",
+                "ListTasksPolicyCascade"
+            ]
+        },
         "task.TaskStatus": {
             "type": "integer",
             "enum": [
                 0,
-                1
+                1,
+                2,
+                3,
+                4,
+                5
             ],
             "x-enum-comments": {
+                "TaskStatusArchived": "task was dropped without being completed",
+                "TaskStatusBlocked": "task cannot progress for now",
                 "TaskStatusCompleted": "task is completed",
+                "TaskStatusInProgress": "task is being worked on",
+                "TaskStatusInReview": "task is done and waits for a review",
                 "TaskStatusIncomplete": "task is incomplete"
             },
             "x-enum-varnames": [
                 "TaskStatusIncomplete",
-                "TaskStatusCompleted"
+                "TaskStatusCompleted",
+                "TaskStatusInProgress",
+                "TaskStatusBlocked",
+                "TaskStatusInReview",
+                "TaskStatusArchived"
             ]
         },
+        "task_delivery_http.AddDependencyRequest": {
+            "type": "object",
+            "required": [
+                "blocker_id"
+            ],
+            "properties": {
+                "blocker_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "task_delivery_http.BatchOperationRequest": {
+            "type": "object",
+            "required": [
+                "op"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "list_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "op": {
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ggltask_internal_task_domain_usecase.BatchOperationType"
+                        }
+                    ]
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.Recurrence"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "in_progress"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "description": "Version makes an update conditional on the task version, as the If-Match header of PUT /tasks/{id} does.",
+                    "type": "integer"
+                }
+            }
+        },
+        "task_delivery_http.BatchOperationResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/task_delivery_http.ErrorResponse"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "task": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.Task"
+                }
+            }
+        },
+        "task_delivery_http.BatchTasksRequest": {
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "atomic": {
+                    "description": "Atomic applies every operation or, as soon as one fails, none of them.\nOtherwise each operation is applied on its own.",
+                    "type": "boolean"
+                },
+                "operations": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/task_delivery_http.BatchOperationRequest"
+                    }
+                }
+            }
+        },
+        "task_delivery_http.BatchTasksResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/task_delivery_http.BatchOperationResponse"
+                    }
+                }
+            }
+        },
+        "task_delivery_http.CreateListRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "task_delivery_http.CreateListResponse": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.List"
+                }
+            }
+        },
         "task_delivery_http.CreateTaskRequest": {
             "type": "object",
             "required": [
                 "name"
             ],
             "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "due_at": {
+                    "description": "DueAt is in RFC 3339; its time zone offset is kept.",
+                    "type": "string"
+                },
+                "list_id": {
+                    "description": "ListID is the list of the task; by default a subtask goes to the list of its parent\nand a top-level task to the default list.",
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 50
+                },
+                "parent_id": {
+                    "description": "ParentID makes the task a subtask of another one.",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "Priority is one of none, low, medium, high and urgent.",
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ]
+                },
+                "recurrence": {
+                    "description": "Recurrence repeats the task from its due date, which is then required.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ggltask_internal_task_domain_entities.Recurrence"
+                        }
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -287,12 +2934,100 @@ const docTemplate = `{
                 },
                 "error_message": {
                     "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID is the id of the request, for clients to quote when reporting an error.",
+                    "type": "string"
+                }
+            }
+        },
+        "task_delivery_http.GetListResponse": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.List"
+                }
+            }
+        },
+        "task_delivery_http.GetTaskResponse": {
+            "type": "object",
+            "properties": {
+                "task": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.Task"
+                }
+            }
+        },
+        "task_delivery_http.GetWorkflowResponse": {
+            "type": "object",
+            "properties": {
+                "workflow": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.Workflow"
+                }
+            }
+        },
+        "task_delivery_http.ListListsResponse": {
+            "type": "object",
+            "properties": {
+                "lists": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ggltask_internal_task_domain_entities.List"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "task_delivery_http.ListOccurrencesResponse": {
+            "type": "object",
+            "properties": {
+                "occurrences": {
+                    "description": "Occurrences are the due dates of the next occurrences, in the time zone of the recurrence.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "task_delivery_http.ListSubtasksResponse": {
+            "type": "object",
+            "properties": {
+                "progress": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.TaskProgress"
+                },
+                "subtasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ggltask_internal_task_domain_entities.Task"
+                    }
+                }
+            }
+        },
+        "task_delivery_http.ListTaskHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ggltask_internal_task_domain_entities.TaskHistory"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
         "task_delivery_http.ListTasksResponse": {
             "type": "object",
             "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
                 "tasks": {
                     "type": "array",
                     "items": {
@@ -304,26 +3039,98 @@ const docTemplate = `{
                 }
             }
         },
+        "task_delivery_http.RestoreTaskResponse": {
+            "type": "object",
+            "properties": {
+                "task": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.Task"
+                }
+            }
+        },
+        "task_delivery_http.SearchTasksResponse": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ggltask_internal_task_domain_entities.TaskSearchHit"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "task_delivery_http.UpdateListRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "task_delivery_http.UpdateListResponse": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.List"
+                }
+            }
+        },
         "task_delivery_http.UpdateTaskRequest": {
             "type": "object",
             "required": [
                 "name"
             ],
             "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "list_id": {
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 50
                 },
-                "status": {
+                "parent_id": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string",
                     "enum": [
-                        0,
-                        1
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/task.TaskStatus"
-                        }
+                        "none",
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
                     ]
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/ggltask_internal_task_domain_entities.Recurrence"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "in_progress"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -335,6 +3142,14 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "An API key or a JWT, as ` + "`" + `Bearer \u003ckey or token\u003e` + "`" + `.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
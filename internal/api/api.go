@@ -33,7 +33,9 @@ func (a *API) Start(ctx context.Context) error {
 	apiS := server.NewServer(a.cfg, a.logger)
 	a.server = apiS
 
-	a.registerHTTPSvc(ctx)
+	if err := a.registerHTTPSvc(ctx); err != nil {
+		return fmt.Errorf("register http service failed: %w", err)
+	}
 
 	if err := apiS.Start(ctx); err != nil {
 		return fmt.Errorf("server start failed: %w", err)
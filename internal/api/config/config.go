@@ -4,16 +4,162 @@ import (
 	"time"
 )
 
+const (
+	StorageDriverMemory = "memory"
+	StorageDriverFile   = "file"
+	StorageDriverSQL    = "sql"
+)
+
 type Config struct {
-	DB Database `yaml:"db" json:"db"`
+	Storage   Storage   `yaml:"storage" json:"storage"`
+	DB        Database  `yaml:"db" json:"db"`
+	Trash     Trash     `yaml:"trash" json:"trash"`
+	Subtask   Subtask   `yaml:"subtask" json:"subtask"`
+	Workflow  Workflow  `yaml:"workflow" json:"workflow"`
+	Auth      Auth      `yaml:"auth" json:"auth"`
+	Policy    Policy    `yaml:"policy" json:"policy"`
+	RateLimit RateLimit `yaml:"rateLimit" json:"rateLimit"`
+}
+
+// Auth configures the authentication of requests. When enabled, every route requires an API key or a JWT, sent as
+// `Authorization: Bearer <key or token>`, granting the scope of the route.
+type Auth struct {
+	Enabled bool `yaml:"enabled" json:"enabled" env:"AUTH_ENABLED" env-default:"true"`
+	// APIKeys are keys accepted besides the stored ones, such as the first admin key. They cannot be revoked through the API.
+	APIKeys []APIKey `yaml:"apiKeys" json:"apiKeys"`
+	JWT     JWT      `yaml:"jwt" json:"jwt"`
+}
+
+// JWT configures the verification of the tokens issued by the gateway. Tokens are accepted only when keys
+// or a JWKS file are given; their scopes come from the scope claim.
+type JWT struct {
+	Issuer   string `yaml:"issuer" json:"issuer" env:"JWT_ISSUER"`
+	Audience string `yaml:"audience" json:"audience" env:"JWT_AUDIENCE"`
+	// RolesClaim is the claim listing the roles of the subject.
+	RolesClaim string `yaml:"rolesClaim" json:"rolesClaim" env-default:"roles"`
+	// WorkspacesClaim is the claim listing the workspaces of the subject; without it, the default workspace.
+	WorkspacesClaim string        `yaml:"workspacesClaim" json:"workspacesClaim" env-default:"workspaces"`
+	Leeway          time.Duration `yaml:"leeway" json:"leeway" env-default:"30s"`
+	Keys            []JWTKey      `yaml:"keys" json:"keys"`
+	// JWKSFile is checked for changes every JWKSReloadInterval, so that rotated keys apply without a restart.
+	JWKSFile           string        `yaml:"jwksFile" json:"jwksFile" env:"JWT_JWKS_FILE"`
+	JWKSReloadInterval time.Duration `yaml:"jwksReloadInterval" json:"jwksReloadInterval" env-default:"1m"`
+}
+
+// Enabled reports whether any key to verify tokens with is configured.
+func (c JWT) Enabled() bool {
+	return len(c.Keys) > 0 || c.JWKSFile != ""
+}
+
+// JWTKey is a static key: the secret of an HS256 key, or the PEM file of an RS256 or ES256 public key.
+type JWTKey struct {
+	// ID matches the kid header of tokens; a key without ID is tried for every token.
+	ID            string `yaml:"id" json:"id"`
+	Secret        string `yaml:"secret" json:"-"`
+	PublicKeyFile string `yaml:"publicKeyFile" json:"publicKeyFile"`
+}
+
+// APIKey is a key given by the hex SHA-256 of the key, never by the key itself.
+type APIKey struct {
+	Name   string   `yaml:"name" json:"name"`
+	Hash   string   `yaml:"hash" json:"hash"`
+	Scopes []string `yaml:"scopes" json:"scopes"`
+	// Roles are the roles of the key in the task policy; without roles, the key has the default role.
+	Roles []string `yaml:"roles" json:"roles"`
+	// Workspaces are the workspaces the key may work in, `*` for all; without workspaces, the default workspace.
+	Workspaces []string `yaml:"workspaces" json:"workspaces"`
+}
+
+// RateLimit configures the token buckets limiting every client, by route group and separately for reads and writes.
+type RateLimit struct {
+	// Key is what a client is: ip, api_key or subject. Requests without an API key or a subject count by IP.
+	Key string `yaml:"key" json:"key" env:"RATE_LIMIT_KEY" env-default:"ip"`
+	// CleanupInterval is how often the buckets of idle clients are dropped.
+	CleanupInterval time.Duration  `yaml:"cleanupInterval" json:"cleanupInterval" env-default:"1m"`
+	Tasks           RateLimitGroup `yaml:"tasks" json:"tasks"`
+	Admin           RateLimitGroup `yaml:"admin" json:"admin"`
+	// Auth limits the failed authentications of every client IP, whatever the key, ahead of authentication.
+	Auth Limit `yaml:"auth" json:"auth"`
+}
+
+// RateLimitGroup is the limits of a route group. Reads are GET, HEAD and OPTIONS requests, writes the others.
+type RateLimitGroup struct {
+	Read  Limit `yaml:"read" json:"read"`
+	Write Limit `yaml:"write" json:"write"`
+}
+
+// Limit lets Requests requests through every Period, and up to Burst at once. Without requests, nothing is limited.
+type Limit struct {
+	Requests int           `yaml:"requests" json:"requests"`
+	Period   time.Duration `yaml:"period" json:"period"`
+	Burst    int           `yaml:"burst" json:"burst"`
+}
+
+// Policy configures which roles may create, update, delete and list tasks, by action name. Without roles,
+// the default policy applies.
+type Policy struct {
+	// Roles are the actions each role may take: create, update, delete and list.
+	Roles map[string][]string `yaml:"roles" json:"roles"`
+	// OwnerOnly are the actions a principal may only take on the tasks it created, unless it is an admin.
+	OwnerOnly []string `yaml:"ownerOnly" json:"ownerOnly"`
+	// DefaultRole is the role of principals carrying none. Without one, such principals may do nothing.
+	DefaultRole string `yaml:"defaultRole" json:"defaultRole"`
+}
+
+// Workflow configures the statuses of tasks and the transitions between them, by status name.
+// Without statuses, the default workflow applies.
+type Workflow struct {
+	// Initial is the status of new tasks.
+	Initial  string   `yaml:"initial" json:"initial"`
+	Statuses []string `yaml:"statuses" json:"statuses"`
+	// Transitions lists, for each status, the statuses a task can move to from it.
+	Transitions map[string][]string `yaml:"transitions" json:"transitions"`
+	// Codes gives the code, stored with tasks, of every status that is not a built-in one. A code must never
+	// be given to another status.
+	Codes map[string]int8 `yaml:"codes" json:"codes"`
+}
+
+// Subtask configures the rules of the task hierarchy.
+type Subtask struct {
+	// AllowIncompleteChildren lets a task be completed while some of its subtasks are not.
+	AllowIncompleteChildren bool `yaml:"allowIncompleteChildren" json:"allowIncompleteChildren" env:"SUBTASK_ALLOW_INCOMPLETE_CHILDREN"`
+	// DeletePolicy is `cascade` to trash the subtasks of a deleted task, or `reparent` to hand them over to its parent.
+	DeletePolicy string `yaml:"deletePolicy" json:"deletePolicy" env:"SUBTASK_DELETE_POLICY" env-default:"cascade"`
+}
+
+// Trash configures how long deleted tasks stay restorable before they are purged for good.
+// A zero RetentionDays keeps trashed tasks forever.
+type Trash struct {
+	RetentionDays int           `yaml:"retentionDays" json:"retentionDays" env:"TRASH_RETENTION_DAYS" env-default:"30"`
+	PurgeInterval time.Duration `yaml:"purgeInterval" json:"purgeInterval" env-default:"1h"`
+}
+
+// Retention returns the retention as a duration.
+func (t Trash) Retention() time.Duration {
+	return time.Duration(t.RetentionDays) * 24 * time.Hour
+}
+
+// Storage selects the backend of the task repository.
+type Storage struct {
+	Driver string      `yaml:"driver" json:"driver" env:"STORAGE_DRIVER" env-default:"memory"`
+	File   FileStorage `yaml:"file" json:"file"`
+}
+
+// FileStorage configures the file backend: a write-ahead log compacted into periodic snapshots.
+type FileStorage struct {
+	Dir              string        `yaml:"dir" json:"dir" env:"STORAGE_FILE_DIR" env-default:"./data"`
+	SnapshotInterval time.Duration `yaml:"snapshotInterval" json:"snapshotInterval" env-default:"1m"`
+	SyncWrites       bool          `yaml:"syncWrites" json:"syncWrites" env-default:"true"`
 }
 
 type Database struct {
+	Dialect      string        `yaml:"dialect" json:"dialect"`
 	Port         string        `yaml:"port" json:"port"`
 	Host         string        `yaml:"host" json:"host"`
 	User         string        `yaml:"user" json:"user"`
 	Password     string        `yaml:"password" json:"password"`
 	Database     string        `yaml:"database" json:"database"`
+	SSLMode      string        `yaml:"sslMode" json:"sslMode"`
 	MaxConns     int32         `yaml:"maxConns" json:"maxConns"`
 	MaxIdleConns int32         `yaml:"maxIdleConns" json:"maxIdleConns"`
 	MaxLifeTime  time.Duration `yaml:"maxLifeTime" json:"maxLifeTime"`
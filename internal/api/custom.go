@@ -2,32 +2,262 @@ package api
 
 import (
 	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	apiCfg "ggltask/internal/api/config"
+	apiRepo "ggltask/internal/api/repository"
+	authHTTP "ggltask/internal/auth/delivery/http"
+	authEntities "ggltask/internal/auth/domain/entities"
+	authUseCase "ggltask/internal/auth/usecase"
+	"ggltask/internal/task"
 	taskHTTP "ggltask/internal/task/delivery/http"
-	taskRepo "ggltask/internal/task/repository/memory"
+	taskJob "ggltask/internal/task/delivery/job"
 	taskUseCase "ggltask/internal/task/usecase"
 
+	"ggltask/pkg/auth"
+	"ggltask/pkg/jwt"
+	"ggltask/pkg/ratelimit"
 	pkgMiddleware "ggltask/pkg/transport/middleware"
+	"ggltask/pkg/workspace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 )
 
 const (
 	defaultTimeout = 10 * time.Second
 )
 
-func (a *API) registerHTTPSvc(_ context.Context) {
-	a.server.SetupHTTPServer()
+func (a *API) registerHTTPSvc(ctx context.Context) error {
+	if err := a.server.SetupHTTPServer(); err != nil {
+		return err
+	}
+
 	httpRouter := a.server.HTTPRouter()
 
-	taskRepository := taskRepo.NewTaskRepository()
+	repos, closeRepos, err := apiRepo.NewRepositories(ctx, a.cfg.CustomConfig, a.logger)
+	if err != nil {
+		return fmt.Errorf("create repositories failed: %w", err)
+	}
+
+	a.shutdownHandler.Add("repositories", closeRepos)
+
+	subtaskCfg := a.cfg.CustomConfig.Subtask
+	if policy := taskUseCase.SubtaskDeletePolicy(subtaskCfg.DeletePolicy); !policy.Valid() {
+		return fmt.Errorf("unknown subtask delete policy %q", subtaskCfg.DeletePolicy)
+	}
+
+	workflowDef := taskUseCase.DefaultWorkflowDefinition
+	if workflowCfg := a.cfg.CustomConfig.Workflow; len(workflowCfg.Statuses) > 0 {
+		workflowDef = taskUseCase.WorkflowDefinition{
+			Initial:     workflowCfg.Initial,
+			Statuses:    workflowCfg.Statuses,
+			Transitions: workflowCfg.Transitions,
+			Codes:       make(map[string]task.TaskStatus, len(workflowCfg.Codes)),
+		}
+
+		for name, code := range workflowCfg.Codes {
+			workflowDef.Codes[name] = task.TaskStatus(code)
+		}
+	}
+
+	workflow, err := taskUseCase.NewWorkflow(workflowDef)
+	if err != nil {
+		return fmt.Errorf("invalid workflow: %w", err)
+	}
+
+	policyDef := taskUseCase.DefaultPolicyDefinition
+	if policyCfg := a.cfg.CustomConfig.Policy; len(policyCfg.Roles) > 0 {
+		policyDef = taskUseCase.PolicyDefinition{
+			Roles:       policyCfg.Roles,
+			OwnerOnly:   policyCfg.OwnerOnly,
+			DefaultRole: policyCfg.DefaultRole,
+		}
+	}
+
+	policy, err := taskUseCase.NewPolicy(policyDef)
+	if err != nil {
+		return fmt.Errorf("invalid policy: %w", err)
+	}
+
+	taskUC := taskUseCase.NewTaskUseCaseImpl(
+		repos.Task,
+		repos.History,
+		repos.List,
+		taskUseCase.WithIncompleteSubtasksAllowed(subtaskCfg.AllowIncompleteChildren),
+		taskUseCase.WithSubtaskDeletePolicy(taskUseCase.SubtaskDeletePolicy(subtaskCfg.DeletePolicy)),
+		taskUseCase.WithWorkflow(workflow),
+		taskUseCase.WithPolicy(policy),
+	)
+
+	if trashCfg := a.cfg.CustomConfig.Trash; trashCfg.RetentionDays > 0 {
+		purger := taskJob.NewTrashPurger(taskUC, trashCfg.Retention(), trashCfg.PurgeInterval, a.logger)
+		purger.Start(ctx)
+		a.shutdownHandler.Add("trash purger", purger.Shutdown)
+	}
 
-	taskUseCase := taskUseCase.NewTaskUseCaseImpl(taskRepository)
+	staticKeys, err := configKeys(a.cfg.CustomConfig.Auth)
+	if err != nil {
+		return fmt.Errorf("invalid api keys: %w", err)
+	}
 
-	httpRouter.Use(
+	keyUseCase := authUseCase.NewKeyUseCaseImpl(repos.Key, authUseCase.WithStaticKeys(staticKeys...))
+
+	authMiddlewares := []gin.HandlerFunc{pkgMiddleware.GinNoAuth(taskUseCase.RoleAdmin)}
+	if authCfg := a.cfg.CustomConfig.Auth; authCfg.Enabled {
+		authMiddlewares = []gin.HandlerFunc{pkgMiddleware.GinAPIKeyAuth(keyUseCase)}
+
+		if authCfg.JWT.Enabled() {
+			verifier, err := jwtVerifier(authCfg.JWT, a.logger)
+			if err != nil {
+				return fmt.Errorf("invalid jwt config: %w", err)
+			}
+
+			authMiddlewares = append(authMiddlewares, pkgMiddleware.GinJWTAuth(verifier))
+		}
+	} else {
+		a.logger.Warn().Msg("authentication is disabled, every request is granted every scope")
+	}
+
+	rateCfg := a.cfg.CustomConfig.RateLimit
+
+	rateKey, err := pkgMiddleware.NewRateLimitKey(rateCfg.Key)
+	if err != nil {
+		return fmt.Errorf("invalid rate limit: %w", err)
+	}
+
+	if rateCfg.CleanupInterval <= 0 {
+		return fmt.Errorf("invalid rate limit: cleanup interval must be positive, got %s", rateCfg.CleanupInterval)
+	}
+
+	limiter := ratelimit.NewLimiter(rateCfg.CleanupInterval)
+	limiter.Start(ctx)
+	a.shutdownHandler.Add("rate limiter", limiter.Shutdown)
+
+	middlewares := []gin.HandlerFunc{
 		pkgMiddleware.GinRecover(),
+		pkgMiddleware.GinRequestID(),
 		pkgMiddleware.GinContextLogger(a.logger), //nolint:contextcheck
-		pkgMiddleware.GinTimeout(defaultTimeout), //nolint:contextcheck
-	)
+		pkgMiddleware.GinActor(),
+		// failed authentications are counted by client IP, ahead of authentication, whatever the route
+		pkgMiddleware.GinAuthRateLimit(limiter, rateLimitOf(rateCfg.Auth)),
+	}
+	middlewares = append(middlewares, authMiddlewares...)
+	// the workspace is checked against the principal, so it follows authentication
+	middlewares = append(middlewares, pkgMiddleware.GinWorkspace(), pkgMiddleware.GinTimeout(defaultTimeout)) //nolint:contextcheck
+
+	httpRouter.Use(middlewares...)
+
+	rateLimit := func(scope string, cfg apiCfg.RateLimitGroup) gin.HandlerFunc {
+		return pkgMiddleware.GinRateLimit(limiter, scope, rateLimitOf(cfg.Read), rateLimitOf(cfg.Write), rateKey)
+	}
+
+	taskHTTP.RegisterTaskRoutes(httpRouter.Group("", rateLimit("tasks", rateCfg.Tasks)), taskUC)
+	authHTTP.RegisterKeyRoutes(httpRouter.Group("", rateLimit("admin", rateCfg.Admin)), keyUseCase)
+
+	return nil
+}
+
+// rateLimitOf converts a limit of the config.
+func rateLimitOf(cfg apiCfg.Limit) ratelimit.Limit {
+	return ratelimit.Limit{Requests: cfg.Requests, Period: cfg.Period, Burst: cfg.Burst}
+}
+
+// configKeys converts the API keys of the config, checking that each is a SHA-256 with known scopes and a name
+// of its own, as the name makes the subject of the key.
+func configKeys(cfg apiCfg.Auth) ([]*authEntities.APIKey, error) {
+	keys := make([]*authEntities.APIKey, 0, len(cfg.APIKeys))
+	names := make(map[string]bool, len(cfg.APIKeys))
+
+	for _, key := range cfg.APIKeys {
+		if key.Name == "" || names[key.Name] {
+			return nil, fmt.Errorf("key %q: name must be set and unique", key.Name)
+		}
+
+		names[key.Name] = true
+
+		if hash, err := hex.DecodeString(key.Hash); err != nil || len(hash) != 32 { //nolint:mnd
+			return nil, fmt.Errorf("key %q: hash must be a hex SHA-256", key.Name)
+		}
+
+		for _, scope := range key.Scopes {
+			if !auth.ValidScope(scope) {
+				return nil, fmt.Errorf("key %q: unknown scope %q", key.Name, scope)
+			}
+		}
+
+		for _, id := range key.Workspaces {
+			if !workspace.ValidGrant(id) {
+				return nil, fmt.Errorf("key %q: invalid workspace %q", key.Name, id)
+			}
+		}
+
+		keys = append(keys, &authEntities.APIKey{
+			Name:       key.Name,
+			Hash:       strings.ToLower(key.Hash),
+			Scopes:     key.Scopes,
+			Roles:      key.Roles,
+			Workspaces: key.Workspaces,
+		})
+	}
+
+	return keys, nil
+}
+
+// jwtVerifier builds the verifier of the tokens issued by the gateway, from the static keys of the config and
+// its JWKS file.
+func jwtVerifier(cfg apiCfg.JWT, logger *zerolog.Logger) (*jwt.Verifier, error) {
+	keys := make([]jwt.Key, 0, len(cfg.Keys))
+
+	for _, key := range cfg.Keys {
+		switch {
+		case key.Secret != "" && key.PublicKeyFile != "":
+			return nil, fmt.Errorf("key %q: secret and publicKeyFile are exclusive", key.ID)
+		case key.Secret != "":
+			keys = append(keys, jwt.Key{ID: key.ID, Material: []byte(key.Secret)})
+		case key.PublicKeyFile != "":
+			data, err := os.ReadFile(key.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key.ID, err)
+			}
+
+			material, err := jwt.ParsePublicKeyPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key.ID, err)
+			}
+
+			keys = append(keys, jwt.Key{ID: key.ID, Material: material})
+		default:
+			return nil, fmt.Errorf("key %q: secret or publicKeyFile is required", key.ID)
+		}
+	}
+
+	staticKeys, err := jwt.NewStaticKeys(keys...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keys: %w", err)
+	}
+
+	keySets := jwt.KeySets{staticKeys}
+
+	if cfg.JWKSFile != "" {
+		jwks, err := jwt.NewJWKSFile(cfg.JWKSFile, cfg.JWKSReloadInterval, logger)
+		if err != nil {
+			return nil, fmt.Errorf("load jwks failed: %w", err)
+		}
+
+		keySets = append(keySets, jwks)
+	}
 
-	taskHTTP.RegisterTaskRoutes(httpRouter, taskUseCase)
+	return jwt.NewVerifier(
+		keySets,
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(cfg.Audience),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithRolesClaim(cfg.RolesClaim),
+		jwt.WithWorkspacesClaim(cfg.WorkspacesClaim),
+	), nil
 }
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"ggltask/database/migrations"
+	apiCfg "ggltask/internal/api/config"
+	authRepository "ggltask/internal/auth/domain/repository"
+	authFileRepo "ggltask/internal/auth/repository/file"
+	authMemoryRepo "ggltask/internal/auth/repository/memory"
+	authSQLRepo "ggltask/internal/auth/repository/sql"
+	"ggltask/internal/task/domain/repository"
+	fileRepo "ggltask/internal/task/repository/file"
+	memoryRepo "ggltask/internal/task/repository/memory"
+	sqlRepo "ggltask/internal/task/repository/sql"
+	"ggltask/pkg/migrate"
+
+	"github.com/rs/zerolog"
+)
+
+// CloseFunc releases the resources held by a repository. It is registered as a shutdown hook.
+type CloseFunc func(ctx context.Context) error
+
+func noopClose(_ context.Context) error {
+	return nil
+}
+
+// Repositories are the repositories of the task and auth domains, all backed by the same storage.
+type Repositories struct {
+	Task    repository.Repository
+	History repository.HistoryRepository
+	List    repository.ListRepository
+	Key     authRepository.KeyRepository
+}
+
+// NewRepositories builds the repositories selected by `storage.driver`.
+func NewRepositories(ctx context.Context, cfg apiCfg.Config, logger *zerolog.Logger) (*Repositories, CloseFunc, error) {
+	switch cfg.Storage.Driver {
+	case apiCfg.StorageDriverMemory, "":
+		return &Repositories{
+			Task:    memoryRepo.NewTaskRepository(),
+			History: memoryRepo.NewHistoryRepository(),
+			List:    memoryRepo.NewListRepository(),
+			Key:     authMemoryRepo.NewKeyRepository(),
+		}, noopClose, nil
+	case apiCfg.StorageDriverFile:
+		return newFileRepositories(cfg.Storage.File, logger)
+	case apiCfg.StorageDriverSQL:
+		return newSQLRepositories(ctx, cfg.DB)
+	default:
+		return nil, nil, fmt.Errorf("unsupported storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+func newFileRepositories(cfg apiCfg.FileStorage, logger *zerolog.Logger) (*Repositories, CloseFunc, error) {
+	taskRepo, err := fileRepo.NewTaskRepository(
+		cfg.Dir,
+		fileRepo.WithSnapshotInterval(cfg.SnapshotInterval),
+		fileRepo.WithSyncWrites(cfg.SyncWrites),
+		fileRepo.WithLogger(logger),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fileRepo.NewTaskRepository error: %w", err)
+	}
+
+	historyRepo, err := fileRepo.NewHistoryRepository(cfg.Dir, cfg.SyncWrites)
+	if err != nil {
+		_ = taskRepo.Close(context.Background())
+
+		return nil, nil, fmt.Errorf("fileRepo.NewHistoryRepository error: %w", err)
+	}
+
+	closeFn := func(ctx context.Context) error {
+		return errors.Join(taskRepo.Close(ctx), historyRepo.Close(ctx))
+	}
+
+	listRepo, err := fileRepo.NewListRepository(cfg.Dir)
+	if err != nil {
+		_ = closeFn(context.Background())
+
+		return nil, nil, fmt.Errorf("fileRepo.NewListRepository error: %w", err)
+	}
+
+	keyRepo, err := authFileRepo.NewKeyRepository(cfg.Dir)
+	if err != nil {
+		_ = closeFn(context.Background())
+
+		return nil, nil, fmt.Errorf("authFileRepo.NewKeyRepository error: %w", err)
+	}
+
+	return &Repositories{Task: taskRepo, History: historyRepo, List: listRepo, Key: keyRepo}, closeFn, nil
+}
+
+func newSQLRepositories(ctx context.Context, cfg apiCfg.Database) (*Repositories, CloseFunc, error) {
+	db, dialect, err := OpenDatabase(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeFn := func(_ context.Context) error {
+		if err := db.Close(); err != nil {
+			return fmt.Errorf("db.Close error: %w", err)
+		}
+
+		return nil
+	}
+
+	migrator, err := NewMigrator(db, dialect)
+	if err != nil {
+		_ = db.Close()
+
+		return nil, nil, err
+	}
+
+	// refuse to serve against a schema older than the code
+	if err := migrator.CheckCurrent(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, nil, fmt.Errorf("migrator.CheckCurrent error: %w", err)
+	}
+
+	return &Repositories{
+		Task:    sqlRepo.NewTaskRepository(db, dialect),
+		History: sqlRepo.NewHistoryRepository(db, dialect),
+		List:    sqlRepo.NewListRepository(db, dialect),
+		Key:     authSQLRepo.NewKeyRepository(db, dialect),
+	}, closeFn, nil
+}
+
+// OpenDatabase opens the database configured under `custom.db`.
+func OpenDatabase(ctx context.Context, cfg apiCfg.Database) (*sql.DB, sqlRepo.Dialect, error) {
+	dialect, err := sqlRepo.ParseDialect(cfg.Dialect)
+	if err != nil {
+		return nil, "", fmt.Errorf("sqlRepo.ParseDialect error: %w", err)
+	}
+
+	db, err := sqlRepo.OpenDB(ctx, sqlRepo.Config{
+		Dialect:      dialect,
+		Host:         cfg.Host,
+		Port:         cfg.Port,
+		User:         cfg.User,
+		Password:     cfg.Password,
+		Database:     cfg.Database,
+		SSLMode:      cfg.SSLMode,
+		MaxConns:     int(cfg.MaxConns),
+		MaxIdleConns: int(cfg.MaxIdleConns),
+		MaxLifeTime:  cfg.MaxLifeTime,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("sqlRepo.OpenDB error: %w", err)
+	}
+
+	return db, dialect, nil
+}
+
+// NewMigrator returns a migrator loaded with the embedded migrations of the dialect.
+func NewMigrator(db *sql.DB, dialect sqlRepo.Dialect) (*migrate.Migrator, error) {
+	fsys, err := migrations.ForDialect(string(dialect))
+	if err != nil {
+		return nil, fmt.Errorf("migrations.ForDialect error: %w", err)
+	}
+
+	migrator, err := migrate.New(db, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("migrate.New error: %w", err)
+	}
+
+	return migrator, nil
+}
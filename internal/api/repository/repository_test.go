@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiCfg "ggltask/internal/api/config"
+	authFileRepo "ggltask/internal/auth/repository/file"
+	authMemoryRepo "ggltask/internal/auth/repository/memory"
+	fileRepo "ggltask/internal/task/repository/file"
+	memoryRepo "ggltask/internal/task/repository/memory"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRepositories(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     apiCfg.Config
+		want    any
+		history any
+		list    any
+		key     any
+		wantErr bool
+	}{
+		{
+			name:    "memory",
+			cfg:     apiCfg.Config{Storage: apiCfg.Storage{Driver: apiCfg.StorageDriverMemory}},
+			want:    &memoryRepo.TaskRepository{},
+			history: &memoryRepo.HistoryRepository{},
+			list:    &memoryRepo.ListRepository{},
+			key:     &authMemoryRepo.KeyRepository{},
+		},
+		{
+			name: "file",
+			cfg: apiCfg.Config{Storage: apiCfg.Storage{
+				Driver: apiCfg.StorageDriverFile,
+				File:   apiCfg.FileStorage{Dir: t.TempDir(), SnapshotInterval: time.Minute},
+			}},
+			want:    &fileRepo.TaskRepository{},
+			history: &fileRepo.HistoryRepository{},
+			list:    &fileRepo.ListRepository{},
+			key:     &authFileRepo.KeyRepository{},
+		},
+		{
+			name:    "unsupported driver",
+			cfg:     apiCfg.Config{Storage: apiCfg.Storage{Driver: "mongo"}},
+			wantErr: true,
+		},
+		{
+			name: "sql with unsupported dialect",
+			cfg: apiCfg.Config{
+				Storage: apiCfg.Storage{Driver: apiCfg.StorageDriverSQL},
+				DB:      apiCfg.Database{Dialect: "sqlite"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := zerolog.Nop()
+			repos, closeFn, err := NewRepositories(context.Background(), tt.cfg, &logger)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.IsType(t, tt.want, repos.Task)
+			assert.IsType(t, tt.history, repos.History)
+			assert.IsType(t, tt.list, repos.List)
+			assert.IsType(t, tt.key, repos.Key)
+			assert.NoError(t, closeFn(context.Background()))
+		})
+	}
+}
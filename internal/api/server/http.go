@@ -13,8 +13,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-// SetupHTTPServer is setting up the HTTP server.
-func (s *Server) SetupHTTPServer() {
+// SetupHTTPServer is setting up the HTTP server. Only the trusted proxies of the config may name the client IP,
+// as the rate limits count clients by it.
+func (s *Server) SetupHTTPServer() error {
 	ginMode := gin.ReleaseMode
 	if s.cfg.Debug {
 		ginMode = gin.DebugMode
@@ -23,6 +24,10 @@ func (s *Server) SetupHTTPServer() {
 	gin.SetMode(ginMode)
 	s.httpRouter = gin.New()
 
+	if err := s.httpRouter.SetTrustedProxies(s.cfg.HTTP.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
 	// swagger
 	docs.SwaggerInfo.BasePath = "/"
 	s.httpRouter.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -35,6 +40,8 @@ func (s *Server) SetupHTTPServer() {
 		IdleTimeout:       s.cfg.HTTP.Timeouts.IdleTimeout,
 		Handler:           s.httpRouter,
 	}
+
+	return nil
 }
 
 func (s *Server) HTTPRouter() *gin.Engine {
@@ -0,0 +1,74 @@
+package server
+
+import (
+	apiCfg "ggltask/internal/api/config"
+	"ggltask/pkg/config"
+	"ggltask/pkg/ratelimit"
+	"ggltask/pkg/transport/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_SetupHTTPServer_TrustedProxies(t *testing.T) {
+	// not parallel: setting up a server sets the gin mode of the process
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		wantCodes      []int
+	}{
+		{
+			name:      "no trusted proxy counts by remote address",
+			wantCodes: []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests},
+		},
+		{
+			name:           "a trusted proxy names the client",
+			trustedProxies: []string{"10.0.0.0/8"},
+			wantCodes:      []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config[apiCfg.Config]{}
+			cfg.HTTP.TrustedProxies = tt.trustedProxies
+			logger := zerolog.Nop()
+
+			srv := NewServer(cfg, &logger)
+			require.NoError(t, srv.SetupHTTPServer())
+
+			limiter := ratelimit.NewLimiter(time.Minute)
+			srv.HTTPRouter().Use(middleware.GinAuthRateLimit(limiter, ratelimit.Limit{Requests: 2, Period: time.Minute}))
+			srv.HTTPRouter().GET("/ping", func(c *gin.Context) {
+				c.AbortWithStatus(http.StatusUnauthorized)
+			})
+
+			// every request claims another client in X-Forwarded-For
+			for i, want := range tt.wantCodes {
+				req := httptest.NewRequest("GET", "/ping", nil)
+				req.RemoteAddr = "10.0.0.1:1234"
+				req.Header.Set("X-Forwarded-For", "192.0.2."+strconv.Itoa(i+1))
+
+				w := httptest.NewRecorder()
+				srv.HTTPRouter().ServeHTTP(w, req)
+				assert.Equal(t, want, w.Code)
+			}
+		})
+	}
+}
+
+func TestServer_SetupHTTPServer_InvalidTrustedProxy(t *testing.T) {
+	// not parallel: setting up a server sets the gin mode of the process
+	cfg := &config.Config[apiCfg.Config]{}
+	cfg.HTTP.TrustedProxies = []string{"not-an-ip"}
+	logger := zerolog.Nop()
+
+	assert.Error(t, NewServer(cfg, &logger).SetupHTTPServer())
+}
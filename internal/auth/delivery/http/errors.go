@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"ggltask/internal/auth/domain/usecase"
+	"ggltask/pkg/requestid"
+	"net/http"
+)
+
+type ErrorResponse struct {
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+	// RequestID is the id of the request, for clients to quote when reporting an error.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// UseCaseErrorToErrorResp is a helper function that converts a usecase error to an error response.
+// It returns the HTTP status code and the error response, carrying the request id of ctx.
+func UseCaseErrorToErrorResp(ctx context.Context, err error) (int, ErrorResponse) {
+	var usecaseErr usecase.UseCaseError
+	if !errors.As(err, &usecaseErr) {
+		return http.StatusInternalServerError, ErrorResponse{
+			ErrorCode:    "INTERNAL_SERVER_ERROR",
+			ErrorMessage: "Internal Server Error",
+			RequestID:    requestid.FromContext(ctx),
+		}
+	}
+
+	return usecaseErr.HTTPStatusCode(), ErrorResponse{
+		ErrorCode:    usecaseErr.ErrorCode(),
+		ErrorMessage: usecaseErr.ErrorMsg(),
+		RequestID:    requestid.FromContext(ctx),
+	}
+}
+
+func InvalidRequestError(ctx context.Context) ErrorResponse {
+	return ErrorResponse{
+		ErrorCode:    "INVALID_REQUEST",
+		ErrorMessage: "Invalid Request",
+		RequestID:    requestid.FromContext(ctx),
+	}
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"fmt"
+	"ggltask/internal/auth/domain/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+type KeyHandler struct {
+	keyUsecase usecase.KeyUseCase
+}
+
+func NewKeyHandler(keyUsecase usecase.KeyUseCase) *KeyHandler {
+	return &KeyHandler{keyUsecase: keyUsecase}
+}
+
+// @Summary Create API key
+// @Description Issue a new API key with the given scopes. The key is in the response only: it is stored hashed. Its scopes, roles and workspaces must all be granted to the principal creating it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateKeyRequest true "Create key request"
+// @Success 200 {object} CreateKeyResponse "Create key response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the keys:admin scope, or granting more than the principal holds"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/admin/keys [post]
+func (h *KeyHandler) CreateKey(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	result, err := h.keyUsecase.CreateKey(ctx, usecase.CreateKeyParams{
+		Name:       req.Name,
+		Scopes:     req.Scopes,
+		Roles:      req.Roles,
+		Workspaces: req.Workspaces,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", req),
+			"error":   err,
+		}).Msg("key create error")
+
+		c.JSON(UseCaseErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateKeyResponse{
+		Key:    result.Key,
+		Secret: result.Secret,
+	})
+}
+
+// @Summary List API keys
+// @Description List the API keys by page, ordered by id, revoked ones included. The keys from the config are not listed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request query ListKeysRequest true "List keys request"
+// @Success 200 {object} ListKeysResponse "List keys response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the keys:admin scope"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/admin/keys [get]
+func (h *KeyHandler) ListKeys(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req ListKeysRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	result, err := h.keyUsecase.ListKeys(ctx, usecase.ListKeysParams{
+		PageIndex: req.PageIndex,
+		PageSize:  req.PageSize,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", req),
+			"error":   err,
+		}).Msg("key list error")
+
+		c.JSON(UseCaseErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, ListKeysResponse{
+		Keys:  result.Keys,
+		Total: result.Total,
+	})
+}
+
+// @Summary Revoke API key
+// @Description Revoke an API key: requests sending it are rejected from then on. Revoking a revoked key changes nothing.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Key ID"
+// @Success 200 {object} RevokeKeyResponse "Revoke key response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the keys:admin scope"
+// @Failure 404 {object} ErrorResponse "not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/admin/keys/{id} [delete]
+func (h *KeyHandler) RevokeKey(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	key, err := h.keyUsecase.RevokeKey(ctx, uint(idUint))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": id,
+			"error":   err,
+		}).Msg("key revoke error")
+
+		c.JSON(UseCaseErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, RevokeKeyResponse{
+		Key: key,
+	})
+}
@@ -0,0 +1,212 @@
+package http
+
+import (
+	"errors"
+	"flag"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/usecase"
+	"ggltask/internal/auth/mock/usecasemock"
+	"ggltask/pkg/auth"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "use leak detector")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+
+		return
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	os.Exit(m.Run())
+}
+
+func TestKeyHandler(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	key := &entities.APIKey{
+		ID:        2,
+		Name:      "ci",
+		Prefix:    "ggl_0123abcd",
+		Hash:      "secret hash",
+		Scopes:    []string{auth.ScopeTasksRead},
+		CreatedAt: createdAt,
+	}
+	keyJSON := `{"id":2,"name":"ci","prefix":"ggl_0123abcd","scopes":["tasks:read"],"created_at":"2030-01-02T09:00:00Z"}`
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		scopes         []string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.KeyUseCase
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:   "create",
+			method: "POST",
+			url:    "/api/v1/admin/keys",
+			body:   `{"name":"ci","scopes":["tasks:read"]}`,
+			scopes: []string{auth.ScopeKeysAdmin},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				mockUsecase := usecasemock.NewMockKeyUseCase(ctrl)
+				mockUsecase.EXPECT().CreateKey(gomock.Any(), usecase.CreateKeyParams{Name: "ci", Scopes: []string{auth.ScopeTasksRead}}).
+					Return(&usecase.CreateKeyResult{Key: key, Secret: "ggl_0123abcdef"}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"key":` + keyJSON + `,"secret":"ggl_0123abcdef"}`,
+		},
+		{
+			name:   "create without scopes",
+			method: "POST",
+			url:    "/api/v1/admin/keys",
+			body:   `{"name":"ci","scopes":[]}`,
+			scopes: []string{auth.ScopeKeysAdmin},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				return usecasemock.NewMockKeyUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+		{
+			name:   "create with an unknown scope",
+			method: "POST",
+			url:    "/api/v1/admin/keys",
+			body:   `{"name":"ci","scopes":["tasks:delete"]}`,
+			scopes: []string{auth.ScopeKeysAdmin},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				mockUsecase := usecasemock.NewMockKeyUseCase(ctrl)
+				mockUsecase.EXPECT().CreateKey(gomock.Any(), gomock.Any()).
+					Return(nil, usecase.InvalidArgumentError{Argument: "scopes", Reason: `unknown scope "tasks:delete"`})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_ARGUMENT","error_message":"invalid scopes: unknown scope \"tasks:delete\""}`,
+		},
+		{
+			name:   "list",
+			method: "GET",
+			url:    "/api/v1/admin/keys?page_index=1&page_size=10",
+			scopes: []string{auth.ScopeAll},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				mockUsecase := usecasemock.NewMockKeyUseCase(ctrl)
+				mockUsecase.EXPECT().ListKeys(gomock.Any(), usecase.ListKeysParams{PageIndex: 1, PageSize: 10}).
+					Return(&usecase.ListKeysResult{Keys: []*entities.APIKey{key}, Total: 1}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"keys":[` + keyJSON + `],"total":1}`,
+		},
+		{
+			name:   "list error",
+			method: "GET",
+			url:    "/api/v1/admin/keys",
+			scopes: []string{auth.ScopeKeysAdmin},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				mockUsecase := usecasemock.NewMockKeyUseCase(ctrl)
+				mockUsecase.EXPECT().ListKeys(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"error_code":"INTERNAL_SERVER_ERROR","error_message":"Internal Server Error"}`,
+		},
+		{
+			name:   "revoke",
+			method: "DELETE",
+			url:    "/api/v1/admin/keys/2",
+			scopes: []string{auth.ScopeKeysAdmin},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				revoked := *key
+				revoked.RevokedAt = &createdAt
+
+				mockUsecase := usecasemock.NewMockKeyUseCase(ctrl)
+				mockUsecase.EXPECT().RevokeKey(gomock.Any(), uint(2)).Return(&revoked, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"key":` + strings.TrimSuffix(keyJSON, "}") + `,"revoked_at":"2030-01-02T09:00:00Z"}}`,
+		},
+		{
+			name:   "revoke unknown key",
+			method: "DELETE",
+			url:    "/api/v1/admin/keys/9",
+			scopes: []string{auth.ScopeKeysAdmin},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				mockUsecase := usecasemock.NewMockKeyUseCase(ctrl)
+				mockUsecase.EXPECT().RevokeKey(gomock.Any(), uint(9)).Return(nil, usecase.NotFoundError{Resource: "api key", ID: uint(9)})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error_code":"NOT_FOUND","error_message":"api key 9 not found"}`,
+		},
+		{
+			name:   "revoke with an invalid id",
+			method: "DELETE",
+			url:    "/api/v1/admin/keys/abc",
+			scopes: []string{auth.ScopeKeysAdmin},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				return usecasemock.NewMockKeyUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+		{
+			name:   "without the admin scope",
+			method: "GET",
+			url:    "/api/v1/admin/keys",
+			scopes: []string{auth.ScopeTasksRead, auth.ScopeTasksWrite},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.KeyUseCase {
+				return usecasemock.NewMockKeyUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"error_code":"FORBIDDEN","error_message":"missing scope keys:admin"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.Default()
+			router.Use(func(c *gin.Context) {
+				ctx := auth.WithPrincipal(c.Request.Context(), auth.Principal{Subject: "admin", Scopes: tt.scopes})
+				c.Request = c.Request.WithContext(ctx)
+			})
+			RegisterKeyRoutes(router, tt.getUsecaseMock(gomock.NewController(t)))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package http
+
+type CreateKeyRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+	// Scopes are what the key grants, among tasks:read, tasks:write, keys:admin and * for all of them.
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+	// Roles are the roles of the key in the task policy, such as viewer, editor or admin. Without roles, the key
+	// has the default role of the policy.
+	Roles []string `json:"roles"`
+	// Workspaces are the workspaces the key may work in, or * for all of them. Without workspaces, the key works
+	// in the default workspace only.
+	Workspaces []string `json:"workspaces"`
+}
+
+type ListKeysRequest struct {
+	PageIndex int `form:"page_index,default=1" binding:"required,gte=1"`
+	PageSize  int `form:"page_size,default=10" binding:"required,gte=1,lte=100"`
+}
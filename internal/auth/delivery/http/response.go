@@ -0,0 +1,18 @@
+package http
+
+import "ggltask/internal/auth/domain/entities"
+
+type CreateKeyResponse struct {
+	Key *entities.APIKey `json:"key"`
+	// Secret is the key to send as `Authorization: Bearer <secret>`. It is not stored and cannot be read again.
+	Secret string `json:"secret"`
+}
+
+type ListKeysResponse struct {
+	Keys  []*entities.APIKey `json:"keys"`
+	Total int                `json:"total"`
+}
+
+type RevokeKeyResponse struct {
+	Key *entities.APIKey `json:"key"`
+}
@@ -0,0 +1,19 @@
+package http
+
+import (
+	"ggltask/internal/auth/domain/usecase"
+	"ggltask/pkg/auth"
+	"ggltask/pkg/transport/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterKeyRoutes registers the admin routes managing API keys, which all require the keys:admin scope.
+func RegisterKeyRoutes(router gin.IRouter, keyUsecase usecase.KeyUseCase) {
+	keyHandler := NewKeyHandler(keyUsecase)
+
+	admin := router.Group("/api/v1/admin", middleware.GinRequireScope(auth.ScopeKeysAdmin))
+	admin.POST("/keys", keyHandler.CreateKey)
+	admin.GET("/keys", keyHandler.ListKeys)
+	admin.DELETE("/keys/:id", keyHandler.RevokeKey)
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"ggltask/pkg/auth"
+	"strconv"
+	"time"
+)
+
+// APIKey is a key issued to a client. Only the hash of the key is stored: the key itself is shown
+// once, when it is created.
+type APIKey struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the start of the key, enough for its owner to tell it apart from their other keys.
+	Prefix string   `json:"prefix"`
+	Hash   string   `json:"-"`
+	Scopes []string `json:"scopes"`
+	// Roles are the roles the key has in the task policy. A key without roles has the default role of the policy.
+	Roles []string `json:"roles,omitempty"`
+	// Workspaces are the workspaces the key may work in, `*` for all. A key without workspaces works in the
+	// default workspace only.
+	Workspaces []string   `json:"workspaces,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Revoked reports whether the key has been revoked.
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Subject is the subject a request authenticated with the key acts as: `apikey:<id>` for a stored key, and
+// `apikey:config:<name>` for a key from the config, which has no id. Names are neither unique nor stable,
+// so a stored key is never known by its name.
+func (k *APIKey) Subject() string {
+	if k.ID == 0 {
+		return auth.APIKeySubjectPrefix + "config:" + k.Name
+	}
+
+	return auth.APIKeySubjectPrefix + strconv.FormatUint(uint64(k.ID), 10)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"ggltask/internal/auth/domain/entities"
+	"time"
+)
+
+// KeyRepository stores API keys. Keys belong to no workspace: a key is found by the hash of the
+// key, and revoking one keeps it, so the listing shows when it stopped working.
+//
+//go:generate mockgen -source=./repository.go -destination=../../mock/repositorymock/repository_mock.go -package=repositorymock
+type KeyRepository interface {
+	CreateKey(ctx context.Context, key *entities.APIKey) (*entities.APIKey, error)
+	GetKeyByHash(ctx context.Context, hash string) (*entities.APIKey, error)
+	// ListKeysByPage is listing the keys by page, ordered by id.
+	ListKeysByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.APIKey, int, error)
+	// RevokeKey sets the revocation time of a key, unless it is already revoked.
+	RevokeKey(ctx context.Context, id uint, revokedAt time.Time) (*entities.APIKey, error)
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+)
+
+//nolint:revive
+type UseCaseError interface {
+	ErrorCode() string
+	ErrorMsg() string
+	Error() string
+	HTTPStatusCode() int
+}
+
+type NotFoundError struct {
+	Resource string
+	ID       interface{}
+}
+
+func (e NotFoundError) ErrorCode() string {
+	return "NOT_FOUND"
+}
+
+func (e NotFoundError) ErrorMsg() string {
+	return fmt.Sprintf("%s %v not found", e.Resource, e.ID)
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("%s %v not found", e.Resource, e.ID)
+}
+
+func (e NotFoundError) HTTPStatusCode() int {
+	return http.StatusNotFound
+}
+
+type InvalidArgumentError struct {
+	Argument string
+	Reason   string
+}
+
+func (e InvalidArgumentError) ErrorCode() string {
+	return "INVALID_ARGUMENT"
+}
+
+func (e InvalidArgumentError) ErrorMsg() string {
+	return fmt.Sprintf("invalid %s: %s", e.Argument, e.Reason)
+}
+
+func (e InvalidArgumentError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Argument, e.Reason)
+}
+
+func (e InvalidArgumentError) HTTPStatusCode() int {
+	return http.StatusBadRequest
+}
+
+// ForbiddenError rejects an action the principal of the request may not take.
+type ForbiddenError struct {
+	Action   string
+	Resource string
+	Reason   string
+}
+
+func (e ForbiddenError) ErrorCode() string {
+	return "FORBIDDEN"
+}
+
+func (e ForbiddenError) ErrorMsg() string {
+	return fmt.Sprintf("not allowed to %s %s: %s", e.Action, e.Resource, e.Reason)
+}
+
+func (e ForbiddenError) Error() string {
+	return e.ErrorMsg()
+}
+
+func (e ForbiddenError) HTTPStatusCode() int {
+	return http.StatusForbidden
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/pkg/auth"
+)
+
+//go:generate mockgen -source=./usecase.go -destination=../../mock/usecasemock/usecase_mock.go -package=usecasemock
+type KeyUseCase interface {
+	// AuthenticateAPIKey returns the principal of a live key, or auth.ErrInvalidCredentials.
+	AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error)
+	CreateKey(ctx context.Context, param CreateKeyParams) (*CreateKeyResult, error)
+	ListKeys(ctx context.Context, param ListKeysParams) (*ListKeysResult, error)
+	RevokeKey(ctx context.Context, id uint) (*entities.APIKey, error)
+}
+
+type CreateKeyParams struct {
+	Name       string
+	Scopes     []string
+	Roles      []string
+	Workspaces []string
+}
+
+type CreateKeyResult struct {
+	Key *entities.APIKey
+	// Secret is the key itself. It is not stored, so this is the only time it can be read.
+	Secret string
+}
+
+type ListKeysParams struct {
+	PageIndex int
+	PageSize  int
+}
+
+type ListKeysResult struct {
+	Keys  []*entities.APIKey
+	Total int
+}
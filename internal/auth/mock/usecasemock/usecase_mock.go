@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./usecase.go
+
+// Package usecasemock is a generated GoMock package.
+package usecasemock
+
+import (
+	context "context"
+	entities "ggltask/internal/auth/domain/entities"
+	usecase "ggltask/internal/auth/domain/usecase"
+	auth "ggltask/pkg/auth"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockKeyUseCase is a mock of KeyUseCase interface.
+type MockKeyUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyUseCaseMockRecorder
+}
+
+// MockKeyUseCaseMockRecorder is the mock recorder for MockKeyUseCase.
+type MockKeyUseCaseMockRecorder struct {
+	mock *MockKeyUseCase
+}
+
+// NewMockKeyUseCase creates a new mock instance.
+func NewMockKeyUseCase(ctrl *gomock.Controller) *MockKeyUseCase {
+	mock := &MockKeyUseCase{ctrl: ctrl}
+	mock.recorder = &MockKeyUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyUseCase) EXPECT() *MockKeyUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockKeyUseCase) AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, key)
+	ret0, _ := ret[0].(auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockKeyUseCaseMockRecorder) AuthenticateAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockKeyUseCase)(nil).AuthenticateAPIKey), ctx, key)
+}
+
+// CreateKey mocks base method.
+func (m *MockKeyUseCase) CreateKey(ctx context.Context, param usecase.CreateKeyParams) (*usecase.CreateKeyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKey", ctx, param)
+	ret0, _ := ret[0].(*usecase.CreateKeyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateKey indicates an expected call of CreateKey.
+func (mr *MockKeyUseCaseMockRecorder) CreateKey(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKey", reflect.TypeOf((*MockKeyUseCase)(nil).CreateKey), ctx, param)
+}
+
+// ListKeys mocks base method.
+func (m *MockKeyUseCase) ListKeys(ctx context.Context, param usecase.ListKeysParams) (*usecase.ListKeysResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKeys", ctx, param)
+	ret0, _ := ret[0].(*usecase.ListKeysResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKeys indicates an expected call of ListKeys.
+func (mr *MockKeyUseCaseMockRecorder) ListKeys(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeys", reflect.TypeOf((*MockKeyUseCase)(nil).ListKeys), ctx, param)
+}
+
+// RevokeKey mocks base method.
+func (m *MockKeyUseCase) RevokeKey(ctx context.Context, id uint) (*entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeKey", ctx, id)
+	ret0, _ := ret[0].(*entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeKey indicates an expected call of RevokeKey.
+func (mr *MockKeyUseCaseMockRecorder) RevokeKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeKey", reflect.TypeOf((*MockKeyUseCase)(nil).RevokeKey), ctx, id)
+}
@@ -0,0 +1,141 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/repository"
+	"ggltask/internal/auth/repository/memory"
+	"ggltask/pkg/atomicfile"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var _ repository.KeyRepository = (*KeyRepository)(nil)
+
+const keysFileName = "keys.json"
+
+type keysFile struct {
+	LastID uint        `json:"last_id"`
+	Keys   []keyRecord `json:"keys"`
+}
+
+// keyRecord is the stored form of a key: entities.APIKey leaves its hash out of JSON, for the API.
+type keyRecord struct {
+	*entities.APIKey
+	Hash string `json:"hash"`
+}
+
+// KeyRepository is a repository for API keys.
+// It serves reads from a memory.KeyRepository and rewrites the whole file on every change:
+// keys are few and rarely change, so they need no log.
+type KeyRepository struct {
+	mem *memory.KeyRepository
+
+	// mu serializes writes so the file holds the last change.
+	mu  sync.Mutex
+	dir string
+}
+
+// NewKeyRepository opens the keys stored in dir.
+func NewKeyRepository(dir string) (*KeyRepository, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll error: %w", err)
+	}
+
+	mem := memory.NewKeyRepository()
+
+	data, err := os.ReadFile(filepath.Join(dir, keysFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("os.ReadFile error: %w", err)
+	}
+
+	if err == nil {
+		var f keysFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal error: %w", err)
+		}
+
+		keys := make([]*entities.APIKey, 0, len(f.Keys))
+		for _, record := range f.Keys {
+			if record.APIKey == nil {
+				return nil, fmt.Errorf("empty key in %s", keysFileName)
+			}
+
+			record.APIKey.Hash = record.Hash
+			keys = append(keys, record.APIKey)
+		}
+
+		mem.Restore(memory.KeySnapshot{Keys: keys, LastID: f.LastID})
+	}
+
+	return &KeyRepository{
+		mem: mem,
+		dir: dir,
+	}, nil
+}
+
+// CreateKey is storing a new key.
+func (r *KeyRepository) CreateKey(ctx context.Context, key *entities.APIKey) (*entities.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created, err := r.mem.CreateKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("mem.CreateKey error: %w", err)
+	}
+
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetKeyByHash is getting a key by the hash of the key.
+func (r *KeyRepository) GetKeyByHash(ctx context.Context, hash string) (*entities.APIKey, error) {
+	return r.mem.GetKeyByHash(ctx, hash) //nolint:wrapcheck
+}
+
+// ListKeysByPage is listing the keys by page, ordered by id.
+func (r *KeyRepository) ListKeysByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.APIKey, int, error) {
+	return r.mem.ListKeysByPage(ctx, pageIndex, pageSize) //nolint:wrapcheck
+}
+
+// RevokeKey is setting the revocation time of a key, unless it is already revoked.
+func (r *KeyRepository) RevokeKey(ctx context.Context, id uint, revokedAt time.Time) (*entities.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, err := r.mem.RevokeKey(ctx, id, revokedAt)
+	if err != nil {
+		return nil, fmt.Errorf("mem.RevokeKey error: %w", err)
+	}
+
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// save atomically rewrites the file with the current keys. Callers must hold r.mu.
+func (r *KeyRepository) save() error {
+	snapshot := r.mem.Snapshot()
+
+	f := keysFile{LastID: snapshot.LastID, Keys: make([]keyRecord, 0, len(snapshot.Keys))}
+	for _, key := range snapshot.Keys {
+		f.Keys = append(f.Keys, keyRecord{APIKey: key, Hash: key.Hash})
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("json.Marshal error: %w", err)
+	}
+
+	return atomicfile.Write(r.dir, keysFileName, data) //nolint:wrapcheck
+}
@@ -0,0 +1,72 @@
+package file
+
+import (
+	"context"
+	"flag"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/repository"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "use leak detector")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestKeyRepository_ReloadAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r, err := NewKeyRepository(dir)
+	require.NoError(t, err)
+
+	ci, err := r.CreateKey(ctx, &entities.APIKey{Name: "ci", Prefix: "ggl_0123abcd", Hash: "ci hash", Scopes: []string{"tasks:read"}})
+	require.NoError(t, err)
+	cron, err := r.CreateKey(ctx, &entities.APIKey{Name: "cron", Hash: "cron hash", Scopes: []string{"*"}})
+	require.NoError(t, err)
+	_, err = r.RevokeKey(ctx, cron.ID, time.Now())
+	require.NoError(t, err)
+
+	reopened, err := NewKeyRepository(dir)
+	require.NoError(t, err)
+
+	got, err := reopened.GetKeyByHash(ctx, "ci hash")
+	require.NoError(t, err)
+	assert.Equal(t, ci.ID, got.ID)
+	assert.Equal(t, "ggl_0123abcd", got.Prefix)
+	assert.Equal(t, []string{"tasks:read"}, got.Scopes)
+	assert.False(t, got.Revoked())
+
+	got, err = reopened.GetKeyByHash(ctx, "cron hash")
+	require.NoError(t, err)
+	assert.True(t, got.Revoked())
+
+	_, err = reopened.GetKeyByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+
+	created, err := reopened.CreateKey(ctx, &entities.APIKey{Name: "backup", Hash: "backup hash"})
+	require.NoError(t, err)
+	assert.Equal(t, cron.ID+1, created.ID)
+
+	// the file holds hashes, never keys
+	data, err := os.ReadFile(filepath.Join(dir, keysFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"hash":"ci hash"`)
+}
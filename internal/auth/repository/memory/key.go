@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ repository.KeyRepository = (*KeyRepository)(nil)
+
+// KeyRepository is a repository for API keys.
+// It is a memory repository that keeps the keys by id, with an index by hash.
+type KeyRepository struct {
+	mu     sync.RWMutex
+	keys   map[uint]*entities.APIKey
+	byHash map[string]*entities.APIKey
+	lastID uint
+}
+
+func NewKeyRepository() *KeyRepository {
+	return &KeyRepository{
+		keys:   make(map[uint]*entities.APIKey),
+		byHash: make(map[string]*entities.APIKey),
+	}
+}
+
+// CreateKey is storing a new key.
+func (r *KeyRepository) CreateKey(_ context.Context, key *entities.APIKey) (*entities.APIKey, error) {
+	if key.Name == "" || key.Hash == "" {
+		return nil, repository.ErrInvalidData
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byHash[key.Hash]; ok {
+		return nil, repository.ErrInvalidData
+	}
+
+	r.lastID++
+	key.ID = r.lastID
+	key.CreatedAt = time.Now()
+	r.keys[key.ID] = key
+	r.byHash[key.Hash] = key
+
+	return key, nil
+}
+
+// GetKeyByHash is getting a key by the hash of the key.
+func (r *KeyRepository) GetKeyByHash(_ context.Context, hash string) (*entities.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.byHash[hash]
+	if !ok {
+		return nil, repository.ErrDataNotFound
+	}
+
+	return key, nil
+}
+
+// ListKeysByPage is listing the keys by page, ordered by id.
+func (r *KeyRepository) ListKeysByPage(_ context.Context, pageIndex, pageSize int) ([]*entities.APIKey, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	keys := r.Snapshot().Keys
+	total := len(keys)
+
+	start := min((pageIndex-1)*pageSize, total)
+	end := min(start+pageSize, total)
+
+	return keys[start:end], total, nil
+}
+
+// RevokeKey is setting the revocation time of a key, unless it is already revoked.
+func (r *KeyRepository) RevokeKey(_ context.Context, id uint, revokedAt time.Time) (*entities.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, repository.ErrDataNotFound
+	}
+
+	if !key.Revoked() {
+		key.RevokedAt = &revokedAt
+	}
+
+	return key, nil
+}
+
+// KeySnapshot is the content of the repository: the keys ordered by id and the last allocated id.
+type KeySnapshot struct {
+	Keys   []*entities.APIKey
+	LastID uint
+}
+
+// Snapshot is returning the keys ordered by id together with the last allocated id.
+func (r *KeyRepository) Snapshot() KeySnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*entities.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+
+	return KeySnapshot{Keys: keys, LastID: r.lastID}
+}
+
+// Restore is replacing the repository content, e.g. with keys loaded from disk.
+func (r *KeyRepository) Restore(snapshot KeySnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys = make(map[uint]*entities.APIKey, len(snapshot.Keys))
+	r.byHash = make(map[string]*entities.APIKey, len(snapshot.Keys))
+	r.lastID = snapshot.LastID
+
+	for _, key := range snapshot.Keys {
+		r.keys[key.ID] = key
+		r.byHash[key.Hash] = key
+		r.lastID = max(r.lastID, key.ID)
+	}
+}
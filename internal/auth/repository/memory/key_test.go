@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/repository"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "use leak detector")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestKeyRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewKeyRepository()
+
+	for _, name := range []string{"ci", "cron"} {
+		if _, err := r.CreateKey(ctx, &entities.APIKey{Name: name, Hash: name + " hash"}); err != nil {
+			t.Fatalf("CreateKey() error = %v", err)
+		}
+	}
+
+	if _, err := r.CreateKey(ctx, &entities.APIKey{Name: "copy", Hash: "ci hash"}); !errors.Is(err, repository.ErrInvalidData) {
+		t.Errorf("CreateKey() error = %v, want %v", err, repository.ErrInvalidData)
+	}
+
+	if _, err := r.CreateKey(ctx, &entities.APIKey{Name: "no hash"}); !errors.Is(err, repository.ErrInvalidData) {
+		t.Errorf("CreateKey() error = %v, want %v", err, repository.ErrInvalidData)
+	}
+
+	got, err := r.GetKeyByHash(ctx, "cron hash")
+	if err != nil {
+		t.Fatalf("GetKeyByHash() error = %v", err)
+	}
+
+	if got.ID != 2 || got.Name != "cron" {
+		t.Errorf("GetKeyByHash() = %+v, want key 2", got)
+	}
+
+	if _, err := r.GetKeyByHash(ctx, "unknown"); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("GetKeyByHash() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	revokedAt := time.Now()
+
+	if _, err := r.RevokeKey(ctx, 1, revokedAt); err != nil {
+		t.Fatalf("RevokeKey() error = %v", err)
+	}
+
+	// a second revocation keeps the first time
+	got, err = r.RevokeKey(ctx, 1, revokedAt.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("RevokeKey() error = %v", err)
+	}
+
+	if !got.RevokedAt.Equal(revokedAt) {
+		t.Errorf("RevokeKey() revoked_at = %v, want %v", got.RevokedAt, revokedAt)
+	}
+
+	if _, err := r.RevokeKey(ctx, 9, revokedAt); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("RevokeKey() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	keys, total, err := r.ListKeysByPage(ctx, 2, 1)
+	if err != nil {
+		t.Fatalf("ListKeysByPage() error = %v", err)
+	}
+
+	if total != 2 || len(keys) != 1 || keys[0].ID != 2 {
+		t.Errorf("ListKeysByPage() = %v, %d, want key 2 of 2", keys, total)
+	}
+
+	if _, _, err := r.ListKeysByPage(ctx, 0, 1); !errors.Is(err, repository.ErrInvalidData) {
+		t.Errorf("ListKeysByPage() error = %v, want %v", err, repository.ErrInvalidData)
+	}
+}
@@ -0,0 +1,178 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"errors"
+	"fmt"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/repository"
+	sqlRepo "ggltask/internal/task/repository/sql"
+	"strings"
+	"time"
+)
+
+var _ repository.KeyRepository = (*KeyRepository)(nil)
+
+const keyColumns = "id, name, prefix, hash, scopes, roles, workspaces, created_at, revoked_at"
+
+// KeyRepository is a repository for API keys.
+// It stores keys in the `api_keys` table, next to the tasks, with the scopes, the roles and the workspaces
+// separated by spaces.
+type KeyRepository struct {
+	db      *dbsql.DB
+	dialect sqlRepo.Dialect
+}
+
+func NewKeyRepository(db *dbsql.DB, dialect sqlRepo.Dialect) *KeyRepository {
+	return &KeyRepository{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// CreateKey is storing a new key.
+func (r *KeyRepository) CreateKey(ctx context.Context, key *entities.APIKey) (*entities.APIKey, error) {
+	if key.Name == "" || key.Hash == "" {
+		return nil, repository.ErrInvalidData
+	}
+
+	key.CreatedAt = time.Now().UTC()
+
+	query := "INSERT INTO api_keys (name, prefix, hash, scopes, roles, workspaces, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	args := []any{
+		key.Name, key.Prefix, key.Hash,
+		strings.Join(key.Scopes, " "), strings.Join(key.Roles, " "), strings.Join(key.Workspaces, " "),
+		key.CreatedAt,
+	}
+
+	if r.dialect == sqlRepo.DialectPostgres {
+		if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query+" RETURNING id"), args...).Scan(&key.ID); err != nil {
+			return nil, fmt.Errorf("insert api key error: %w", err)
+		}
+
+		return key, nil
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("insert api key error: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("result.LastInsertId error: %w", err)
+	}
+
+	key.ID = uint(id)
+
+	return key, nil
+}
+
+// GetKeyByHash is getting a key by the hash of the key.
+func (r *KeyRepository) GetKeyByHash(ctx context.Context, hash string) (*entities.APIKey, error) {
+	row := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT "+keyColumns+" FROM api_keys WHERE hash = ?"), hash)
+
+	key, err := scanKey(row)
+	if errors.Is(err, dbsql.ErrNoRows) {
+		return nil, repository.ErrDataNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("select api key error: %w", err)
+	}
+
+	return key, nil
+}
+
+// ListKeysByPage is listing the keys by page, ordered by id.
+func (r *KeyRepository) ListKeysByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.APIKey, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM api_keys").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count api keys error: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		r.dialect.Rebind("SELECT "+keyColumns+" FROM api_keys ORDER BY id LIMIT ? OFFSET ?"),
+		pageSize, (pageIndex-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("select api keys error: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*entities.APIKey, 0, pageSize)
+
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("rows.Scan error: %w", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows.Err error: %w", err)
+	}
+
+	return keys, total, nil
+}
+
+// RevokeKey is setting the revocation time of a key, unless it is already revoked.
+func (r *KeyRepository) RevokeKey(ctx context.Context, id uint, revokedAt time.Time) (*entities.APIKey, error) {
+	if _, err := r.db.ExecContext(
+		ctx,
+		r.dialect.Rebind("UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL"),
+		revokedAt.UTC(), id,
+	); err != nil {
+		return nil, fmt.Errorf("update api key error: %w", err)
+	}
+
+	row := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT "+keyColumns+" FROM api_keys WHERE id = ?"), id)
+
+	key, err := scanKey(row)
+	if errors.Is(err, dbsql.ErrNoRows) {
+		return nil, repository.ErrDataNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("select api key error: %w", err)
+	}
+
+	return key, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanKey(row scanner) (*entities.APIKey, error) {
+	var (
+		key        entities.APIKey
+		scopes     string
+		roles      string
+		workspaces string
+		revokedAt  dbsql.NullTime
+	)
+
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &roles, &workspaces, &key.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	key.Scopes = strings.Fields(scopes)
+	key.Roles = strings.Fields(roles)
+	key.Workspaces = strings.Fields(workspaces)
+
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
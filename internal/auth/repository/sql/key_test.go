@@ -0,0 +1,186 @@
+package sql
+
+import (
+	"context"
+	"flag"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/repository"
+	sqlRepo "ggltask/internal/task/repository/sql"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "use leak detector")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+var keyRowColumns = []string{"id", "name", "prefix", "hash", "scopes", "roles", "workspaces", "created_at", "revoked_at"}
+
+func newMockKeyRepository(t *testing.T, dialect sqlRepo.Dialect) (*KeyRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewKeyRepository(db, dialect), mock
+}
+
+func TestKeyRepository_CreateKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dialect sqlRepo.Dialect
+		setup   func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "postgres",
+			dialect: sqlRepo.DialectPostgres,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"INSERT INTO api_keys (name, prefix, hash, scopes, roles, workspaces, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id")).
+					WithArgs("ci", "ggl_0123abcd", "hash", "tasks:read tasks:write", "editor", "acme globex", sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+			},
+		},
+		{
+			name:    "mysql",
+			dialect: sqlRepo.DialectMySQL,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO api_keys (name, prefix, hash, scopes, roles, workspaces, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)")).
+					WithArgs("ci", "ggl_0123abcd", "hash", "tasks:read tasks:write", "editor", "acme globex", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, mock := newMockKeyRepository(t, tt.dialect)
+			tt.setup(mock)
+
+			got, err := r.CreateKey(context.Background(), &entities.APIKey{
+				Name:       "ci",
+				Prefix:     "ggl_0123abcd",
+				Hash:       "hash",
+				Scopes:     []string{"tasks:read", "tasks:write"},
+				Roles:      []string{"editor"},
+				Workspaces: []string{"acme", "globex"},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, uint(3), got.ID)
+			assert.False(t, got.CreatedAt.IsZero())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestKeyRepository_GetKeyByHash(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+
+	r, mock := newMockKeyRepository(t, sqlRepo.DialectPostgres)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + keyColumns + " FROM api_keys WHERE hash = $1")).
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(keyRowColumns).AddRow(1, "ci", "ggl_0123abcd", "hash", "tasks:read", "editor viewer", "acme", now, now))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + keyColumns + " FROM api_keys WHERE hash = $1")).
+		WithArgs("unknown").
+		WillReturnRows(sqlmock.NewRows(keyRowColumns))
+
+	got, err := r.GetKeyByHash(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, &entities.APIKey{
+		ID:         1,
+		Name:       "ci",
+		Prefix:     "ggl_0123abcd",
+		Hash:       "hash",
+		Scopes:     []string{"tasks:read"},
+		Roles:      []string{"editor", "viewer"},
+		Workspaces: []string{"acme"},
+		CreatedAt:  now,
+		RevokedAt:  &now,
+	}, got)
+
+	_, err = r.GetKeyByHash(context.Background(), "unknown")
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyRepository_ListKeysByPage(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+
+	r, mock := newMockKeyRepository(t, sqlRepo.DialectMySQL)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM api_keys")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT "+keyColumns+" FROM api_keys ORDER BY id LIMIT ? OFFSET ?")).
+		WithArgs(2, 2).
+		WillReturnRows(sqlmock.NewRows(keyRowColumns).AddRow(3, "ci", "ggl_0123abcd", "hash", "*", "", "*", now, nil))
+
+	keys, total, err := r.ListKeysByPage(context.Background(), 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, []string{"*"}, keys[0].Scopes)
+	assert.Equal(t, []string{"*"}, keys[0].Workspaces)
+	assert.Nil(t, keys[0].RevokedAt)
+
+	_, _, err = r.ListKeysByPage(context.Background(), 0, 2)
+	assert.ErrorIs(t, err, repository.ErrInvalidData)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyRepository_RevokeKey(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+
+	r, mock := newMockKeyRepository(t, sqlRepo.DialectPostgres)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL")).
+		WithArgs(now, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + keyColumns + " FROM api_keys WHERE id = $1")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(keyRowColumns).AddRow(1, "ci", "ggl_0123abcd", "hash", "tasks:read", "", "", now, now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL")).
+		WithArgs(now, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + keyColumns + " FROM api_keys WHERE id = $1")).
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows(keyRowColumns))
+
+	got, err := r.RevokeKey(context.Background(), 1, now)
+	assert.NoError(t, err)
+	assert.Equal(t, &now, got.RevokedAt)
+
+	_, err = r.RevokeKey(context.Background(), 9, now)
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/repository"
+	"ggltask/internal/auth/domain/usecase"
+	"ggltask/pkg/auth"
+	"ggltask/pkg/workspace"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var _ usecase.KeyUseCase = (*KeyUseCaseImpl)(nil)
+
+// displayedKeyLength is how much of a key is kept in the clear, prefix included.
+const displayedKeyLength = len(auth.APIKeyPrefix) + 8
+
+type KeyUseCaseImpl struct {
+	keyRepo repository.KeyRepository
+	// staticKeys are the keys from the config, by hash. They cannot be revoked through the usecase.
+	staticKeys map[string]*entities.APIKey
+}
+
+type Option func(*KeyUseCaseImpl)
+
+// WithStaticKeys adds keys that are not stored in the repository, such as the keys from the config.
+// They need a name, a hash and scopes.
+func WithStaticKeys(keys ...*entities.APIKey) Option {
+	return func(a *KeyUseCaseImpl) {
+		for _, key := range keys {
+			a.staticKeys[key.Hash] = key
+		}
+	}
+}
+
+func NewKeyUseCaseImpl(keyRepo repository.KeyRepository, opts ...Option) *KeyUseCaseImpl {
+	a := &KeyUseCaseImpl{
+		keyRepo:    keyRepo,
+		staticKeys: make(map[string]*entities.APIKey),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// AuthenticateAPIKey is responsible for resolving an API key into the principal it was issued to.
+func (a *KeyUseCaseImpl) AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error) {
+	hash := auth.HashAPIKey(key)
+
+	apiKey, ok := a.staticKeys[hash]
+	if !ok {
+		var err error
+
+		apiKey, err = a.keyRepo.GetKeyByHash(ctx, hash)
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return auth.Principal{}, auth.ErrInvalidCredentials
+		}
+
+		if err != nil {
+			return auth.Principal{}, fmt.Errorf("repo.GetKeyByHash error: %w", err)
+		}
+	}
+
+	if apiKey.Revoked() {
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+
+	return auth.Principal{
+		Subject:    apiKey.Subject(),
+		Scopes:     apiKey.Scopes,
+		Roles:      apiKey.Roles,
+		Workspaces: apiKey.Workspaces,
+	}, nil
+}
+
+// CreateKey is responsible for issuing a new API key. The key is returned once, along with what is stored of it.
+// A key grants no more than the principal creating it: its scopes, roles and workspaces must all be the principal's.
+func (a *KeyUseCaseImpl) CreateKey(ctx context.Context, param usecase.CreateKeyParams) (*usecase.CreateKeyResult, error) {
+	if param.Name == "" || utf8.RuneCountInString(param.Name) > 50 {
+		return nil, usecase.InvalidArgumentError{Argument: "name", Reason: "must be 1 to 50 characters"}
+	}
+
+	if len(param.Scopes) == 0 {
+		return nil, usecase.InvalidArgumentError{Argument: "scopes", Reason: "must not be empty"}
+	}
+
+	for _, scope := range param.Scopes {
+		if !auth.ValidScope(scope) {
+			return nil, usecase.InvalidArgumentError{Argument: "scopes", Reason: fmt.Sprintf("unknown scope %q", scope)}
+		}
+	}
+
+	for _, role := range param.Roles {
+		if !validRole(role) {
+			return nil, usecase.InvalidArgumentError{Argument: "roles", Reason: fmt.Sprintf("invalid role %q", role)}
+		}
+	}
+
+	for _, id := range param.Workspaces {
+		if !workspace.ValidGrant(id) {
+			return nil, usecase.InvalidArgumentError{Argument: "workspaces", Reason: fmt.Sprintf("invalid workspace %q", id)}
+		}
+	}
+
+	if err := checkGrants(ctx, param); err != nil {
+		return nil, err
+	}
+
+	secret, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("auth.GenerateAPIKey error: %w", err)
+	}
+
+	key, err := a.keyRepo.CreateKey(ctx, &entities.APIKey{
+		Name:       param.Name,
+		Prefix:     secret[:displayedKeyLength],
+		Hash:       auth.HashAPIKey(secret),
+		Scopes:     param.Scopes,
+		Roles:      param.Roles,
+		Workspaces: param.Workspaces,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.CreateKey error: %w", err)
+	}
+
+	return &usecase.CreateKeyResult{
+		Key:    key,
+		Secret: secret,
+	}, nil
+}
+
+// ListKeys is responsible for listing the stored keys by page, ordered by id, revoked ones included.
+func (a *KeyUseCaseImpl) ListKeys(ctx context.Context, param usecase.ListKeysParams) (*usecase.ListKeysResult, error) {
+	keys, total, err := a.keyRepo.ListKeysByPage(ctx, param.PageIndex, param.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("repo.ListKeysByPage error: %w", err)
+	}
+
+	return &usecase.ListKeysResult{
+		Keys:  keys,
+		Total: total,
+	}, nil
+}
+
+// RevokeKey is responsible for revoking a stored key. Revoking a revoked key changes nothing.
+func (a *KeyUseCaseImpl) RevokeKey(ctx context.Context, id uint) (*entities.APIKey, error) {
+	key, err := a.keyRepo.RevokeKey(ctx, id, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return nil, usecase.NotFoundError{
+				Resource: "api key",
+				ID:       id,
+			}
+		}
+
+		return nil, fmt.Errorf("repo.RevokeKey error: %w", err)
+	}
+
+	return key, nil
+}
+
+// validRole reports whether role can be stored: 1 to 50 characters, none a space. Which roles grant what is
+// up to the task policy, so an unknown role is not an error.
+// checkGrants checks that the principal of ctx holds every scope, role and workspace of the new key. A key without
+// workspaces works in the default workspace. Requests without a principal are not restricted.
+func checkGrants(ctx context.Context, param usecase.CreateKeyParams) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	forbidden := func(reason string) error {
+		return usecase.ForbiddenError{Action: "create", Resource: "api key", Reason: reason}
+	}
+
+	for _, scope := range param.Scopes {
+		if !principal.HasScope(scope) {
+			return forbidden(fmt.Sprintf("scope %q is not granted to the principal", scope))
+		}
+	}
+
+	for _, role := range param.Roles {
+		if !principal.HasRole(role) {
+			return forbidden(fmt.Sprintf("role %q is not granted to the principal", role))
+		}
+	}
+
+	workspaces := param.Workspaces
+	if len(workspaces) == 0 {
+		workspaces = []string{workspace.Default}
+	}
+
+	for _, id := range workspaces {
+		granted := principal.InWorkspace(id)
+		if id == workspace.All {
+			granted = slices.Contains(principal.Workspaces, workspace.All)
+		}
+
+		if !granted {
+			return forbidden(fmt.Sprintf("workspace %q is not granted to the principal", id))
+		}
+	}
+
+	return nil
+}
+
+func validRole(role string) bool {
+	return role != "" && len(role) <= 50 && !strings.ContainsFunc(role, unicode.IsSpace)
+}
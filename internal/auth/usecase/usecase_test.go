@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"ggltask/internal/auth/domain/entities"
+	"ggltask/internal/auth/domain/repository"
+	"ggltask/internal/auth/domain/usecase"
+	"ggltask/internal/auth/mock/repositorymock"
+	"ggltask/pkg/auth"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "use leak detector")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestKeyUseCaseImpl_AuthenticateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	revokedAt := time.Now()
+	repoErr := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		key     string
+		setup   func(mockRepo *repositorymock.MockKeyRepository)
+		want    auth.Principal
+		wantErr error
+	}{
+		{
+			name:  "static key",
+			key:   "ggl_static",
+			setup: func(*repositorymock.MockKeyRepository) {},
+			want:  auth.Principal{Subject: "apikey:config:bootstrap", Scopes: []string{auth.ScopeAll}},
+		},
+		{
+			name: "stored key",
+			key:  "ggl_stored",
+			setup: func(mockRepo *repositorymock.MockKeyRepository) {
+				mockRepo.EXPECT().GetKeyByHash(gomock.Any(), auth.HashAPIKey("ggl_stored")).
+					Return(&entities.APIKey{
+						ID:         1,
+						Name:       "ci",
+						Scopes:     []string{auth.ScopeTasksRead},
+						Roles:      []string{"viewer"},
+						Workspaces: []string{"acme"},
+					}, nil)
+			},
+			want: auth.Principal{
+				Subject:    "apikey:1",
+				Scopes:     []string{auth.ScopeTasksRead},
+				Roles:      []string{"viewer"},
+				Workspaces: []string{"acme"},
+			},
+		},
+		{
+			name: "revoked key",
+			key:  "ggl_revoked",
+			setup: func(mockRepo *repositorymock.MockKeyRepository) {
+				mockRepo.EXPECT().GetKeyByHash(gomock.Any(), auth.HashAPIKey("ggl_revoked")).
+					Return(&entities.APIKey{ID: 2, Name: "old", RevokedAt: &revokedAt}, nil)
+			},
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "unknown key",
+			key:  "ggl_unknown",
+			setup: func(mockRepo *repositorymock.MockKeyRepository) {
+				mockRepo.EXPECT().GetKeyByHash(gomock.Any(), gomock.Any()).Return(nil, repository.ErrDataNotFound)
+			},
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "repository error",
+			key:  "ggl_unknown",
+			setup: func(mockRepo *repositorymock.MockKeyRepository) {
+				mockRepo.EXPECT().GetKeyByHash(gomock.Any(), gomock.Any()).Return(nil, repoErr)
+			},
+			wantErr: repoErr,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := repositorymock.NewMockKeyRepository(gomock.NewController(t))
+			tt.setup(mockRepo)
+
+			uc := NewKeyUseCaseImpl(mockRepo, WithStaticKeys(&entities.APIKey{
+				Name:   "bootstrap",
+				Hash:   auth.HashAPIKey("ggl_static"),
+				Scopes: []string{auth.ScopeAll},
+			}))
+
+			got, err := uc.AuthenticateAPIKey(context.Background(), tt.key)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKeyUseCaseImpl_CreateKey(t *testing.T) {
+	t.Parallel()
+
+	// a key admin limited to the acme workspace, as an editor
+	keyAdmin := &auth.Principal{
+		Subject:    "apikey:1",
+		Scopes:     []string{auth.ScopeKeysAdmin, auth.ScopeTasksRead},
+		Roles:      []string{"editor"},
+		Workspaces: []string{"acme"},
+	}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		params    usecase.CreateKeyParams
+		wantErr   error
+	}{
+		{
+			name: "success",
+			params: usecase.CreateKeyParams{
+				Name:       "ci",
+				Scopes:     []string{auth.ScopeTasksRead, auth.ScopeTasksWrite},
+				Roles:      []string{"editor"},
+				Workspaces: []string{"acme", "*"},
+			},
+		},
+		{
+			name:    "without name",
+			params:  usecase.CreateKeyParams{Scopes: []string{auth.ScopeTasksRead}},
+			wantErr: usecase.InvalidArgumentError{Argument: "name", Reason: "must be 1 to 50 characters"},
+		},
+		{
+			name:    "without scopes",
+			params:  usecase.CreateKeyParams{Name: "ci"},
+			wantErr: usecase.InvalidArgumentError{Argument: "scopes", Reason: "must not be empty"},
+		},
+		{
+			name:    "unknown scope",
+			params:  usecase.CreateKeyParams{Name: "ci", Scopes: []string{"tasks:delete"}},
+			wantErr: usecase.InvalidArgumentError{Argument: "scopes", Reason: `unknown scope "tasks:delete"`},
+		},
+		{
+			name:    "invalid role",
+			params:  usecase.CreateKeyParams{Name: "ci", Scopes: []string{auth.ScopeTasksRead}, Roles: []string{"team lead"}},
+			wantErr: usecase.InvalidArgumentError{Argument: "roles", Reason: `invalid role "team lead"`},
+		},
+		{
+			name:    "invalid workspace",
+			params:  usecase.CreateKeyParams{Name: "ci", Scopes: []string{auth.ScopeTasksRead}, Workspaces: []string{"Acme Corp"}},
+			wantErr: usecase.InvalidArgumentError{Argument: "workspaces", Reason: `invalid workspace "Acme Corp"`},
+		},
+		{
+			name:      "within the grants of the principal",
+			principal: keyAdmin,
+			params:    usecase.CreateKeyParams{Name: "ci", Scopes: []string{auth.ScopeTasksRead}, Roles: []string{"editor"}, Workspaces: []string{"acme"}},
+		},
+		{
+			name:      "scope the principal lacks",
+			principal: keyAdmin,
+			params:    usecase.CreateKeyParams{Name: "ci", Scopes: []string{auth.ScopeAll}, Workspaces: []string{"acme"}},
+			wantErr:   usecase.ForbiddenError{Action: "create", Resource: "api key", Reason: `scope "*" is not granted to the principal`},
+		},
+		{
+			name:      "role the principal lacks",
+			principal: keyAdmin,
+			params:    usecase.CreateKeyParams{Name: "ci", Scopes: []string{auth.ScopeTasksRead}, Roles: []string{"admin"}, Workspaces: []string{"acme"}},
+			wantErr:   usecase.ForbiddenError{Action: "create", Resource: "api key", Reason: `role "admin" is not granted to the principal`},
+		},
+		{
+			name:      "every workspace",
+			principal: keyAdmin,
+			params:    usecase.CreateKeyParams{Name: "ci", Scopes: []string{auth.ScopeTasksRead}, Workspaces: []string{"*"}},
+			wantErr:   usecase.ForbiddenError{Action: "create", Resource: "api key", Reason: `workspace "*" is not granted to the principal`},
+		},
+		{
+			name:      "default workspace the principal lacks",
+			principal: keyAdmin,
+			params:    usecase.CreateKeyParams{Name: "ci", Scopes: []string{auth.ScopeTasksRead}},
+			wantErr:   usecase.ForbiddenError{Action: "create", Resource: "api key", Reason: `workspace "default" is not granted to the principal`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := repositorymock.NewMockKeyRepository(gomock.NewController(t))
+			mockRepo.EXPECT().CreateKey(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, key *entities.APIKey) (*entities.APIKey, error) {
+					key.ID = 1
+
+					return key, nil
+				}).AnyTimes()
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.WithPrincipal(ctx, *tt.principal)
+			}
+
+			got, err := NewKeyUseCaseImpl(mockRepo).CreateKey(ctx, tt.params)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, auth.IsAPIKey(got.Secret))
+			// only the hash and the start of the key are kept
+			assert.Equal(t, auth.HashAPIKey(got.Secret), got.Key.Hash)
+			assert.True(t, strings.HasPrefix(got.Secret, got.Key.Prefix))
+			assert.Len(t, got.Key.Prefix, len(auth.APIKeyPrefix)+8)
+			assert.Equal(t, tt.params.Scopes, got.Key.Scopes)
+			assert.Equal(t, tt.params.Roles, got.Key.Roles)
+			assert.Equal(t, tt.params.Workspaces, got.Key.Workspaces)
+		})
+	}
+}
+
+func TestKeyUseCaseImpl_RevokeKey(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRepo := repositorymock.NewMockKeyRepository(ctrl)
+	mockRepo.EXPECT().RevokeKey(gomock.Any(), uint(1), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id uint, revokedAt time.Time) (*entities.APIKey, error) {
+			return &entities.APIKey{ID: id, RevokedAt: &revokedAt}, nil
+		})
+	mockRepo.EXPECT().RevokeKey(gomock.Any(), uint(9), gomock.Any()).Return(nil, repository.ErrDataNotFound)
+
+	uc := NewKeyUseCaseImpl(mockRepo)
+
+	key, err := uc.RevokeKey(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.True(t, key.Revoked())
+
+	_, err = uc.RevokeKey(context.Background(), 9)
+	assert.Equal(t, usecase.NotFoundError{Resource: "api key", ID: uint(9)}, err)
+}
+
+func TestKeyUseCaseImpl_ListKeys(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRepo := repositorymock.NewMockKeyRepository(ctrl)
+	mockRepo.EXPECT().ListKeysByPage(gomock.Any(), 1, 10).Return([]*entities.APIKey{{ID: 1}, {ID: 2}}, 2, nil)
+
+	result, err := NewKeyUseCaseImpl(mockRepo).ListKeys(context.Background(), usecase.ListKeysParams{PageIndex: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	assert.Len(t, result.Keys, 2)
+}
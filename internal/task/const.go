@@ -1,13 +1,147 @@
 package task
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// TaskStatus is the code of a status, which is what tasks store. The workflow names the statuses and decides
+// which ones a task can take.
+//
 //nolint:revive
 type TaskStatus int8
 
+// The built-in statuses. Their codes and names are fixed, and the rules of the service rely on them: a completed
+// or archived task is closed. Statuses a workflow adds are open.
 const (
 	TaskStatusIncomplete TaskStatus = iota // task is incomplete
 	TaskStatusCompleted                    // task is completed
+	TaskStatusInProgress                   // task is being worked on
+	TaskStatusBlocked                      // task cannot progress for now
+	TaskStatusInReview                     // task is done and waits for a review
+	TaskStatusArchived                     // task was dropped without being completed
 )
 
+var builtinStatusNames = []string{"incomplete", "completed", "in_progress", "blocked", "in_review", "archived"}
+
+// Valid reports whether the status is a code a task can store. Which statuses a task can take, and in which
+// order, is up to the workflow.
 func (s TaskStatus) Valid() bool {
-	return s == TaskStatusIncomplete || s == TaskStatusCompleted
+	return s >= 0
+}
+
+// Builtin reports whether the status is one of the built-in statuses.
+func (s TaskStatus) Builtin() bool {
+	return s >= TaskStatusIncomplete && s <= TaskStatusArchived
+}
+
+// Closed reports whether a task in the status needs no more work: it is completed or archived.
+// A closed task is neither overdue nor blocking another one.
+func (s TaskStatus) Closed() bool {
+	return s == TaskStatusCompleted || s == TaskStatusArchived
+}
+
+// String returns the name of a built-in status; the workflow names the others.
+func (s TaskStatus) String() string {
+	if !s.Builtin() {
+		return "TaskStatus(" + strconv.Itoa(int(s)) + ")"
+	}
+
+	return builtinStatusNames[s]
+}
+
+// Ref returns the status as a reference by code.
+func (s TaskStatus) Ref() StatusRef {
+	return StatusRef(strconv.Itoa(int(s)))
+}
+
+// BuiltinStatus returns the built-in status with the given name, such as `in_progress`.
+func BuiltinStatus(name string) (TaskStatus, bool) {
+	for i, n := range builtinStatusNames {
+		if n == name {
+			return TaskStatus(i), true
+		}
+	}
+
+	return TaskStatusIncomplete, false
+}
+
+// StatusRef is a status as a client names it: by name, such as `in_progress`, or by code, such as `2`.
+// Only the workflow can tell which status it is.
+type StatusRef string
+
+// UnmarshalJSON accepts a name as a JSON string and a code as a JSON number.
+func (r *StatusRef) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*r = StatusRef(name)
+
+		return nil
+	}
+
+	var code int
+	if err := json.Unmarshal(data, &code); err != nil {
+		return fmt.Errorf("invalid status %s", data)
+	}
+
+	*r = StatusRef(strconv.Itoa(code))
+
+	return nil
+}
+
+// Priority ranks how urgent a task is; the levels are ordered, so they can be compared.
+type Priority int8
+
+const (
+	PriorityNone Priority = iota // task has no priority
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func (p Priority) Valid() bool {
+	return p >= PriorityNone && p <= PriorityUrgent
+}
+
+func (p Priority) String() string {
+	if !p.Valid() {
+		return "Priority(" + strconv.Itoa(int(p)) + ")"
+	}
+
+	return priorityNames[p]
+}
+
+// ParsePriority returns the priority with the given name, such as `high`.
+func ParsePriority(name string) (Priority, bool) {
+	for i, n := range priorityNames {
+		if n == name {
+			return Priority(i), true
+		}
+	}
+
+	return PriorityNone, false
+}
+
+// MarshalText encodes a priority as its name, so the API and the stored JSON speak `high` rather than 3.
+func (p Priority) MarshalText() ([]byte, error) {
+	if !p.Valid() {
+		return nil, fmt.Errorf("invalid priority %d", p)
+	}
+
+	return []byte(p.String()), nil
+}
+
+func (p *Priority) UnmarshalText(text []byte) error {
+	priority, ok := ParsePriority(string(text))
+	if !ok {
+		return fmt.Errorf("invalid priority %q", text)
+	}
+
+	*p = priority
+
+	return nil
 }
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidETag = errors.New("invalid entity tag")
+
+// formatETag returns the strong entity tag of a task version, e.g. `"3"`.
+func formatETag(version uint) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
+
+// parseIfMatch returns the task versions listed by an If-Match header, one of which the task must be at.
+// An empty header or `*` imposes no version and yields nil. If-Match uses the strong comparison, so weak tags
+// and tags that are not versions never match and are left out: a header listing only those yields an empty list.
+// A header that is not a list of entity tags is invalid.
+func parseIfMatch(header string) ([]uint, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	versions := make([]uint, 0)
+	tags := 0
+
+	for rest := header; ; tags++ {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			break
+		}
+
+		weak := strings.HasPrefix(rest, "W/")
+		rest = strings.TrimPrefix(rest, "W/")
+
+		// an entity tag is a quoted string without quotes inside, which may hold commas
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, errInvalidETag
+		}
+
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return nil, errInvalidETag
+		}
+
+		opaque := rest[1 : end+1]
+
+		rest = strings.TrimLeft(rest[end+2:], " \t")
+		if rest != "" && rest[0] != ',' {
+			return nil, errInvalidETag
+		}
+
+		if version, err := strconv.ParseUint(opaque, 10, 64); !weak && err == nil && version != 0 {
+			versions = append(versions, uint(version))
+		}
+	}
+
+	if tags == 0 {
+		return nil, errInvalidETag
+	}
+
+	return versions, nil
+}
+
+// formatLastModified returns the Last-Modified value of a task updated at t.
+func formatLastModified(t time.Time) string {
+	return t.UTC().Format(http.TimeFormat)
+}
+
+// notModified evaluates the conditional headers of a GET for a task with the given entity tag and
+// modification time, as RFC 9110 section 13.2.2 orders them: If-None-Match, when present, decides alone.
+func notModified(header http.Header, etag string, updatedAt time.Time) bool {
+	if ifNoneMatch := header.Get("If-None-Match"); ifNoneMatch != "" {
+		return matchIfNoneMatch(ifNoneMatch, etag)
+	}
+
+	since, err := http.ParseTime(header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// Last-Modified has a one second resolution
+	return !updatedAt.Truncate(time.Second).After(since)
+}
+
+// matchIfNoneMatch reports whether an If-None-Match header lists the entity tag, using the weak comparison.
+func matchIfNoneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
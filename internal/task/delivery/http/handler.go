@@ -1,15 +1,27 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"ggltask/internal/task/domain/usecase"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 )
 
+// maxPatchSize bounds the body of a PATCH request.
+const maxPatchSize = 1 << 20
+
+// patchFormats maps the media types accepted by PATCH to the patch format they carry.
+var patchFormats = map[string]usecase.PatchFormat{
+	"application/merge-patch+json": usecase.PatchFormatMergePatch,
+	"application/json-patch+json":  usecase.PatchFormatJSONPatch,
+}
+
 type TaskHandler struct {
 	taskUsecase usecase.TaskUseCase
 }
@@ -25,95 +37,189 @@ func NewTaskHandler(taskUsecase usecase.TaskUseCase) *TaskHandler {
 // @Produce json
 // @Param request body CreateTaskRequest true "Create task request"
 // @Success 200 {object} CreateTaskResponse "Create task response"
+// @Header 200 {string} ETag "task version"
 // @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 429 {object} ErrorResponse "rate limited"
 // @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
 // @Router /api/v1/tasks [post]
 func (h *TaskHandler) CreateTask(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var req CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, InvalidRequestError())
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
 
 		return
 	}
 
-	newTask, err := h.taskUsecase.CreateTask(ctx, usecase.CreateTaskParams{Name: req.Name})
+	newTask, err := h.taskUsecase.CreateTask(ctx, req.params())
 	if err != nil {
 		zerolog.Ctx(ctx).Error().Fields(map[string]any{
 			"payload": fmt.Sprintf("%+v", req),
 			"error":   err,
 		}).Msg("task create error")
 
-		c.JSON(UseCaesErrorToErrorResp(err))
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
 
 		return
 	}
 
+	c.Header("ETag", formatETag(newTask.Version))
 	c.JSON(http.StatusOK, CreateTaskResponse{
 		Task: newTask,
 	})
 }
 
 // @Summary List tasks
-// @Description List tasks
+// @Description List tasks, optionally filtered and sorted, by page_index or by cursor
 // @Tags task
 // @Accept json
 // @Produce json
 // @Param request query ListTasksRequest true "List tasks request"
 // @Success 200 {object} ListTasksResponse "List tasks response"
+// @Header 200 {string} Link "RFC 8288 links to the next and previous pages"
 // @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "list not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
 // @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
 // @Router /api/v1/tasks [get]
 func (h *TaskHandler) ListTasks(c *gin.Context) {
-	ctx := c.Request.Context()
-
 	var req ListTasksRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, InvalidRequestError())
+		c.JSON(http.StatusBadRequest, InvalidRequestError(c.Request.Context()))
 
 		return
 	}
 
-	result, err := h.taskUsecase.ListTasks(ctx, usecase.ListTasksParams{
-		PageIndex: req.PageIndex,
-		PageSize:  req.PageSize,
-	})
+	h.listTasks(c, req)
+}
+
+func (h *TaskHandler) listTasks(c *gin.Context, req ListTasksRequest) {
+	ctx := c.Request.Context()
+
+	params, err := req.params()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	result, err := h.taskUsecase.ListTasks(ctx, params)
 	if err != nil {
 		zerolog.Ctx(ctx).Error().Fields(map[string]any{
 			"payload": fmt.Sprintf("%+v", req),
 			"error":   err,
 		}).Msg("task list error")
 
-		c.JSON(UseCaesErrorToErrorResp(err))
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
 
 		return
 	}
 
+	if link := formatLinkHeader(c.Request.URL, result.NextCursor, result.PrevCursor); link != "" {
+		c.Header("Link", link)
+	}
+
 	c.JSON(http.StatusOK, ListTasksResponse{
-		Tasks: result.Tasks,
-		Total: result.Total,
+		Tasks:      result.Tasks,
+		Total:      result.Total,
+		NextCursor: result.NextCursor,
+		PrevCursor: result.PrevCursor,
+	})
+}
+
+// @Summary Get task
+// @Description Get a task by id. A request whose If-None-Match lists the current ETag, or whose
+// @Description If-Modified-Since is not older than Last-Modified, gets 304 Not Modified without a body.
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param If-None-Match header string false "ETags of the task versions the client has"
+// @Param If-Modified-Since header string false "Last-Modified of the task version the client has"
+// @Success 200 {object} GetTaskResponse "Get task response"
+// @Success 304 "task not modified"
+// @Header 200,304 {string} ETag "task version"
+// @Header 200,304 {string} Last-Modified "time of the last change of the task"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id} [get]
+func (h *TaskHandler) GetTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	taskEntity, err := h.taskUsecase.GetTask(ctx, uint(idUint))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": id,
+			"error":   err,
+		}).Msg("task get error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	// the ETag is the version, like everywhere else, so it can be sent back in If-Match
+	etag := formatETag(taskEntity.Version)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", formatLastModified(taskEntity.UpdatedAt))
+
+	if notModified(c.Request.Header, etag, taskEntity.UpdatedAt) {
+		c.Status(http.StatusNotModified)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, GetTaskResponse{
+		Task: taskEntity,
 	})
 }
 
 // @Summary Update task
-// @Description Update a task
+// @Description Update a task. Its status can only change along the transitions of the workflow. Completing a recurring task creates its next occurrence, which takes the recurrence over.
 // @Tags task
 // @Accept json
 // @Produce json
 // @Param id path string true "Task ID"
+// @Param If-Match header string false "ETags of the task versions the update applies to"
 // @Param request body UpdateTaskRequest true "Update task request"
 // @Success 200 {object} UpdateTaskResponse "Update task response"
+// @Header 200 {string} ETag "task version"
 // @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
 // @Failure 404 {object} ErrorResponse "not found"
+// @Failure 409 {object} ErrorResponse "task has incomplete subtasks or blockers, would be a subtask of itself, or the workflow forbids its new status"
+// @Failure 412 {object} ErrorResponse "task has been modified since the If-Match version"
+// @Failure 429 {object} ErrorResponse "rate limited"
 // @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
 // @Router /api/v1/tasks/{id} [put]
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var req UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, InvalidRequestError())
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
 
 		return
 	}
@@ -121,16 +227,25 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	id := c.Param("id")
 	idUint, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, InvalidRequestError())
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
 
 		return
 	}
 
-	updateTaskParams := usecase.UpdateTaskParams{
-		ID:     uint(idUint),
-		Name:   req.Name,
-		Status: req.Status,
+	expectedVersion, err := h.expectedVersion(c, uint(idUint))
+	if err != nil {
+		if errors.Is(err, errInvalidETag) {
+			c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+			return
+		}
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
 	}
+
+	updateTaskParams := req.params(uint(idUint), expectedVersion)
 	updatedTask, err := h.taskUsecase.UpdateTask(ctx, updateTaskParams)
 	if err != nil {
 		zerolog.Ctx(ctx).Error().Fields(map[string]any{
@@ -138,26 +253,152 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 			"error":   err,
 		}).Msg("task update error")
 
-		c.JSON(UseCaesErrorToErrorResp(err))
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
 
 		return
 	}
 
+	c.Header("ETag", formatETag(updatedTask.Version))
 	c.JSON(http.StatusOK, UpdateTaskResponse{
 		Task: updatedTask,
 	})
 }
 
+// @Summary Patch task
+// @Description Partially update a task with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902)
+// @Tags task
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param If-Match header string false "apply the patch only if the task is still at one of these versions"
+// @Param request body object true "merge patch document or array of JSON Patch operations"
+// @Success 200 {object} UpdateTaskResponse "Patch task response"
+// @Header 200 {string} ETag "task version"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "not found"
+// @Failure 409 {object} ErrorResponse "a JSON Patch test operation failed, or the subtask rules are broken"
+// @Failure 412 {object} ErrorResponse "task has been modified since the If-Match version"
+// @Failure 415 {object} ErrorResponse "unsupported patch media type"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id} [patch]
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	format, ok := patchFormats[c.ContentType()]
+	if !ok {
+		c.JSON(http.StatusUnsupportedMediaType, UnsupportedMediaTypeError(ctx))
+
+		return
+	}
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c, uint(idUint))
+	if err != nil {
+		if errors.Is(err, errInvalidETag) {
+			c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+			return
+		}
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	patchTaskParams := usecase.PatchTaskParams{
+		ID:              uint(idUint),
+		Format:          format,
+		Patch:           patch,
+		ExpectedVersion: expectedVersion,
+	}
+	patchedTask, err := h.taskUsecase.PatchTask(ctx, patchTaskParams)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", patchTaskParams),
+			"error":   err,
+		}).Msg("task patch error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.Header("ETag", formatETag(patchedTask.Version))
+	c.JSON(http.StatusOK, UpdateTaskResponse{
+		Task: patchedTask,
+	})
+}
+
+// expectedVersion returns the version the If-Match header of the request requires the task of id to be at,
+// zero when it requires none. A list of tags matches when any of them is the current version of the task.
+func (h *TaskHandler) expectedVersion(c *gin.Context, id uint) (uint, error) {
+	versions, err := parseIfMatch(strings.Join(c.Request.Header.Values("If-Match"), ","))
+	if err != nil || versions == nil {
+		return 0, err
+	}
+
+	switch len(versions) {
+	case 0:
+		return 0, usecase.PreconditionFailedError{Resource: "task", ID: id}
+	case 1:
+		return versions[0], nil
+	}
+
+	ctx := c.Request.Context()
+
+	current, err := h.taskUsecase.GetTask(ctx, id)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": id,
+			"error":   err,
+		}).Msg("task get error")
+
+		return 0, err
+	}
+
+	for _, version := range versions {
+		if version == current.Version {
+			// the update still fails if the task changes in between
+			return version, nil
+		}
+	}
+
+	return 0, usecase.PreconditionFailedError{Resource: "task", ID: id}
+}
+
 // @Summary Delete task
-// @Description Delete a task
+// @Description Move a task to the trash. Its subtasks are trashed along with it or handed over to its parent,
+// @Description depending on the server configuration.
 // @Tags task
 // @Accept json
 // @Produce json
 // @Param id path string true "Task ID"
 // @Success 200 {object} nil "empty result"
 // @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
 // @Failure 404 {object} ErrorResponse "not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
 // @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
 // @Router /api/v1/tasks/{id} [delete]
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -165,7 +406,7 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	id := c.Param("id")
 	idUint, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, InvalidRequestError())
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
 
 		return
 	}
@@ -176,10 +417,521 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 			"error":   err,
 		}).Msg("task delete error")
 
-		c.JSON(UseCaesErrorToErrorResp(err))
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
 
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{})
 }
+
+// @Summary List trash
+// @Description List trashed tasks, most recently deleted first
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param request query ListTrashRequest true "List trash request"
+// @Success 200 {object} ListTasksResponse "List trashed tasks response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/trash [get]
+func (h *TaskHandler) ListTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req ListTrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	result, err := h.taskUsecase.ListTrash(ctx, usecase.ListTasksParams{
+		PageIndex: req.PageIndex,
+		PageSize:  req.PageSize,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", req),
+			"error":   err,
+		}).Msg("trash list error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ListTasksResponse{
+		Tasks: result.Tasks,
+		Total: result.Total,
+	})
+}
+
+// @Summary Restore task
+// @Description Move a task out of the trash
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} RestoreTaskResponse "Restore task response"
+// @Header 200 {string} ETag "task version"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id}/restore [post]
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	restoredTask, err := h.taskUsecase.RestoreTask(ctx, uint(idUint))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": id,
+			"error":   err,
+		}).Msg("task restore error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.Header("ETag", formatETag(restoredTask.Version))
+	c.JSON(http.StatusOK, RestoreTaskResponse{
+		Task: restoredTask,
+	})
+}
+
+// @Summary Purge task
+// @Description Permanently remove a trashed task
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} nil "empty result"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/trash/{id} [delete]
+func (h *TaskHandler) PurgeTask(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	if err := h.taskUsecase.PurgeTask(ctx, uint(idUint)); err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": id,
+			"error":   err,
+		}).Msg("task purge error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// @Summary List task history
+// @Description List the changes of a task, newest first. The actor of a change is the authenticated subject; without credentials, it is the X-Actor header, prefixed with unverified:.
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request query ListTaskHistoryRequest true "List task history request"
+// @Success 200 {object} ListTaskHistoryResponse "List task history response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id}/history [get]
+func (h *TaskHandler) ListTaskHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	var req ListTaskHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	result, err := h.taskUsecase.ListTaskHistory(ctx, usecase.ListTaskHistoryParams{
+		TaskID:    uint(idUint),
+		PageIndex: req.PageIndex,
+		PageSize:  req.PageSize,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%s %+v", id, req),
+			"error":   err,
+		}).Msg("task history list error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ListTaskHistoryResponse{
+		History: result.History,
+		Total:   result.Total,
+	})
+}
+
+// @Summary List subtasks
+// @Description List the direct subtasks of a task, ordered by id, with the progress of its subtasks at every depth.
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} ListSubtasksResponse "List subtasks response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "task not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id}/subtasks [get]
+func (h *TaskHandler) ListSubtasks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	result, err := h.taskUsecase.ListSubtasks(ctx, uint(idUint))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": id,
+			"error":   err,
+		}).Msg("subtask list error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ListSubtasksResponse{
+		Subtasks: result.Subtasks,
+		Progress: result.Progress,
+	})
+}
+
+// @Summary Add task dependency
+// @Description Make a task depend on another one, which must be completed first. Adding an existing dependency
+// @Description changes nothing; a dependency that would create a cycle is rejected.
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body AddDependencyRequest true "Add dependency request"
+// @Success 200 {object} UpdateTaskResponse "Task with its dependencies"
+// @Header 200 {string} ETag "task version"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "task not found"
+// @Failure 409 {object} ErrorResponse "the dependency would create a cycle"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id}/dependencies [post]
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	var req AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	param := usecase.DependencyParams{TaskID: uint(idUint), BlockerID: req.BlockerID}
+
+	updatedTask, err := h.taskUsecase.AddDependency(ctx, param)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", param),
+			"error":   err,
+		}).Msg("task dependency add error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.Header("ETag", formatETag(updatedTask.Version))
+	c.JSON(http.StatusOK, UpdateTaskResponse{
+		Task: updatedTask,
+	})
+}
+
+// @Summary Remove task dependency
+// @Description Remove a dependency of a task
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request query RemoveDependencyRequest true "Remove dependency request"
+// @Success 200 {object} UpdateTaskResponse "Task with its remaining dependencies"
+// @Header 200 {string} ETag "task version"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "task or dependency not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id}/dependencies [delete]
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	var req RemoveDependencyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	param := usecase.DependencyParams{TaskID: uint(idUint), BlockerID: req.BlockerID}
+
+	updatedTask, err := h.taskUsecase.RemoveDependency(ctx, param)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", param),
+			"error":   err,
+		}).Msg("task dependency remove error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.Header("ETag", formatETag(updatedTask.Version))
+	c.JSON(http.StatusOK, UpdateTaskResponse{
+		Task: updatedTask,
+	})
+}
+
+// @Summary List task occurrences
+// @Description Preview the next occurrences of a recurring task, after its current due date.
+// @Description A task that does not recur has none.
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request query ListOccurrencesRequest true "List occurrences request"
+// @Success 200 {object} ListOccurrencesResponse "List occurrences response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "task not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id}/occurrences [get]
+func (h *TaskHandler) ListOccurrences(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	var req ListOccurrencesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	occurrences, err := h.taskUsecase.ListOccurrences(ctx, usecase.ListOccurrencesParams{
+		ID:    uint(idUint),
+		Count: req.Count,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%s %+v", id, req),
+			"error":   err,
+		}).Msg("task occurrences list error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ListOccurrencesResponse{
+		Occurrences: occurrences,
+	})
+}
+
+// @Summary Get workflow
+// @Description Get the workflow of the task statuses: the statuses, the initial status of new tasks,
+// @Description and the statuses a task can move to from each status.
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Success 200 {object} GetWorkflowResponse "Get workflow response"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/workflow [get]
+func (h *TaskHandler) GetWorkflow(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	workflow, err := h.taskUsecase.GetWorkflow(ctx)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"error": err,
+		}).Msg("workflow get error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, GetWorkflowResponse{
+		Workflow: workflow,
+	})
+}
+
+// @Summary Search tasks
+// @Description Search tasks by name, most relevant first. Matching is case-insensitive word by word,
+// @Description and the last word of the query also matches as a prefix, for autocomplete.
+// @Description Each hit carries its name as an HTML fragment with the matched words wrapped in <mark>.
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param request query SearchTasksRequest true "Search tasks request"
+// @Success 200 {object} SearchTasksResponse "Search tasks response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/search [get]
+func (h *TaskHandler) SearchTasks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req SearchTasksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	result, err := h.taskUsecase.SearchTasks(ctx, usecase.SearchTasksParams{
+		Query:     req.Query,
+		PageIndex: req.PageIndex,
+		PageSize:  req.PageSize,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", req),
+			"error":   err,
+		}).Msg("task search error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, SearchTasksResponse{
+		Hits:  result.Hits,
+		Total: result.Total,
+	})
+}
+
+// @Summary Batch tasks
+// @Description Apply a batch of create, update and delete operations, in order. An atomic batch applies
+// @Description every operation or none of them: when one fails, the others report ABORTED.
+// @Description Otherwise each operation is applied on its own. Either way the response holds a result per operation.
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param request body BatchTasksRequest true "Batch tasks request"
+// @Success 200 {object} BatchTasksResponse "Batch tasks response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/tasks:batch [post]
+func (h *TaskHandler) BatchTasks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req BatchTasksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+		return
+	}
+
+	result, err := h.taskUsecase.BatchTasks(ctx, req.params())
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", req),
+			"error":   err,
+		}).Msg("task batch error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+		return
+	}
+
+	results := make([]BatchOperationResponse, 0, len(result.Results))
+	for i, r := range result.Results {
+		if r.Err != nil {
+			status, errResp := UseCaesErrorToErrorResp(ctx, r.Err)
+			if status >= http.StatusInternalServerError {
+				zerolog.Ctx(ctx).Error().Fields(map[string]any{
+					"payload": fmt.Sprintf("%+v", req.Operations[i]),
+					"error":   r.Err,
+				}).Msg("task batch operation error")
+			}
+
+			results = append(results, BatchOperationResponse{Status: status, Error: &errResp})
+
+			continue
+		}
+
+		results = append(results, BatchOperationResponse{Status: http.StatusOK, Task: r.Task})
+	}
+
+	c.JSON(http.StatusOK, BatchTasksResponse{Results: results})
+}
@@ -1,13 +1,16 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"ggltask/internal/task"
 	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
 	"ggltask/internal/task/domain/usecase"
 	"ggltask/internal/task/mock/usecasemock"
+	"ggltask/pkg/transport/middleware"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -36,6 +39,10 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func TestTaskHandler_CreateTask(t *testing.T) {
 	t.Parallel()
 
@@ -77,10 +84,39 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 			wantErr:        false,
 			wantStatusCode: 200,
 		},
+		{
+			name:        "details",
+			requestBody: `{"name": "test_name", "description": "long", "priority": "urgent", "due_at": "2030-01-02T09:00:00+02:00", "tags": ["ops"]}`,
+			wantResponse: CreateTaskResponse{
+				Task: &entities.Task{ID: 1, Name: "test_name", Priority: task.PriorityUrgent, Tags: []string{"ops"}, CreatedAt: now, UpdatedAt: now},
+			},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().CreateTask(gomock.Any(), usecase.CreateTaskParams{
+					Name:        "test_name",
+					Description: "long",
+					Priority:    task.PriorityUrgent,
+					DueAt:       ptr(time.Date(2030, 1, 2, 9, 0, 0, 0, time.FixedZone("", 2*60*60))),
+					Tags:        []string{"ops"},
+				}).Return(&entities.Task{ID: 1, Name: "test_name", Priority: task.PriorityUrgent, Tags: []string{"ops"}, CreatedAt: now, UpdatedAt: now}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:         "unknown priority",
+			requestBody:  `{"name": "test_name", "priority": "asap"}`,
+			wantResponse: InvalidRequestError(context.Background()),
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
 		{
 			name:         "request body is invalid",
 			requestBody:  `{"name": ""}`,
-			wantResponse: InvalidRequestError(),
+			wantResponse: InvalidRequestError(context.Background()),
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				return usecasemock.NewMockTaskUseCase(ctrl)
 			},
@@ -107,7 +143,7 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 		{
 			name:         "task name too long",
 			requestBody:  `{"name": "012345678901234567890123456789012345678901234567891"}`,
-			wantResponse: InvalidRequestError(),
+			wantResponse: InvalidRequestError(context.Background()),
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				return usecasemock.NewMockTaskUseCase(ctrl)
 			},
@@ -193,7 +229,7 @@ func TestTaskHandler_ListTasks(t *testing.T) {
 		{
 			name:         "request body is invalid",
 			requestBody:  `page_index=3&page_size=-2`,
-			wantResponse: InvalidRequestError(),
+			wantResponse: InvalidRequestError(context.Background()),
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				return usecasemock.NewMockTaskUseCase(ctrl)
 			},
@@ -236,6 +272,100 @@ func TestTaskHandler_ListTasks(t *testing.T) {
 			},
 			wantStatusCode: http.StatusOK,
 		},
+		{
+			name:         "filters and sort",
+			requestBody:  `status=completed&name=milk&ids=3,1&created_since=2024-01-01T00:00:00Z&updated_before=2024-02-01T00:00:00%2B08:00&sort=created_at,-updated_at`,
+			wantResponse: ListTasksResponse{Tasks: []*entities.Task{}, Total: 0},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				completed := task.StatusRef("completed")
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), usecase.ListTasksParams{
+					PageIndex: 1,
+					PageSize:  10,
+					Status:    &completed,
+					Filter: repository.TaskFilter{
+						NameContains:  "milk",
+						CreatedSince:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+						UpdatedBefore: time.Date(2024, 2, 1, 0, 0, 0, 0, time.FixedZone("", 8*60*60)),
+						IDs:           []uint{3, 1},
+					},
+					Sort: []repository.SortKey{
+						{Field: repository.SortFieldCreatedAt},
+						{Field: repository.SortFieldUpdatedAt, Desc: true},
+					},
+				}).Return(&usecase.ListTasksResult{Tasks: []*entities.Task{}}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:         "detail filters",
+			requestBody:  `priority=%3E%3Dhigh&tag=Ops&tag=db&overdue=true&ready=true&due_before=2030-01-01T00:00:00Z&sort=-priority`,
+			wantResponse: ListTasksResponse{Tasks: []*entities.Task{}, Total: 0},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				high := task.PriorityHigh
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), usecase.ListTasksParams{
+					PageIndex: 1,
+					PageSize:  10,
+					Filter: repository.TaskFilter{
+						MinPriority: &high,
+						Tags:        []string{"ops", "db"},
+						DueBefore:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+						Overdue:     true,
+						Ready:       true,
+					},
+					Sort: []repository.SortKey{{Field: repository.SortFieldPriority, Desc: true}},
+				}).Return(&usecase.ListTasksResult{Tasks: []*entities.Task{}}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:         "unknown priority",
+			requestBody:  `priority=asap`,
+			wantResponse: InvalidRequestError(context.Background()),
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown sort field",
+			requestBody:  `sort=owner`,
+			wantResponse: InvalidRequestError(context.Background()),
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:         "malformed ids",
+			requestBody:  `ids=1,x`,
+			wantResponse: InvalidRequestError(context.Background()),
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "status outside the workflow",
+			requestBody: `status=7`,
+			wantResponse: ErrorResponse{
+				ErrorCode:    "INVALID_ARGUMENT",
+				ErrorMessage: "invalid status: 7 is not a status of the workflow",
+			},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), gomock.Any()).
+					Return(nil, usecase.InvalidArgumentError{Argument: "status", Reason: "7 is not a status of the workflow"})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
 		{
 			name:        "internal server error",
 			requestBody: `page_index=1&page_size=5`,
@@ -295,6 +425,8 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
 		wantStatusCode int
 		url string
+		ifMatch        string
+		wantETag       string
 	}{
 		{
 			name:        "success",
@@ -313,8 +445,14 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
 				mockUsecase.EXPECT().UpdateTask(gomock.Any(), usecase.UpdateTaskParams{
 					ID:     1,
-					Name:    "test_name",
-					Status:  task.TaskStatusIncomplete,
+					Name:    ptr("test_name"),
+					Status:  ptr(task.TaskStatusIncomplete.Ref()),
+					Description:     ptr(""),
+					Priority:        ptr(task.PriorityNone),
+					DueAt:           ptr[*time.Time](nil),
+					Tags:            ptr([]string(nil)),
+					ParentID:        ptr[*uint](nil),
+					Recurrence:      ptr[*entities.Recurrence](nil),
 				}).Return(&entities.Task{
 					ID:        1,
 					Name:      "test_name",
@@ -327,11 +465,164 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 			},
 			wantStatusCode: http.StatusOK,
 		},
+		{
+			name:        "if-match success",
+			url:         "/tasks/1",
+			ifMatch:     `"3"`,
+			wantETag:    `"4"`,
+			requestBody: `{"name": "test_name", "status": 1}`,
+			wantResponse: UpdateTaskResponse{
+				Task: &entities.Task{
+					ID:        1,
+					Name:      "test_name",
+					Status:    task.TaskStatusCompleted,
+					Version:   4,
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().UpdateTask(gomock.Any(), usecase.UpdateTaskParams{
+					ID:              1,
+					Name:            ptr("test_name"),
+					Status:          ptr(task.TaskStatusCompleted.Ref()),
+					Description:     ptr(""),
+					Priority:        ptr(task.PriorityNone),
+					DueAt:           ptr[*time.Time](nil),
+					Tags:            ptr([]string(nil)),
+					ParentID:        ptr[*uint](nil),
+					Recurrence:      ptr[*entities.Recurrence](nil),
+					ExpectedVersion: 3,
+				}).Return(&entities.Task{
+					ID:        1,
+					Name:      "test_name",
+					Status:    task.TaskStatusCompleted,
+					Version:   4,
+					CreatedAt: now,
+					UpdatedAt: now,
+				}, nil)
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:        "stale if-match",
+			url:         "/tasks/1",
+			ifMatch:     `"2"`,
+			requestBody: `{"name": "test_name", "status": 1}`,
+			wantResponse: ErrorResponse{
+				ErrorCode:    "PRECONDITION_FAILED",
+				ErrorMessage: "task 1 has been modified by another request",
+			},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().UpdateTask(gomock.Any(), usecase.UpdateTaskParams{
+					ID:              1,
+					Name:            ptr("test_name"),
+					Status:          ptr(task.TaskStatusCompleted.Ref()),
+					Description:     ptr(""),
+					Priority:        ptr(task.PriorityNone),
+					DueAt:           ptr[*time.Time](nil),
+					Tags:            ptr([]string(nil)),
+					ParentID:        ptr[*uint](nil),
+					Recurrence:      ptr[*entities.Recurrence](nil),
+					ExpectedVersion: 2,
+				}).Return(nil, usecase.PreconditionFailedError{Resource: "task", ID: 1})
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:        "weak if-match never matches",
+			url:         "/tasks/1",
+			ifMatch:     `W/"2"`,
+			requestBody: `{"name": "test_name", "status": 1}`,
+			wantResponse: ErrorResponse{
+				ErrorCode:    "PRECONDITION_FAILED",
+				ErrorMessage: "task 1 has been modified by another request",
+			},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:        "if-match list with the current version",
+			url:         "/tasks/1",
+			ifMatch:     `"3", W/"4", "4"`,
+			wantETag:    `"5"`,
+			requestBody: `{"name": "test_name", "status": 1}`,
+			wantResponse: UpdateTaskResponse{
+				Task: &entities.Task{
+					ID:        1,
+					Name:      "test_name",
+					Status:    task.TaskStatusCompleted,
+					Version:   5,
+					CreatedAt: now,
+					UpdatedAt: now,
+				},
+			},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().GetTask(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 4}, nil)
+				mockUsecase.EXPECT().UpdateTask(gomock.Any(), usecase.UpdateTaskParams{
+					ID:              1,
+					Name:            ptr("test_name"),
+					Status:          ptr(task.TaskStatusCompleted.Ref()),
+					Description:     ptr(""),
+					Priority:        ptr(task.PriorityNone),
+					DueAt:           ptr[*time.Time](nil),
+					Tags:            ptr([]string(nil)),
+					ParentID:        ptr[*uint](nil),
+					Recurrence:      ptr[*entities.Recurrence](nil),
+					ExpectedVersion: 4,
+				}).Return(&entities.Task{
+					ID:        1,
+					Name:      "test_name",
+					Status:    task.TaskStatusCompleted,
+					Version:   5,
+					CreatedAt: now,
+					UpdatedAt: now,
+				}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:        "if-match list without the current version",
+			url:         "/tasks/1",
+			ifMatch:     `"2", "3"`,
+			requestBody: `{"name": "test_name", "status": 1}`,
+			wantResponse: ErrorResponse{
+				ErrorCode:    "PRECONDITION_FAILED",
+				ErrorMessage: "task 1 has been modified by another request",
+			},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().GetTask(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 4}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:         "if-match is malformed",
+			url:          "/tasks/1",
+			ifMatch:      `"3", 4`,
+			requestBody:  `{"name": "test_name", "status": 1}`,
+			wantResponse: InvalidRequestError(context.Background()),
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
 		{
 			name:         "request body is invalid",
 			url:         "/tasks/1",
-			requestBody:  `{"name": "test_name", "status": 3}`,
-			wantResponse: InvalidRequestError(),
+			requestBody:  `{"name": "test_name", "status": true}`,
+			wantResponse: InvalidRequestError(context.Background()),
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				return usecasemock.NewMockTaskUseCase(ctrl)
 			},
@@ -341,7 +632,7 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 			name:         "task id is not a number",
 			url:         "/tasks/a",
 			requestBody:  `{"name": "test_name", "status": 1}`,
-			wantResponse: InvalidRequestError(),
+			wantResponse: InvalidRequestError(context.Background()),
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				return usecasemock.NewMockTaskUseCase(ctrl)
 			},
@@ -359,8 +650,14 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
 				mockUsecase.EXPECT().UpdateTask(gomock.Any(), usecase.UpdateTaskParams{
 					ID:     1,
-					Name:    "test_name",
-					Status:  task.TaskStatusCompleted,
+					Name:    ptr("test_name"),
+					Status:  ptr(task.TaskStatusCompleted.Ref()),
+					Description:     ptr(""),
+					Priority:        ptr(task.PriorityNone),
+					DueAt:           ptr[*time.Time](nil),
+					Tags:            ptr([]string(nil)),
+					ParentID:        ptr[*uint](nil),
+					Recurrence:      ptr[*entities.Recurrence](nil),
 				}).Return(nil, errors.New("expected error"))
 
 				return mockUsecase
@@ -379,8 +676,14 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
 				mockUsecase.EXPECT().UpdateTask(gomock.Any(), usecase.UpdateTaskParams{
 					ID:     999,
-					Name:    "test_name",
-					Status:  task.TaskStatusCompleted,
+					Name:    ptr("test_name"),
+					Status:  ptr(task.TaskStatusCompleted.Ref()),
+					Description:     ptr(""),
+					Priority:        ptr(task.PriorityNone),
+					DueAt:           ptr[*time.Time](nil),
+					Tags:            ptr([]string(nil)),
+					ParentID:        ptr[*uint](nil),
+					Recurrence:      ptr[*entities.Recurrence](nil),
 				}).Return(nil, usecase.NotFoundError{
 					Resource: "task",
 					ID:       999,
@@ -390,6 +693,23 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 			},	
 			wantStatusCode: http.StatusNotFound,
 		},
+		{
+			name:        "blocked by incomplete tasks",
+			url:         "/tasks/1",
+			requestBody: `{"name": "test_name", "status": 1}`,
+			wantResponse: ErrorResponse{
+				ErrorCode:    "BLOCKED_BY_DEPENDENCIES",
+				ErrorMessage: "task 1 is blocked by incomplete tasks [2 3]",
+			},
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).
+					Return(nil, usecase.BlockedByDependenciesError{ID: uint(1), Blockers: []uint{2, 3}})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusConflict,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -404,10 +724,16 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("PUT", tt.url, strings.NewReader(tt.requestBody))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.wantStatusCode, w.Code)
+			if tt.wantETag != "" {
+				assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+			}
 
 			wantResponseJson, err := json.Marshal(tt.wantResponse)
 			if err != nil {
@@ -419,77 +745,1141 @@ func TestTaskHandler_UpdateTask(t *testing.T) {
 	}
 }
 
-func TestTaskHandler_DeleteTask(t *testing.T) {
+func TestTaskHandler_PatchTask(t *testing.T) {
 	t.Parallel()
 
+	now := time.Now()
+	patched := &entities.Task{
+		ID:        1,
+		Name:      "patched",
+		Status:    task.TaskStatusCompleted,
+		Version:   4,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
 	tests := []struct {
 		name           string
 		url            string
-		wantStatusCode int
+		contentType    string
+		requestBody    string
+		ifMatch        string
+		wantResponse   interface{}
+		wantETag       string
 		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
 	}{
 		{
-			name:         "success",
-			url:         "/tasks/1",
+			name:         "merge patch",
+			url:          "/tasks/1",
+			contentType:  "application/merge-patch+json",
+			requestBody:  `{"name": "patched"}`,
+			wantResponse: UpdateTaskResponse{Task: patched},
+			wantETag:     `"4"`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().PatchTask(gomock.Any(), usecase.PatchTaskParams{
+					ID:     1,
+					Format: usecase.PatchFormatMergePatch,
+					Patch:  []byte(`{"name": "patched"}`),
+				}).Return(patched, nil)
+
+				return mockUsecase
+			},
 			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:         "json patch with if-match",
+			url:          "/tasks/1",
+			contentType:  "application/json-patch+json; charset=utf-8",
+			requestBody:  `[{"op": "replace", "path": "/status", "value": 1}]`,
+			ifMatch:      `"3"`,
+			wantResponse: UpdateTaskResponse{Task: patched},
+			wantETag:     `"4"`,
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
-				mockUsecase.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil)
+				mockUsecase.EXPECT().PatchTask(gomock.Any(), usecase.PatchTaskParams{
+					ID:              1,
+					Format:          usecase.PatchFormatJSONPatch,
+					Patch:           []byte(`[{"op": "replace", "path": "/status", "value": 1}]`),
+					ExpectedVersion: 3,
+				}).Return(patched, nil)
 
 				return mockUsecase
 			},
+			wantStatusCode: http.StatusOK,
 		},
 		{
-			name:         "task id is not a number",
-			url:         "/tasks/a",
+			name:         "json patch with an if-match list",
+			url:          "/tasks/1",
+			contentType:  "application/json-patch+json",
+			requestBody:  `[{"op": "replace", "path": "/status", "value": 1}]`,
+			ifMatch:      `"2", "3"`,
+			wantResponse: UpdateTaskResponse{Task: patched},
+			wantETag:     `"4"`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().GetTask(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 3}, nil)
+				mockUsecase.EXPECT().PatchTask(gomock.Any(), usecase.PatchTaskParams{
+					ID:              1,
+					Format:          usecase.PatchFormatJSONPatch,
+					Patch:           []byte(`[{"op": "replace", "path": "/status", "value": 1}]`),
+					ExpectedVersion: 3,
+				}).Return(patched, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:         "if-match is malformed",
+			url:          "/tasks/1",
+			contentType:  "application/merge-patch+json",
+			requestBody:  `{"name": "patched"}`,
+			ifMatch:      `3`,
+			wantResponse: InvalidRequestError(context.Background()),
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
 			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unsupported media type",
+			url:          "/tasks/1",
+			contentType:  "application/json",
+			requestBody:  `{"name": "patched"}`,
+			wantResponse: UnsupportedMediaTypeError(context.Background()),
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				return usecasemock.NewMockTaskUseCase(ctrl)
 			},
+			wantStatusCode: http.StatusUnsupportedMediaType,
 		},
 		{
-			name:         "internal server error",
+			name:         "task id is not a number",
+			url:          "/tasks/a",
+			contentType:  "application/merge-patch+json",
+			requestBody:  `{"name": "patched"}`,
+			wantResponse: InvalidRequestError(context.Background()),
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "test operation failed",
 			url:         "/tasks/1",
-			wantStatusCode: http.StatusInternalServerError,
+			contentType: "application/json-patch+json",
+			requestBody: `[{"op": "test", "path": "/name", "value": "other"}]`,
+			wantResponse: ErrorResponse{
+				ErrorCode:    "CONFLICT",
+				ErrorMessage: "task 1: test operation failed",
+			},
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
-				mockUsecase.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(errors.New("expected error"))
+				mockUsecase.EXPECT().PatchTask(gomock.Any(), gomock.Any()).
+					Return(nil, usecase.ConflictError{Resource: "task", ID: uint(1), Reason: "test operation failed"})
 
 				return mockUsecase
 			},
+			wantStatusCode: http.StatusConflict,
 		},
 		{
-			name:         "task not found",
-			url:         "/tasks/999",
-			wantStatusCode: http.StatusNotFound,
+			name:        "invalid patch",
+			url:         "/tasks/1",
+			contentType: "application/merge-patch+json",
+			requestBody: `{"id": 2}`,
+			wantResponse: ErrorResponse{
+				ErrorCode:    "INVALID_ARGUMENT",
+				ErrorMessage: `invalid patch: field "id" is read-only`,
+			},
 			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
 				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
-				mockUsecase.EXPECT().DeleteTask(gomock.Any(), uint(999)).Return(usecase.NotFoundError{
-					Resource: "task",
-					ID:       999,
-				})
+				mockUsecase.EXPECT().PatchTask(gomock.Any(), gomock.Any()).
+					Return(nil, usecase.InvalidArgumentError{Argument: "patch", Reason: `field "id" is read-only`})
 
 				return mockUsecase
 			},
+			wantStatusCode: http.StatusBadRequest,
 		},
-	}	
+	}
 	for _, tt := range tests {
-		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			mockUsecase := tt.getUsecaseMock(gomock.NewController(t))
-			handler := NewTaskHandler(mockUsecase)
-			
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
 			router := gin.Default()
-			router.DELETE("/tasks/:id", handler.DeleteTask)
+			router.PATCH("/tasks/:id", handler.PatchTask)
 
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("DELETE", tt.url, nil)
+			req := httptest.NewRequest(http.MethodPatch, tt.url, strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+
+			wantResponseJson, err := json.Marshal(tt.wantResponse)
+			if err != nil {
+				t.Fatalf("Failed to marshal wantResponse: %v", err)
+			}
+
+			assert.Equal(t, string(wantResponseJson), w.Body.String())
 		})
 	}
 }
+
+func TestTaskHandler_DeleteTask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		url            string
+		wantStatusCode int
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+	}{
+		{
+			name:         "success",
+			url:         "/tasks/1",
+			wantStatusCode: http.StatusOK,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil)
+
+				return mockUsecase
+			},
+		},
+		{
+			name:         "task id is not a number",
+			url:         "/tasks/a",
+			wantStatusCode: http.StatusBadRequest,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+		},
+		{
+			name:         "internal server error",
+			url:         "/tasks/1",
+			wantStatusCode: http.StatusInternalServerError,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(errors.New("expected error"))
+
+				return mockUsecase
+			},
+		},
+		{
+			name:         "task not found",
+			url:         "/tasks/999",
+			wantStatusCode: http.StatusNotFound,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().DeleteTask(gomock.Any(), uint(999)).Return(usecase.NotFoundError{
+					Resource: "task",
+					ID:       999,
+				})
+
+				return mockUsecase
+			},
+		},
+	}	
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockUsecase := tt.getUsecaseMock(gomock.NewController(t))
+			handler := NewTaskHandler(mockUsecase)
+			
+			router := gin.Default()
+			router.DELETE("/tasks/:id", handler.DeleteTask)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", tt.url, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+		})
+	}
+}
+
+func TestTaskHandler_ListTrash(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Now()
+	tests := []struct {
+		name           string
+		query          string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+	}{
+		{
+			name:  "success",
+			query: "page_index=1&page_size=5",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListTrash(gomock.Any(), usecase.ListTasksParams{
+					PageIndex: 1,
+					PageSize:  5,
+				}).Return(&usecase.ListTasksResult{
+					Tasks: []*entities.Task{{ID: 1, Name: "test_name", DeletedAt: &deletedAt}},
+					Total: 1,
+				}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:  "request is invalid",
+			query: "page_index=0&page_size=5",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.GET("/trash", handler.ListTrash)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/trash?"+tt.query, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+		})
+	}
+}
+
+func TestTaskHandler_RestoreTask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		url            string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+		wantETag       string
+	}{
+		{
+			name: "success",
+			url:  "/tasks/1/restore",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().RestoreTask(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 3}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantETag:       `"3"`,
+		},
+		{
+			name: "task id is not a number",
+			url:  "/tasks/a/restore",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "task not in trash",
+			url:  "/tasks/2/restore",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().RestoreTask(gomock.Any(), uint(2)).Return(nil, usecase.NotFoundError{
+					Resource: "trashed task",
+					ID:       uint(2),
+				})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.POST("/tasks/:id/restore", handler.RestoreTask)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", tt.url, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.Equal(t, tt.wantETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestTaskHandler_PurgeTask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		url            string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+	}{
+		{
+			name: "success",
+			url:  "/trash/1",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().PurgeTask(gomock.Any(), uint(1)).Return(nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "task not in trash",
+			url:  "/trash/2",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().PurgeTask(gomock.Any(), uint(2)).Return(usecase.NotFoundError{
+					Resource: "trashed task",
+					ID:       uint(2),
+				})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.DELETE("/trash/:id", handler.PurgeTask)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", tt.url, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+		})
+	}
+}
+
+func TestTaskHandler_ListTaskHistory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		url            string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+	}{
+		{
+			name: "success",
+			url:  "/tasks/1/history?page_index=2&page_size=5",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListTaskHistory(gomock.Any(), usecase.ListTaskHistoryParams{
+					TaskID:    1,
+					PageIndex: 2,
+					PageSize:  5,
+				}).Return(&usecase.ListTaskHistoryResult{
+					History: []*entities.TaskHistory{{ID: 1, TaskID: 1, Action: entities.HistoryActionCreate}},
+					Total:   6,
+				}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "task id is not a number",
+			url:  "/tasks/a/history",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "page size is too large",
+			url:  "/tasks/1/history?page_size=1000",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.GET("/tasks/:id/history", handler.ListTaskHistory)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.url, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+		})
+	}
+}
+
+func TestTaskHandler_ListSubtasks(t *testing.T) {
+	t.Parallel()
+
+	parentID := uint(1)
+
+	tests := []struct {
+		name           string
+		url            string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "success",
+			url:  "/tasks/1/subtasks",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListSubtasks(gomock.Any(), uint(1)).Return(&usecase.ListSubtasksResult{
+					Subtasks: []*entities.Task{{ID: 2, Name: "child", Tags: []string{}, ParentID: &parentID}},
+					Progress: entities.TaskProgress{Completed: 1, Total: 3},
+				}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{"subtasks":[{"id":2,"name":"child","list_id":0,"description":"","status":0,"priority":"none","tags":[],"parent_id":1,` +
+				`"version":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"progress":{"completed":1,"total":3}}`,
+		},
+		{
+			name: "task id is not a number",
+			url:  "/tasks/a/subtasks",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+		{
+			name: "task not found",
+			url:  "/tasks/9/subtasks",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListSubtasks(gomock.Any(), uint(9)).Return(nil, usecase.NotFoundError{Resource: "task", ID: uint(9)})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error_code":"NOT_FOUND","error_message":"task 9 not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.GET("/tasks/:id/subtasks", handler.ListSubtasks)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.url, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestTaskHandler_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:   "add",
+			method: "POST",
+			url:    "/tasks/1/dependencies",
+			body:   `{"blocker_id":2}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().AddDependency(gomock.Any(), usecase.DependencyParams{TaskID: 1, BlockerID: 2}).
+					Return(&entities.Task{ID: 1, Name: "task", Tags: []string{}, BlockedBy: []uint{2}, Version: 3}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{"task":{"id":1,"name":"task","list_id":0,"description":"","status":0,"priority":"none","tags":[],"blocked_by":[2],` +
+				`"version":3,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:   "add without blocker",
+			method: "POST",
+			url:    "/tasks/1/dependencies",
+			body:   `{}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+		{
+			name:   "add closing a cycle",
+			method: "POST",
+			url:    "/tasks/1/dependencies",
+			body:   `{"blocker_id":2}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().AddDependency(gomock.Any(), gomock.Any()).Return(nil, usecase.ConflictError{
+					Resource: "task", ID: uint(1), Reason: "depending on task 2 would create a dependency cycle",
+				})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusConflict,
+			wantBody:       `{"error_code":"CONFLICT","error_message":"task 1: depending on task 2 would create a dependency cycle"}`,
+		},
+		{
+			name:   "remove",
+			method: "DELETE",
+			url:    "/tasks/1/dependencies?blocker_id=2",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().RemoveDependency(gomock.Any(), usecase.DependencyParams{TaskID: 1, BlockerID: 2}).
+					Return(&entities.Task{ID: 1, Name: "task", Tags: []string{}, Version: 4}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{"task":{"id":1,"name":"task","list_id":0,"description":"","status":0,"priority":"none","tags":[],` +
+				`"version":4,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:   "remove a missing dependency",
+			method: "DELETE",
+			url:    "/tasks/1/dependencies?blocker_id=5",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().RemoveDependency(gomock.Any(), gomock.Any()).
+					Return(nil, usecase.NotFoundError{Resource: "dependency of task 1 on task", ID: uint(5)})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error_code":"NOT_FOUND","error_message":"dependency of task 1 on task 5 not found"}`,
+		},
+		{
+			name:   "task id is not a number",
+			method: "DELETE",
+			url:    "/tasks/a/dependencies?blocker_id=2",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.POST("/tasks/:id/dependencies", handler.AddDependency)
+			router.DELETE("/tasks/:id/dependencies", handler.RemoveDependency)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestTaskHandler_ListOccurrences(t *testing.T) {
+	t.Parallel()
+
+	taipei := time.FixedZone("", 8*60*60)
+
+	tests := []struct {
+		name           string
+		url            string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "success",
+			url:  "/tasks/1/occurrences?count=2",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListOccurrences(gomock.Any(), usecase.ListOccurrencesParams{ID: 1, Count: 2}).Return([]time.Time{
+					time.Date(2024, 1, 16, 9, 0, 0, 0, taipei),
+					time.Date(2024, 1, 17, 9, 0, 0, 0, taipei),
+				}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"occurrences":["2024-01-16T09:00:00+08:00","2024-01-17T09:00:00+08:00"]}`,
+		},
+		{
+			name: "default count",
+			url:  "/tasks/1/occurrences",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListOccurrences(gomock.Any(), usecase.ListOccurrencesParams{ID: 1, Count: 5}).Return([]time.Time{}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"occurrences":[]}`,
+		},
+		{
+			name: "count out of range",
+			url:  "/tasks/1/occurrences?count=101",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+		{
+			name: "task not found",
+			url:  "/tasks/9/occurrences",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListOccurrences(gomock.Any(), gomock.Any()).Return(nil, usecase.NotFoundError{Resource: "task", ID: uint(9)})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error_code":"NOT_FOUND","error_message":"task 9 not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.GET("/tasks/:id/occurrences", handler.ListOccurrences)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.url, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestTaskHandler_ListTasks_LinkHeader(t *testing.T) {
+	t.Parallel()
+
+	mockUsecase := usecasemock.NewMockTaskUseCase(gomock.NewController(t))
+	mockUsecase.EXPECT().ListTasks(gomock.Any(), usecase.ListTasksParams{
+		PageIndex: 2,
+		PageSize:  5,
+		Filter:    repository.TaskFilter{NameContains: "milk"},
+	}).Return(&usecase.ListTasksResult{
+		Tasks:      []*entities.Task{},
+		Total:      20,
+		NextCursor: "bmV4dA",
+		PrevCursor: "cHJldg",
+	}, nil)
+
+	router := gin.Default()
+	router.GET("/api/v1/tasks", NewTaskHandler(mockUsecase).ListTasks)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/tasks?page_index=2&page_size=5&name=milk", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t,
+		`</api/v1/tasks?cursor=bmV4dA&name=milk&page_size=5>; rel="next", </api/v1/tasks?cursor=cHJldg&name=milk&page_size=5>; rel="prev"`,
+		w.Header().Get("Link"),
+	)
+	assert.JSONEq(t, `{"tasks":[],"total":20,"next_cursor":"bmV4dA","prev_cursor":"cHJldg"}`, w.Body.String())
+}
+
+func TestTaskHandler_SearchTasks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		url            string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+	}{
+		{
+			name: "success",
+			url:  "/tasks/search?q=buy%20mi&page_size=5",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().SearchTasks(gomock.Any(), usecase.SearchTasksParams{
+					Query:     "buy mi",
+					PageIndex: 1,
+					PageSize:  5,
+				}).Return(&usecase.SearchTasksResult{
+					Hits: []*entities.TaskSearchHit{{
+						Task:      &entities.Task{ID: 1, Name: "buy milk"},
+						Score:     1,
+						Highlight: "<mark>buy</mark> <mark>milk</mark>",
+					}},
+					Total: 1,
+				}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "query is missing",
+			url:  "/tasks/search",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "query has no word",
+			url:  "/tasks/search?q=%21%21",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().SearchTasks(gomock.Any(), gomock.Any()).
+					Return(nil, usecase.InvalidArgumentError{Argument: "q", Reason: "query has no word to search for"})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.GET("/tasks/search", handler.SearchTasks)
+			router.GET("/tasks/:id/history", handler.ListTaskHistory)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.url, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+		})
+	}
+}
+
+func TestTaskHandler_BatchTasks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+		wantResults    []BatchOperationResponse
+	}{
+		{
+			name: "success",
+			url:  "/api/v1/tasks:batch",
+			body: `{"atomic":true,"operations":[{"op":"create","name":"new"},{"op":"update","id":1,"name":"renamed","status":1,"version":3},{"op":"delete","id":9}]}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().BatchTasks(gomock.Any(), usecase.BatchTasksParams{
+					Operations: []usecase.BatchOperation{
+						{Type: usecase.BatchOperationCreate, Name: "new"},
+						{Type: usecase.BatchOperationUpdate, ID: 1, Name: "renamed", Status: task.TaskStatusCompleted.Ref(), ExpectedVersion: 3},
+						{Type: usecase.BatchOperationDelete, ID: 9},
+					},
+					Atomic: true,
+				}).Return(&usecase.BatchTasksResult{Results: []usecase.BatchOperationResult{
+					{Err: usecase.AbortedError{Index: 2}},
+					{Err: usecase.AbortedError{Index: 2}},
+					{Err: usecase.NotFoundError{Resource: "task", ID: uint(9)}},
+				}}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantResults: []BatchOperationResponse{
+				{Status: http.StatusConflict, Error: &ErrorResponse{ErrorCode: "ABORTED", ErrorMessage: "batch aborted by the failure of operation 2"}},
+				{Status: http.StatusConflict, Error: &ErrorResponse{ErrorCode: "ABORTED", ErrorMessage: "batch aborted by the failure of operation 2"}},
+				{Status: http.StatusNotFound, Error: &ErrorResponse{ErrorCode: "NOT_FOUND", ErrorMessage: "task 9 not found"}},
+			},
+		},
+		{
+			name: "create without name",
+			url:  "/api/v1/tasks:batch",
+			body: `{"operations":[{"op":"create"}]}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "delete without id",
+			url:  "/api/v1/tasks:batch",
+			body: `{"operations":[{"op":"delete"}]}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown op",
+			url:  "/api/v1/tasks:batch",
+			body: `{"operations":[{"op":"purge","id":1}]}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "no operations",
+			url:  "/api/v1/tasks:batch",
+			body: `{"operations":[]}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown custom method",
+			url:  "/api/v1/tasks:import",
+			body: `{}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router := gin.Default()
+			router.Use(middleware.GinNoAuth())
+			RegisterTaskRoutes(router, tt.getUsecaseMock(gomock.NewController(t)))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+
+			if tt.wantResults != nil {
+				var resp BatchTasksResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantResults, resp.Results)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetTask(t *testing.T) {
+	t.Parallel()
+
+	updatedAt := time.Date(2024, 5, 1, 10, 30, 0, 500, time.UTC)
+	lastModified := "Wed, 01 May 2024 10:30:00 GMT"
+
+	tests := []struct {
+		name           string
+		url            string
+		header         map[string]string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+	}{
+		{
+			name:           "success",
+			url:            "/tasks/1",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "if-none-match lists the etag",
+			url:            "/tasks/1",
+			header:         map[string]string{"If-None-Match": `"2", W/"3"`},
+			wantStatusCode: http.StatusNotModified,
+		},
+		{
+			name:           "if-none-match with another etag",
+			url:            "/tasks/1",
+			header:         map[string]string{"If-None-Match": `"2"`, "If-Modified-Since": lastModified},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "not modified since",
+			url:            "/tasks/1",
+			header:         map[string]string{"If-Modified-Since": lastModified},
+			wantStatusCode: http.StatusNotModified,
+		},
+		{
+			name:           "modified since",
+			url:            "/tasks/1",
+			header:         map[string]string{"If-Modified-Since": "Wed, 01 May 2024 10:29:59 GMT"},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "not found",
+			url:  "/tasks/1",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().GetTask(gomock.Any(), uint(1)).Return(nil, usecase.NotFoundError{Resource: "task", ID: uint(1)})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "task id is not a number",
+			url:  "/tasks/a",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			var mockUsecase usecase.TaskUseCase
+			if tt.getUsecaseMock != nil {
+				mockUsecase = tt.getUsecaseMock(ctrl)
+			} else {
+				m := usecasemock.NewMockTaskUseCase(ctrl)
+				m.EXPECT().GetTask(gomock.Any(), uint(1)).
+					Return(&entities.Task{ID: 1, Name: "task", Version: 3, UpdatedAt: updatedAt}, nil)
+				mockUsecase = m
+			}
+
+			handler := NewTaskHandler(mockUsecase)
+
+			router := gin.Default()
+			router.GET("/tasks/:id", handler.GetTask)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.url, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+
+			if tt.wantStatusCode == http.StatusOK || tt.wantStatusCode == http.StatusNotModified {
+				assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+				assert.Equal(t, lastModified, w.Header().Get("Last-Modified"))
+			}
+
+			if tt.wantStatusCode == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetWorkflow(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+	mockUsecase.EXPECT().GetWorkflow(gomock.Any()).Return(&entities.Workflow{
+		Initial: entities.WorkflowStatus{Code: task.TaskStatusIncomplete, Name: "incomplete"},
+		Statuses: []entities.WorkflowStatus{
+			{Code: task.TaskStatusIncomplete, Name: "incomplete"},
+			{Code: task.TaskStatusCompleted, Name: "completed"},
+		},
+		Transitions: []entities.WorkflowTransition{
+			{From: task.TaskStatusIncomplete, To: []task.TaskStatus{task.TaskStatusCompleted}},
+			{From: task.TaskStatusCompleted, To: []task.TaskStatus{}},
+		},
+	}, nil)
+
+	handler := NewTaskHandler(mockUsecase)
+
+	router := gin.Default()
+	router.GET("/workflow", handler.GetWorkflow)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/workflow", nil)
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"workflow":{
+		"initial":{"code":0,"name":"incomplete"},
+		"statuses":[{"code":0,"name":"incomplete"},{"code":1,"name":"completed"}],
+		"transitions":[{"from":0,"to":[1]},{"from":1,"to":[]}]
+	}}`, w.Body.String())
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/url"
+	"strings"
+)
+
+// formatLinkHeader returns an RFC 8288 Link header pointing at the next and previous pages,
+// or an empty string when there is neither. The links keep the filters of the request URL
+// and replace its position with the cursor.
+func formatLinkHeader(requestURL *url.URL, nextCursor, prevCursor string) string {
+	links := make([]string, 0, 2) //nolint:mnd
+
+	for _, link := range []struct {
+		rel    string
+		cursor string
+	}{
+		{"next", nextCursor},
+		{"prev", prevCursor},
+	} {
+		if link.cursor == "" {
+			continue
+		}
+
+		query := requestURL.Query()
+		query.Del("page_index")
+		query.Set("cursor", link.cursor)
+
+		target := url.URL{Path: requestURL.Path, RawQuery: query.Encode()}
+		links = append(links, "<"+target.String()+`>; rel="`+link.rel+`"`)
+	}
+
+	return strings.Join(links, ", ")
+}
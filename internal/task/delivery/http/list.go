@@ -0,0 +1,285 @@
+package http
+
+import (
+	"fmt"
+	"ggltask/internal/task/domain/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// @Summary Create list
+// @Description Create a new list of tasks
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param request body CreateListRequest true "Create list request"
+// @Success 200 {object} CreateListResponse "Create list response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/lists [post]
+func (h *TaskHandler) CreateList(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	list, err := h.taskUsecase.CreateList(ctx, usecase.CreateListParams{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", req),
+			"error":   err,
+		}).Msg("list create error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateListResponse{
+		List: list,
+	})
+}
+
+// @Summary List lists
+// @Description List the lists of tasks by page, ordered by id
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param request query ListListsRequest true "List lists request"
+// @Success 200 {object} ListListsResponse "List lists response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/lists [get]
+func (h *TaskHandler) ListLists(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req ListListsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	result, err := h.taskUsecase.ListLists(ctx, usecase.ListListsParams{
+		PageIndex: req.PageIndex,
+		PageSize:  req.PageSize,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%+v", req),
+			"error":   err,
+		}).Msg("list list error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, ListListsResponse{
+		Lists: result.Lists,
+		Total: result.Total,
+	})
+}
+
+// @Summary Get list
+// @Description Get a list by id
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID"
+// @Success 200 {object} GetListResponse "Get list response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/lists/{id} [get]
+func (h *TaskHandler) GetList(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	list, err := h.taskUsecase.GetList(ctx, uint(idUint))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": id,
+			"error":   err,
+		}).Msg("list get error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, GetListResponse{
+		List: list,
+	})
+}
+
+// @Summary Update list
+// @Description Replace the name and description of a list
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID"
+// @Param request body UpdateListRequest true "Update list request"
+// @Success 200 {object} UpdateListResponse "Update list response"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/lists/{id} [put]
+func (h *TaskHandler) UpdateList(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	var req UpdateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	list, err := h.taskUsecase.UpdateList(ctx, usecase.UpdateListParams{
+		ID:          uint(idUint),
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%s %+v", id, req),
+			"error":   err,
+		}).Msg("list update error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, UpdateListResponse{
+		List: list,
+	})
+}
+
+// @Summary Delete list
+// @Description Delete a list. Its tasks are archived into the default list or, with tasks=cascade, moved to the trash
+// @Description along with their subtasks. Its trashed tasks go to the default list when restored. The default list cannot be deleted.
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID"
+// @Param request query DeleteListRequest true "Delete list request"
+// @Success 200 {object} nil "empty result"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "not found"
+// @Failure 409 {object} ErrorResponse "the list is the default list, or the workflow forbids archiving one of its tasks"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/lists/{id} [delete]
+func (h *TaskHandler) DeleteList(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	var req DeleteListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(ctx))
+
+		return
+	}
+
+	if err := h.taskUsecase.DeleteList(ctx, usecase.DeleteListParams{ID: uint(idUint), Tasks: req.Tasks}); err != nil {
+		zerolog.Ctx(ctx).Error().Fields(map[string]any{
+			"payload": fmt.Sprintf("%s %+v", id, req),
+			"error":   err,
+		}).Msg("list delete error")
+
+		c.JSON(UseCaesErrorToErrorResp(ctx, err))
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// @Summary List tasks of list
+// @Description List the tasks of a list, with the filters, sorting and paging of GET /tasks
+// @Tags list
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID"
+// @Param request query ListTasksRequest true "List tasks request"
+// @Success 200 {object} ListTasksResponse "List tasks response"
+// @Header 200 {string} Link "RFC 8288 links to the next and previous pages"
+// @Failure 400 {object} ErrorResponse "invalid request"
+// @Failure 401 {object} ErrorResponse "not authenticated"
+// @Failure 403 {object} ErrorResponse "missing the scope of the route"
+// @Failure 404 {object} ErrorResponse "list not found"
+// @Failure 429 {object} ErrorResponse "rate limited"
+// @Failure 500 {object} ErrorResponse "internal error"
+// @Security BearerAuth
+// @Router /api/v1/lists/{id}/tasks [get]
+func (h *TaskHandler) ListListTasks(c *gin.Context) {
+	idUint, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || idUint == 0 {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(c.Request.Context()))
+
+		return
+	}
+
+	var req ListTasksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, InvalidRequestError(c.Request.Context()))
+
+		return
+	}
+
+	req.ListID = uint(idUint)
+
+	h.listTasks(c, req)
+}
@@ -0,0 +1,260 @@
+package http
+
+import (
+	"errors"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/usecasemock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskHandler_Lists(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	list := &entities.List{ID: 2, Name: "work", Description: "office", CreatedAt: createdAt, UpdatedAt: createdAt}
+	listJSON := `{"id":2,"name":"work","description":"office","created_at":"2030-01-02T09:00:00Z","updated_at":"2030-01-02T09:00:00Z"}`
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		getUsecaseMock func(ctrl *gomock.Controller) usecase.TaskUseCase
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:   "create",
+			method: "POST",
+			url:    "/lists",
+			body:   `{"name":"work","description":"office"}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().CreateList(gomock.Any(), usecase.CreateListParams{Name: "work", Description: "office"}).
+					Return(list, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"list":` + listJSON + `}`,
+		},
+		{
+			name:   "create without name",
+			method: "POST",
+			url:    "/lists",
+			body:   `{"description":"office"}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+		{
+			name:   "list",
+			method: "GET",
+			url:    "/lists?page_size=5",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListLists(gomock.Any(), usecase.ListListsParams{PageIndex: 1, PageSize: 5}).
+					Return(&usecase.ListListsResult{Lists: []*entities.List{list}, Total: 2}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"lists":[` + listJSON + `],"total":2}`,
+		},
+		{
+			name:   "get",
+			method: "GET",
+			url:    "/lists/2",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().GetList(gomock.Any(), uint(2)).Return(list, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"list":` + listJSON + `}`,
+		},
+		{
+			name:   "get unknown list",
+			method: "GET",
+			url:    "/lists/9",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().GetList(gomock.Any(), uint(9)).Return(nil, usecase.NotFoundError{Resource: "list", ID: uint(9)})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error_code":"NOT_FOUND","error_message":"list 9 not found"}`,
+		},
+		{
+			name:   "update",
+			method: "PUT",
+			url:    "/lists/2",
+			body:   `{"name":"work","description":"office"}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().UpdateList(gomock.Any(), usecase.UpdateListParams{ID: 2, Name: "work", Description: "office"}).
+					Return(list, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"list":` + listJSON + `}`,
+		},
+		{
+			name:   "update with a list id not a number",
+			method: "PUT",
+			url:    "/lists/a",
+			body:   `{"name":"work"}`,
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+		{
+			name:   "delete archives the tasks by default",
+			method: "DELETE",
+			url:    "/lists/2",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().DeleteList(gomock.Any(), usecase.DeleteListParams{ID: 2, Tasks: usecase.ListTasksPolicyArchive}).
+					Return(nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{}`,
+		},
+		{
+			name:   "delete with cascade",
+			method: "DELETE",
+			url:    "/lists/2?tasks=cascade",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().DeleteList(gomock.Any(), usecase.DeleteListParams{ID: 2, Tasks: usecase.ListTasksPolicyCascade}).
+					Return(nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{}`,
+		},
+		{
+			name:   "delete with an unknown policy",
+			method: "DELETE",
+			url:    "/lists/2?tasks=keep",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+		{
+			name:   "delete the default list",
+			method: "DELETE",
+			url:    "/lists/1",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().DeleteList(gomock.Any(), gomock.Any()).Return(usecase.ConflictError{
+					Resource: "list", ID: uint(1), Reason: "the default list cannot be deleted",
+				})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusConflict,
+			wantBody:       `{"error_code":"CONFLICT","error_message":"list 1: the default list cannot be deleted"}`,
+		},
+		{
+			name:   "delete failed",
+			method: "DELETE",
+			url:    "/lists/2",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().DeleteList(gomock.Any(), gomock.Any()).Return(errors.New("expected error"))
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"error_code":"INTERNAL_SERVER_ERROR","error_message":"Internal Server Error"}`,
+		},
+		{
+			name:   "list tasks of a list",
+			method: "GET",
+			url:    "/lists/2/tasks?list_id=3&name=mail",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), usecase.ListTasksParams{
+					PageIndex: 1,
+					PageSize:  10,
+					Filter:    repository.TaskFilter{ListID: 2, NameContains: "mail"},
+				}).Return(&usecase.ListTasksResult{Tasks: []*entities.Task{}, Total: 0}, nil)
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"tasks":[],"total":0}`,
+		},
+		{
+			name:   "list tasks of an unknown list",
+			method: "GET",
+			url:    "/lists/9/tasks",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				mockUsecase := usecasemock.NewMockTaskUseCase(ctrl)
+				mockUsecase.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Return(nil, usecase.NotFoundError{Resource: "list", ID: uint(9)})
+
+				return mockUsecase
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       `{"error_code":"NOT_FOUND","error_message":"list 9 not found"}`,
+		},
+		{
+			name:   "list tasks of list 0",
+			method: "GET",
+			url:    "/lists/0/tasks",
+			getUsecaseMock: func(ctrl *gomock.Controller) usecase.TaskUseCase {
+				return usecasemock.NewMockTaskUseCase(ctrl)
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `{"error_code":"INVALID_REQUEST","error_message":"Invalid Request"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTaskHandler(tt.getUsecaseMock(gomock.NewController(t)))
+
+			router := gin.Default()
+			router.POST("/lists", handler.CreateList)
+			router.GET("/lists", handler.ListLists)
+			router.GET("/lists/:id", handler.GetList)
+			router.PUT("/lists/:id", handler.UpdateList)
+			router.DELETE("/lists/:id", handler.DeleteList)
+			router.GET("/lists/:id/tasks", handler.ListListTasks)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatusCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
@@ -1,17 +1,366 @@
 package http
 
-import "ggltask/internal/task"
+import (
+	"errors"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidQuery = errors.New("invalid query")
 
 type CreateTaskRequest struct {
-	Name string `json:"name" binding:"required,max=50"`
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description" binding:"max=10000"`
+	// Priority is one of none, low, medium, high and urgent.
+	Priority task.Priority `json:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	// DueAt is in RFC 3339; its time zone offset is kept.
+	DueAt *time.Time `json:"due_at"`
+	Tags  []string   `json:"tags" binding:"max=20"`
+	// ParentID makes the task a subtask of another one.
+	ParentID *uint `json:"parent_id"`
+	// Recurrence repeats the task from its due date, which is then required.
+	Recurrence *entities.Recurrence `json:"recurrence"`
+	// ListID is the list of the task; by default a subtask goes to the list of its parent
+	// and a top-level task to the default list.
+	ListID uint `json:"list_id"`
+}
+
+func (r CreateTaskRequest) params() usecase.CreateTaskParams {
+	return usecase.CreateTaskParams{
+		Name:        r.Name,
+		Description: r.Description,
+		Priority:    r.Priority,
+		DueAt:       r.DueAt,
+		Tags:        r.Tags,
+		ParentID:    r.ParentID,
+		Recurrence:  r.Recurrence,
+		ListID:      r.ListID,
+	}
 }
 
+// UpdateTaskRequest replaces every field of a task; an omitted due_at removes the due date,
+// an omitted parent_id makes the task top-level and an omitted recurrence stops it recurring.
+// An omitted list_id keeps the task in its list, another one moves it there.
+// The status, by name or by code, must be one the workflow lets the task move to, see GET /workflow;
+// an omitted status is the initial one.
 type UpdateTaskRequest struct {
-	Name   string          `json:"name" binding:"required,max=50"`
-	Status task.TaskStatus `json:"status" binding:"oneof=0 1"`
+	Name        string               `json:"name" binding:"required,max=50"`
+	Description string               `json:"description" binding:"max=10000"`
+	Status      task.StatusRef       `json:"status" swaggertype:"string" example:"in_progress"`
+	Priority    task.Priority        `json:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	DueAt       *time.Time           `json:"due_at"`
+	Tags        []string             `json:"tags" binding:"max=20"`
+	ParentID    *uint                `json:"parent_id"`
+	Recurrence  *entities.Recurrence `json:"recurrence"`
+	ListID      uint                 `json:"list_id"`
+}
+
+func (r UpdateTaskRequest) params(id uint, expectedVersion uint) usecase.UpdateTaskParams {
+	var listID *uint
+	if r.ListID != 0 {
+		listID = &r.ListID
+	}
+
+	return usecase.UpdateTaskParams{
+		ID:              id,
+		Name:            &r.Name,
+		Description:     &r.Description,
+		Status:          &r.Status,
+		Priority:        &r.Priority,
+		DueAt:           &r.DueAt,
+		Tags:            &r.Tags,
+		ParentID:        &r.ParentID,
+		Recurrence:      &r.Recurrence,
+		ListID:          listID,
+		ExpectedVersion: expectedVersion,
+	}
+}
+
+// AddDependencyRequest makes the task depend on the blocker: it cannot be completed before the blocker is.
+type AddDependencyRequest struct {
+	BlockerID uint `json:"blocker_id" binding:"required"`
+}
+
+type RemoveDependencyRequest struct {
+	BlockerID uint `form:"blocker_id" binding:"required"`
 }
 
 type ListTasksRequest struct {
 	PageIndex int `form:"page_index,default=1" binding:"required,gte=1"`
 	PageSize  int `form:"page_size,default=10" binding:"required,gte=1,lte=100"`
+	// Status is a status of the workflow, by name or by code.
+	Status task.StatusRef `form:"status"`
+	// ListID lists the tasks of a list, as GET /lists/{id}/tasks does.
+	ListID uint `form:"list_id"`
+	// Name matches a case-insensitive substring of the task name.
+	Name string `form:"name" binding:"max=50"`
+	// IDs is a comma-separated list of task ids, e.g. `1,2,3`.
+	IDs string `form:"ids"`
+	// The since bounds are inclusive, the before bounds exclusive, all in RFC 3339.
+	CreatedSince  time.Time `form:"created_since" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedSince  time.Time `form:"updated_since" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedBefore time.Time `form:"updated_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueSince      time.Time `form:"due_since" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueBefore     time.Time `form:"due_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Overdue lists the incomplete tasks whose due date has passed.
+	Overdue bool `form:"overdue"`
+	// Ready lists the incomplete tasks that no incomplete task blocks.
+	Ready bool `form:"ready"`
+	// Priority is a priority, optionally prefixed with a comparison among `>=`, `>`, `<=` and `<`, e.g. `>=high`.
+	Priority string `form:"priority"`
+	// Tag lists the tasks having every given tag; repeat it for several tags, e.g. `tag=ops&tag=db`.
+	Tag []string `form:"tag" binding:"max=20"`
+	// Sort is a comma-separated list of id, name, status, priority, created_at and updated_at,
+	// each optionally prefixed with `-` for descending order, e.g. `created_at,-updated_at`.
+	Sort string `form:"sort"`
+	// Cursor is the next_cursor or prev_cursor of an earlier response; it takes precedence over page_index.
+	Cursor string `form:"cursor"`
+}
+
+// params converts the request into usecase parameters, parsing the id list and the sort keys.
+func (r ListTasksRequest) params() (usecase.ListTasksParams, error) {
+	ids, err := parseIDs(r.IDs)
+	if err != nil {
+		return usecase.ListTasksParams{}, err
+	}
+
+	sort, err := parseSort(r.Sort)
+	if err != nil {
+		return usecase.ListTasksParams{}, err
+	}
+
+	minPriority, maxPriority, err := parsePriorityFilter(r.Priority)
+	if err != nil {
+		return usecase.ListTasksParams{}, err
+	}
+
+	var status *task.StatusRef
+	if r.Status != "" {
+		status = &r.Status
+	}
+
+	return usecase.ListTasksParams{
+		PageIndex: r.PageIndex,
+		PageSize:  r.PageSize,
+		Filter: repository.TaskFilter{
+			ListID:        r.ListID,
+			NameContains:  r.Name,
+			CreatedSince:  r.CreatedSince,
+			CreatedBefore: r.CreatedBefore,
+			UpdatedSince:  r.UpdatedSince,
+			UpdatedBefore: r.UpdatedBefore,
+			IDs:           ids,
+			MinPriority:   minPriority,
+			MaxPriority:   maxPriority,
+			Tags:          normalizeTagFilter(r.Tag),
+			DueSince:      r.DueSince,
+			DueBefore:     r.DueBefore,
+			Overdue:       r.Overdue,
+			Ready:         r.Ready,
+		},
+		Sort:   sort,
+		Cursor: r.Cursor,
+		Status: status,
+	}, nil
+}
+
+func parseIDs(s string) ([]uint, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(s, ",")
+	ids := make([]uint, 0, len(parts))
+
+	for _, part := range parts {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, errInvalidQuery
+		}
+
+		ids = append(ids, uint(id))
+	}
+
+	return ids, nil
+}
+
+// parsePriorityFilter parses a priority filter such as `high` or `>=high` into inclusive bounds.
+func parsePriorityFilter(s string) (*task.Priority, *task.Priority, error) {
+	if s == "" {
+		return nil, nil, nil
+	}
+
+	// the two-character operators go first, so `>=` is not read as `>`; a bare priority means `=`
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		name, ok := strings.CutPrefix(s, op)
+		if !ok && op != "=" {
+			continue
+		}
+
+		priority, valid := task.ParsePriority(strings.TrimSpace(name))
+		if !valid {
+			return nil, nil, errInvalidQuery
+		}
+
+		switch op {
+		case ">=":
+			return &priority, nil, nil
+		case "<=":
+			return nil, &priority, nil
+		case ">":
+			if priority == task.PriorityUrgent {
+				return nil, nil, errInvalidQuery
+			}
+
+			priority++
+
+			return &priority, nil, nil
+		case "<":
+			if priority == task.PriorityNone {
+				return nil, nil, errInvalidQuery
+			}
+
+			priority--
+
+			return nil, &priority, nil
+		default:
+			return &priority, &priority, nil
+		}
+	}
+
+	return nil, nil, errInvalidQuery
+}
+
+// normalizeTagFilter lowercases the filtered tags, as tags are stored lowercase.
+func normalizeTagFilter(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(tag)))
+	}
+
+	return normalized
+}
+
+func parseSort(s string) ([]repository.SortKey, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(s, ",")
+	keys := make([]repository.SortKey, 0, len(parts))
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		key := repository.SortKey{Field: repository.SortField(strings.TrimPrefix(part, "-")), Desc: strings.HasPrefix(part, "-")}
+
+		if !key.Field.Valid() {
+			return nil, errInvalidQuery
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+type ListTrashRequest struct {
+	PageIndex int `form:"page_index,default=1" binding:"required,gte=1"`
+	PageSize  int `form:"page_size,default=10" binding:"required,gte=1,lte=100"`
+}
+
+type ListTaskHistoryRequest struct {
+	PageIndex int `form:"page_index,default=1" binding:"required,gte=1"`
+	PageSize  int `form:"page_size,default=10" binding:"required,gte=1,lte=100"`
+}
+
+type ListOccurrencesRequest struct {
+	Count int `form:"count,default=5" binding:"required,gte=1,lte=100"`
+}
+
+type SearchTasksRequest struct {
+	Query     string `form:"q" binding:"required,max=200"`
+	PageIndex int    `form:"page_index,default=1" binding:"required,gte=1"`
+	PageSize  int    `form:"page_size,default=10" binding:"required,gte=1,lte=100"`
+}
+
+type BatchTasksRequest struct {
+	// Atomic applies every operation or, as soon as one fails, none of them.
+	// Otherwise each operation is applied on its own.
+	Atomic     bool                    `json:"atomic"`
+	Operations []BatchOperationRequest `json:"operations" binding:"required,min=1,max=1000,dive"`
+}
+
+// BatchOperationRequest is a create, update or delete operation. A create takes the fields of
+// POST /tasks, an update the same fields as PUT /tasks/{id}, and a delete only the id.
+type BatchOperationRequest struct {
+	Op          usecase.BatchOperationType `json:"op" binding:"required,oneof=create update delete"`
+	ID          uint                       `json:"id" binding:"required_unless=Op create"`
+	Name        string                     `json:"name" binding:"required_unless=Op delete,max=50"`
+	Description string                     `json:"description" binding:"max=10000"`
+	Status      task.StatusRef             `json:"status" swaggertype:"string" example:"in_progress"`
+	Priority    task.Priority              `json:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	DueAt       *time.Time                 `json:"due_at"`
+	Tags        []string                   `json:"tags" binding:"max=20"`
+	ParentID    *uint                      `json:"parent_id"`
+	Recurrence  *entities.Recurrence       `json:"recurrence"`
+	ListID      uint                       `json:"list_id"`
+	// Version makes an update conditional on the task version, as the If-Match header of PUT /tasks/{id} does.
+	Version uint `json:"version"`
+}
+
+func (r BatchTasksRequest) params() usecase.BatchTasksParams {
+	ops := make([]usecase.BatchOperation, 0, len(r.Operations))
+	for _, op := range r.Operations {
+		ops = append(ops, usecase.BatchOperation{
+			Type:            op.Op,
+			ID:              op.ID,
+			Name:            op.Name,
+			Description:     op.Description,
+			Status:          op.Status,
+			Priority:        op.Priority,
+			DueAt:           op.DueAt,
+			Tags:            op.Tags,
+			ParentID:        op.ParentID,
+			Recurrence:      op.Recurrence,
+			ListID:          op.ListID,
+			ExpectedVersion: op.Version,
+		})
+	}
+
+	return usecase.BatchTasksParams{
+		Operations: ops,
+		Atomic:     r.Atomic,
+	}
+}
+
+type CreateListRequest struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description" binding:"max=10000"`
+}
+
+// UpdateListRequest replaces the name and description of a list.
+type UpdateListRequest struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description" binding:"max=10000"`
+}
+
+type ListListsRequest struct {
+	PageIndex int `form:"page_index,default=1" binding:"required,gte=1"`
+	PageSize  int `form:"page_size,default=10" binding:"required,gte=1,lte=100"`
+}
+
+type DeleteListRequest struct {
+	// Tasks is what becomes of the tasks of the list: archive moves them to the default list and archives them,
+	// cascade moves them to the trash.
+	Tasks usecase.ListTasksPolicy `form:"tasks,default=archive" binding:"oneof=archive cascade" enums:"archive,cascade"`
 }
@@ -1,16 +1,81 @@
 package http
 
-import "ggltask/internal/task/domain/entities"
+import (
+	"ggltask/internal/task/domain/entities"
+	"time"
+)
 
 type ListTasksResponse struct {
-	Tasks []*entities.Task `json:"tasks"`
-	Total int              `json:"total"`
+	Tasks      []*entities.Task `json:"tasks"`
+	Total      int              `json:"total"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	PrevCursor string           `json:"prev_cursor,omitempty"`
 }
 
 type CreateTaskResponse struct {
 	Task *entities.Task `json:"task"`
 }
 
+type GetTaskResponse struct {
+	Task *entities.Task `json:"task"`
+}
+
 type UpdateTaskResponse struct {
 	Task *entities.Task `json:"task"`
 }
+
+type RestoreTaskResponse struct {
+	Task *entities.Task `json:"task"`
+}
+
+type ListTaskHistoryResponse struct {
+	History []*entities.TaskHistory `json:"history"`
+	Total   int                     `json:"total"`
+}
+
+type ListSubtasksResponse struct {
+	Subtasks []*entities.Task      `json:"subtasks"`
+	Progress entities.TaskProgress `json:"progress"`
+}
+
+type ListOccurrencesResponse struct {
+	// Occurrences are the due dates of the next occurrences, in the time zone of the recurrence.
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+type SearchTasksResponse struct {
+	Hits  []*entities.TaskSearchHit `json:"hits"`
+	Total int                       `json:"total"`
+}
+
+type BatchTasksResponse struct {
+	Results []BatchOperationResponse `json:"results"`
+}
+
+// BatchOperationResponse is the result of an operation, with the status code it would have had as a request of its own.
+type BatchOperationResponse struct {
+	Status int            `json:"status"`
+	Task   *entities.Task `json:"task,omitempty"`
+	Error  *ErrorResponse `json:"error,omitempty"`
+}
+
+type GetWorkflowResponse struct {
+	Workflow *entities.Workflow `json:"workflow"`
+}
+
+type CreateListResponse struct {
+	List *entities.List `json:"list"`
+}
+
+type GetListResponse struct {
+	List *entities.List `json:"list"`
+}
+
+type UpdateListResponse struct {
+	List *entities.List `json:"list"`
+}
+
+type ListListsResponse struct {
+	Lists []*entities.List `json:"lists"`
+	Total int              `json:"total"`
+}
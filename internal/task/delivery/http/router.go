@@ -2,16 +2,63 @@ package http
 
 import (
 	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/auth"
+	"ggltask/pkg/transport/middleware"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterTaskRoutes(router *gin.Engine, taskUsecase usecase.TaskUseCase) {
+// RegisterTaskRoutes registers the task and list routes. Reading requires the tasks:read scope and
+// changing anything the tasks:write scope.
+func RegisterTaskRoutes(router gin.IRouter, taskUsecase usecase.TaskUseCase) {
 	taskHandler := NewTaskHandler(taskUsecase)
+	read := middleware.GinRequireScope(auth.ScopeTasksRead)
+	write := middleware.GinRequireScope(auth.ScopeTasksWrite)
 
 	v1 := router.Group("/api/v1")
-	v1.POST("/tasks", taskHandler.CreateTask)
-	v1.GET("/tasks", taskHandler.ListTasks)
-	v1.PUT("/tasks/:id", taskHandler.UpdateTask)
-	v1.DELETE("/tasks/:id", taskHandler.DeleteTask)
+	v1.POST("/tasks", write, taskHandler.CreateTask)
+	v1.POST("/tasks:method", write, customMethods(map[string]gin.HandlerFunc{
+		"batch": taskHandler.BatchTasks,
+	}))
+	v1.GET("/tasks", read, taskHandler.ListTasks)
+	v1.GET("/tasks/search", read, taskHandler.SearchTasks)
+	v1.GET("/tasks/:id", read, taskHandler.GetTask)
+	v1.PUT("/tasks/:id", write, taskHandler.UpdateTask)
+	v1.PATCH("/tasks/:id", write, taskHandler.PatchTask)
+	v1.DELETE("/tasks/:id", write, taskHandler.DeleteTask)
+	v1.POST("/tasks/:id/restore", write, taskHandler.RestoreTask)
+	v1.GET("/tasks/:id/history", read, taskHandler.ListTaskHistory)
+	v1.GET("/tasks/:id/subtasks", read, taskHandler.ListSubtasks)
+	v1.POST("/tasks/:id/dependencies", write, taskHandler.AddDependency)
+	v1.DELETE("/tasks/:id/dependencies", write, taskHandler.RemoveDependency)
+	v1.GET("/tasks/:id/occurrences", read, taskHandler.ListOccurrences)
+
+	v1.GET("/trash", read, taskHandler.ListTrash)
+	v1.DELETE("/trash/:id", write, taskHandler.PurgeTask)
+
+	v1.GET("/workflow", read, taskHandler.GetWorkflow)
+
+	v1.POST("/lists", write, taskHandler.CreateList)
+	v1.GET("/lists", read, taskHandler.ListLists)
+	v1.GET("/lists/:id", read, taskHandler.GetList)
+	v1.PUT("/lists/:id", write, taskHandler.UpdateList)
+	v1.DELETE("/lists/:id", write, taskHandler.DeleteList)
+	v1.GET("/lists/:id/tasks", read, taskHandler.ListListTasks)
+}
+
+// customMethods dispatches custom methods such as POST /tasks:batch. gin cannot route a literal colon,
+// so the route captures everything after the collection name in the `method` parameter, colon included.
+func customMethods(handlers map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method, ok := strings.CutPrefix(c.Param("method"), ":")
+		if handler, found := handlers[method]; ok && found {
+			handler(c)
+
+			return
+		}
+
+		c.AbortWithStatus(http.StatusNotFound)
+	}
 }
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"encoding/json"
+	"ggltask/internal/task/repository/memory"
+	taskusecase "ggltask/internal/task/usecase"
+	"ggltask/pkg/auth"
+	"ggltask/pkg/transport/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWorkspaceIsolation runs the routes against the real usecase and memory repositories to prove that
+// a request never reads or modifies the tasks of another workspace.
+func TestWorkspaceIsolation(t *testing.T) {
+	t.Parallel()
+
+	router := gin.New()
+	router.Use(middleware.GinNoAuth(taskusecase.RoleAdmin), middleware.GinWorkspace())
+	RegisterTaskRoutes(router, taskusecase.NewTaskUseCaseImpl(
+		memory.NewTaskRepository(),
+		memory.NewHistoryRepository(),
+		memory.NewListRepository(),
+	))
+
+	do := func(ws, method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		if method == "PATCH" {
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+		}
+
+		if ws != "" {
+			req.Header.Set(middleware.WorkspaceHeader, ws)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		return w
+	}
+
+	for _, ws := range []string{"acme", "acme", "globex"} {
+		w := do(ws, "POST", "/api/v1/tasks", `{"name":"`+ws+` task"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// task 2 only exists in acme
+	for _, tt := range []struct {
+		method string
+		url    string
+		body   string
+	}{
+		{method: "GET", url: "/api/v1/tasks/2"},
+		{method: "PUT", url: "/api/v1/tasks/2", body: `{"name":"stolen","status":1}`},
+		{method: "PATCH", url: "/api/v1/tasks/2", body: `{"name":"stolen"}`},
+		{method: "DELETE", url: "/api/v1/tasks/2"},
+		{method: "POST", url: "/api/v1/tasks/2/restore"},
+		{method: "DELETE", url: "/api/v1/trash/2"},
+	} {
+		w := do("globex", tt.method, tt.url, tt.body)
+		assert.Equal(t, http.StatusNotFound, w.Code, "%s %s: %s", tt.method, tt.url, w.Body.String())
+	}
+
+	w := do("globex", "GET", "/api/v1/tasks/2/history", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"history":[],"total":0}`, w.Body.String())
+
+	w = do("acme", "GET", "/api/v1/tasks/2/history", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"total":1`)
+
+	w = do("acme", "GET", "/api/v1/tasks/2", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"acme task"`)
+
+	// every workspace numbers its tasks from 1
+	w = do("globex", "GET", "/api/v1/tasks/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"globex task"`)
+
+	for ws, want := range map[string]int{"acme": 2, "globex": 1, "": 0} {
+		w := do(ws, "GET", "/api/v1/tasks?page_index=1&page_size=10", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp struct {
+			Total int `json:"total"`
+		}
+
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, want, resp.Total, "workspace %q", ws)
+	}
+
+	w = do("globex", "GET", "/api/v1/tasks/search?q=acme", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "acme task")
+
+	w = do("Not A Workspace", "GET", "/api/v1/tasks/1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error_code":"INVALID_REQUEST","error_message":"invalid workspace id"}`, w.Body.String())
+}
+
+// TestWorkspaceAccess proves that a principal only works in the workspaces it was granted, whatever workspace
+// its requests name.
+func TestWorkspaceAccess(t *testing.T) {
+	t.Parallel()
+
+	admin := []string{taskusecase.RoleAdmin}
+	authenticator := keys{
+		"ggl_acme":    {Subject: "apikey:1", Scopes: []string{auth.ScopeAll}, Roles: admin, Workspaces: []string{"acme"}},
+		"ggl_globex":  {Subject: "apikey:2", Scopes: []string{auth.ScopeAll}, Roles: admin, Workspaces: []string{"globex"}},
+		"ggl_default": {Subject: "apikey:3", Scopes: []string{auth.ScopeAll}, Roles: admin},
+	}
+
+	router := gin.New()
+	router.Use(middleware.GinAPIKeyAuth(authenticator), middleware.GinWorkspace())
+	RegisterTaskRoutes(router, taskusecase.NewTaskUseCaseImpl(
+		memory.NewTaskRepository(),
+		memory.NewHistoryRepository(),
+		memory.NewListRepository(),
+	))
+
+	do := func(key, ws, method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.AuthorizationHeader, "Bearer "+key)
+
+		if ws != "" {
+			req.Header.Set(middleware.WorkspaceHeader, ws)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		return w
+	}
+
+	// without the header, a key works in its own workspace
+	w := do("ggl_acme", "", "POST", "/api/v1/tasks", `{"name":"acme task"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = do("ggl_acme", "acme", "GET", "/api/v1/tasks/1", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"name":"acme task"`)
+
+	for _, tt := range []struct {
+		key    string
+		method string
+		body   string
+	}{
+		{key: "ggl_globex", method: "GET"},
+		{key: "ggl_globex", method: "PUT", body: `{"name":"stolen","status":1}`},
+		{key: "ggl_globex", method: "DELETE"},
+		{key: "ggl_default", method: "GET"},
+		{key: "ggl_default", method: "PUT", body: `{"name":"stolen","status":1}`},
+	} {
+		w := do(tt.key, "acme", tt.method, "/api/v1/tasks/1", tt.body)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", tt.key, tt.method)
+		assert.JSONEq(t, `{"error_code":"FORBIDDEN","error_message":"workspace acme is not allowed"}`, w.Body.String())
+	}
+
+	w = do("ggl_globex", "", "GET", "/api/v1/tasks/1", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	w = do("ggl_default", "", "GET", "/api/v1/tasks/1", "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	w = do("ggl_acme", "", "GET", "/api/v1/tasks/1", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"name":"acme task"`)
+}
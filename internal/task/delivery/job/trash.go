@@ -0,0 +1,102 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/workspace"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TrashPurger periodically purges the tasks that have stayed in the trash longer than the retention.
+type TrashPurger struct {
+	taskUsecase usecase.TaskUseCase
+	retention   time.Duration
+	interval    time.Duration
+	logger      *zerolog.Logger
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewTrashPurger(taskUsecase usecase.TaskUseCase, retention, interval time.Duration, logger *zerolog.Logger) *TrashPurger {
+	return &TrashPurger{
+		taskUsecase: taskUsecase,
+		retention:   retention,
+		interval:    interval,
+		logger:      logger,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start runs a purge right away and then every interval, until Shutdown is called.
+func (p *TrashPurger) Start(ctx context.Context) {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := p.PurgeOnce(ctx); err != nil {
+				p.logger.Error().Err(err).Msg("trash purge failed")
+			}
+
+			select {
+			case <-p.stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// PurgeOnce purges the tasks trashed before now minus the retention, in every workspace.
+// A workspace that fails does not stop the others; the errors are joined.
+func (p *TrashPurger) PurgeOnce(ctx context.Context) error {
+	workspaces, err := p.taskUsecase.ListWorkspaces(ctx)
+	if err != nil {
+		return fmt.Errorf("taskUsecase.ListWorkspaces error: %w", err)
+	}
+
+	deletedBefore := time.Now().Add(-p.retention)
+
+	var errs []error
+
+	for _, ws := range workspaces {
+		purged, err := p.taskUsecase.PurgeTrash(workspace.WithWorkspace(ctx, ws), deletedBefore)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("taskUsecase.PurgeTrash error in workspace %q: %w", ws, err))
+
+			continue
+		}
+
+		if len(purged) > 0 {
+			p.logger.Info().Str("workspace", ws).Uints("task_ids", purged).Msg("purged expired trashed tasks")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Shutdown stops the purge loop and waits for a running purge to finish.
+func (p *TrashPurger) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("trash purger shutdown error: %w", ctx.Err())
+	}
+}
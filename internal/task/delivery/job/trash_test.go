@@ -0,0 +1,87 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"ggltask/internal/task/mock/usecasemock"
+	"ggltask/pkg/workspace"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "use leak detector")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestTrashPurger_PurgeOnce(t *testing.T) {
+	t.Parallel()
+
+	retention := 30 * 24 * time.Hour
+	logger := zerolog.Nop()
+
+	mockUsecase := usecasemock.NewMockTaskUseCase(gomock.NewController(t))
+	mockUsecase.EXPECT().ListWorkspaces(gomock.Any()).Return([]string{"acme", "default"}, nil).Times(2)
+	mockUsecase.EXPECT().ListWorkspaces(gomock.Any()).Return(nil, errors.New("expected error"))
+
+	purgedIn := make([]string, 0)
+	mockUsecase.EXPECT().PurgeTrash(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, deletedBefore time.Time) ([]uint, error) {
+			assert.WithinDuration(t, time.Now().Add(-retention), deletedBefore, time.Minute)
+			purgedIn = append(purgedIn, workspace.FromContext(ctx))
+
+			return []uint{1, 2}, nil
+		}).Times(2)
+	// a failing workspace does not stop the next one
+	gomock.InOrder(
+		mockUsecase.EXPECT().PurgeTrash(gomock.Any(), gomock.Any()).Return(nil, errors.New("expected error")),
+		mockUsecase.EXPECT().PurgeTrash(gomock.Any(), gomock.Any()).Return(nil, nil),
+	)
+
+	p := NewTrashPurger(mockUsecase, retention, time.Hour, &logger)
+	assert.NoError(t, p.PurgeOnce(context.Background()))
+	assert.Equal(t, []string{"acme", "default"}, purgedIn)
+	assert.Error(t, p.PurgeOnce(context.Background()))
+	assert.Error(t, p.PurgeOnce(context.Background()))
+}
+
+func TestTrashPurger_StartShutdown(t *testing.T) {
+	t.Parallel()
+
+	logger := zerolog.Nop()
+	purged := make(chan struct{}, 1)
+
+	mockUsecase := usecasemock.NewMockTaskUseCase(gomock.NewController(t))
+	mockUsecase.EXPECT().ListWorkspaces(gomock.Any()).Return([]string{"default"}, nil).MinTimes(1)
+	mockUsecase.EXPECT().PurgeTrash(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ time.Time) ([]uint, error) {
+			select {
+			case purged <- struct{}{}:
+			default:
+			}
+
+			return nil, nil
+		}).MinTimes(1)
+
+	p := NewTrashPurger(mockUsecase, time.Hour, time.Hour, &logger)
+	p.Start(context.Background())
+	<-purged
+
+	assert.NoError(t, p.Shutdown(context.Background()))
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+type HistoryAction string
+
+const (
+	HistoryActionCreate  HistoryAction = "create"
+	HistoryActionUpdate  HistoryAction = "update"
+	HistoryActionDelete  HistoryAction = "delete"
+	HistoryActionRestore HistoryAction = "restore"
+)
+
+// FieldChange is the value of one task field before and after a change, keyed by its json name.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// TaskHistory is one recorded change of a task.
+type TaskHistory struct {
+	ID        uint          `json:"id"`
+	TaskID    uint          `json:"task_id"`
+	Action    HistoryAction `json:"action"`
+	Actor     string        `json:"actor"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+}
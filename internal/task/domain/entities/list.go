@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// DefaultListID is the list tasks go to unless another one is given. It always exists and cannot be deleted.
+const DefaultListID uint = 1
+
+// List groups tasks, such as the work of a team or a project. Every task belongs to exactly one list,
+// which need not be the list of its parent.
+type List struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,7 @@
+package entities
+
+// TaskProgress rolls up the completion of the subtasks of a task, at every depth.
+type TaskProgress struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
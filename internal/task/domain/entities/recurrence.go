@@ -0,0 +1,9 @@
+package entities
+
+// Recurrence repeats a task on the occurrences of a recurrence rule, computed in a time zone.
+// The rule is a subset of the RRULE of RFC 5545: FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL.
+type Recurrence struct {
+	Rule string `json:"rule" example:"FREQ=WEEKLY;BYDAY=MO,WE"`
+	// TimeZone is an IANA time zone name, such as Asia/Taipei.
+	TimeZone string `json:"time_zone" example:"Asia/Taipei"`
+}
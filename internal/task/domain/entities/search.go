@@ -0,0 +1,10 @@
+package entities
+
+// TaskSearchHit is a task matching a search query.
+type TaskSearchHit struct {
+	Task *Task `json:"task"`
+	// Score ranks the hit; it is only comparable between hits of the same query and backend.
+	Score float64 `json:"score"`
+	// Highlight is the task name as an HTML fragment with the matched words wrapped in <mark>.
+	Highlight string `json:"highlight"`
+}
@@ -6,9 +6,27 @@ import (
 )
 
 type Task struct {
-	ID        uint            `json:"id"`
-	Name      string          `json:"name"`
-	Status    task.TaskStatus `json:"status"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID          uint            `json:"id"`
+	Name        string          `json:"name"`
+	ListID      uint            `json:"list_id"`
+	Description string          `json:"description"`
+	Status      task.TaskStatus `json:"status"`
+	Priority    task.Priority   `json:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	// DueAt keeps the time zone offset it was set with, so the due date reads the same as it was entered.
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// Tags is a set of lowercase labels, sorted and without duplicates.
+	Tags []string `json:"tags"`
+	// ParentID is the task this one is a subtask of, nil for a top-level task.
+	ParentID *uint `json:"parent_id,omitempty"`
+	// BlockedBy holds the ids of the tasks to complete before this one, sorted.
+	BlockedBy []uint `json:"blocked_by,omitempty"`
+	// Recurrence repeats the task from its due date: completing it creates the next occurrence,
+	// which takes the recurrence over.
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+	// CreatedBy is the actor who created the task, empty when it is unknown.
+	CreatedBy string     `json:"created_by,omitempty"`
+	Version   uint       `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"ggltask/internal/task"
+	"slices"
+	"strconv"
+)
+
+// WorkflowStatus is a status of a workflow, by code and name. Tasks carry the code.
+type WorkflowStatus struct {
+	Code task.TaskStatus `json:"code" example:"2"`
+	Name string          `json:"name" example:"in_progress"`
+}
+
+// WorkflowTransition lists the statuses a task can move to from a status.
+type WorkflowTransition struct {
+	From task.TaskStatus   `json:"from"`
+	To   []task.TaskStatus `json:"to"`
+}
+
+// Workflow is the state machine of the task statuses: new tasks start in the initial status,
+// then move from status to status along the transitions.
+type Workflow struct {
+	Initial     WorkflowStatus       `json:"initial"`
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// Has reports whether the status is one of the workflow.
+func (w *Workflow) Has(status task.TaskStatus) bool {
+	return slices.ContainsFunc(w.Statuses, func(s WorkflowStatus) bool { return s.Code == status })
+}
+
+// Resolve returns the status of the workflow a reference names, by name or by code.
+func (w *Workflow) Resolve(ref task.StatusRef) (WorkflowStatus, bool) {
+	for _, s := range w.Statuses {
+		if s.Name == string(ref) {
+			return s, true
+		}
+	}
+
+	if code, err := strconv.Atoi(string(ref)); err == nil {
+		for _, s := range w.Statuses {
+			if int(s.Code) == code {
+				return s, true
+			}
+		}
+	}
+
+	return WorkflowStatus{}, false
+}
+
+// Name returns the name of a status, from the workflow or, for a built-in status it no longer has, the
+// built-in name.
+func (w *Workflow) Name(status task.TaskStatus) string {
+	for _, s := range w.Statuses {
+		if s.Code == status {
+			return s.Name
+		}
+	}
+
+	return status.String()
+}
+
+// Allows reports whether a task can move from one status to another. Staying in a status is always
+// allowed, and so is leaving a status the workflow no longer has for one it has.
+func (w *Workflow) Allows(from, to task.TaskStatus) bool {
+	if from == to {
+		return true
+	}
+
+	if !w.Has(from) {
+		return w.Has(to)
+	}
+
+	for _, transition := range w.Transitions {
+		if transition.From == from {
+			return slices.Contains(transition.To, to)
+		}
+	}
+
+	return false
+}
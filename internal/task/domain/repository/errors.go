@@ -6,3 +6,4 @@ import (
 
 var ErrDataNotFound = errors.New("data not found")
 var ErrInvalidData = errors.New("invalid data")
+var ErrVersionConflict = errors.New("version conflict")
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/pkg/fulltext"
+	"time"
+)
+
+// TaskFilter narrows a task listing. Zero-valued fields do not filter.
+type TaskFilter struct {
+	Status *task.TaskStatus
+	// ListID matches the tasks of a list.
+	ListID uint
+	// NameContains matches a case-insensitive substring of the name.
+	NameContains string
+	// The Since bounds are inclusive, the Before bounds exclusive.
+	CreatedSince  time.Time
+	CreatedBefore time.Time
+	UpdatedSince  time.Time
+	UpdatedBefore time.Time
+	IDs           []uint
+	// The priority bounds are inclusive.
+	MinPriority *task.Priority
+	MaxPriority *task.Priority
+	// Tags matches the tasks that have every one of the tags.
+	Tags []string
+	// The due bounds work as the other time bounds; a task without a due date never matches them.
+	DueSince  time.Time
+	DueBefore time.Time
+	// Overdue matches the open tasks, neither completed nor archived, whose due date has passed.
+	Overdue bool
+	// Ready matches the open tasks that no live open task blocks.
+	Ready bool
+}
+
+type SortField string
+
+const (
+	SortFieldID        SortField = "id"
+	SortFieldName      SortField = "name"
+	SortFieldStatus    SortField = "status"
+	SortFieldPriority  SortField = "priority"
+	SortFieldCreatedAt SortField = "created_at"
+	SortFieldUpdatedAt SortField = "updated_at"
+)
+
+func (f SortField) Valid() bool {
+	switch f {
+	case SortFieldID, SortFieldName, SortFieldStatus, SortFieldPriority, SortFieldCreatedAt, SortFieldUpdatedAt:
+		return true
+	default:
+		return false
+	}
+}
+
+// Value returns the value of the field in a task.
+func (f SortField) Value(t *entities.Task) any {
+	switch f {
+	case SortFieldName:
+		return t.Name
+	case SortFieldStatus:
+		return t.Status
+	case SortFieldPriority:
+		return t.Priority
+	case SortFieldCreatedAt:
+		return t.CreatedAt
+	case SortFieldUpdatedAt:
+		return t.UpdatedAt
+	default:
+		return t.ID
+	}
+}
+
+type SortKey struct {
+	Field SortField
+	Desc  bool
+}
+
+// TaskQuery selects a page of tasks. Tasks are ordered by Sort, then by id so pages are stable;
+// an empty Sort orders by id alone.
+type TaskQuery struct {
+	Filter    TaskFilter
+	Sort      []SortKey
+	PageIndex int
+	PageSize  int
+}
+
+// Valid reports whether the page and the sort keys are well formed.
+func (q TaskQuery) Valid() bool {
+	if q.PageIndex < 1 || q.PageSize < 1 {
+		return false
+	}
+
+	for _, key := range q.Sort {
+		if !key.Field.Valid() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TaskCursorQuery selects the tasks that follow a cursor in the sort order or, with Backward, precede it.
+// Unlike an offset, a cursor keeps its place when tasks are created or deleted between two fetches.
+type TaskCursorQuery struct {
+	Filter TaskFilter
+	Sort   []SortKey
+	// Cursor holds the id and the sort key values of the task the listing starts after, exclusive.
+	// A nil Cursor starts from the first task, or from the last one with Backward.
+	Cursor   *entities.Task
+	Backward bool
+	Limit    int
+}
+
+// Valid reports whether the limit and the sort keys are well formed.
+func (q TaskCursorQuery) Valid() bool {
+	return TaskQuery{Sort: q.Sort, PageIndex: 1, PageSize: q.Limit}.Valid()
+}
+
+// TaskSearchQuery selects a page of the live tasks whose name matches a full-text query, most relevant first.
+// Every word of Text must match a word of the name; the last word also matches as a prefix, for autocomplete.
+type TaskSearchQuery struct {
+	Text      string
+	PageIndex int
+	PageSize  int
+}
+
+// Valid reports whether the query has a word to match and the page is well formed.
+func (q TaskSearchQuery) Valid() bool {
+	return len(fulltext.Terms(q.Text)) > 0 && q.PageIndex >= 1 && q.PageSize >= 1
+}
@@ -3,13 +3,68 @@ package repository
 import (
 	"context"
 	"ggltask/internal/task/domain/entities"
+	"time"
 )
 
+// Repository stores tasks.
+//
+// Every method works in the workspace of its context, see workspace.FromContext: a workspace is a
+// separate set of tasks with ids of its own, and nothing in another workspace is visible or writable.
+// Workspaces, for the maintenance jobs, is the one call that spans them.
+//
+// UpdateTask is a compare-and-swap when task.Version is set: the stored version must equal it,
+// otherwise ErrVersionConflict is returned. Every successful update increments the version.
+//
+// DeleteTask moves a task to the trash by setting DeletedAt. Trashed tasks are invisible to
+// GetTaskByID, ListTasksByPage and UpdateTask until RestoreTask brings them back; PurgeTask and
+// PurgeDeletedTasks remove them for good.
+//
+// WithinTransaction is the unit of work: the writes fn makes through tx are applied all together when
+// fn returns nil and not at all otherwise. Other writers wait for or are isolated from the transaction,
+// depending on the backend.
+//
 //go:generate mockgen -source=./repository.go -destination=../../mock/repositorymock/repository_mock.go -package=repositorymock
 type Repository interface {
 	CreateTask(ctx context.Context, task *entities.Task) (*entities.Task, error)
 	GetTaskByID(ctx context.Context, id uint) (*entities.Task, error)
-	ListTasksByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.Task, int, error)
+	ListTasksByPage(ctx context.Context, query TaskQuery) ([]*entities.Task, int, error)
+	// ListTasksByCursor returns the tasks in sort order whichever the direction, the total number of
+	// tasks matching the filter, and whether more tasks follow in the direction of the query.
+	ListTasksByCursor(ctx context.Context, query TaskCursorQuery) ([]*entities.Task, int, bool, error)
+	// ListChildTasks returns the live tasks whose parent is the given task, ordered by id.
+	ListChildTasks(ctx context.Context, parentID uint) ([]*entities.Task, error)
 	UpdateTask(ctx context.Context, task *entities.Task) (*entities.Task, error)
 	DeleteTask(ctx context.Context, id uint) error
+	ListDeletedTasksByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.Task, int, error)
+	// GetDeletedTaskByID returns a trashed task, and ErrDataNotFound for a live one.
+	GetDeletedTaskByID(ctx context.Context, id uint) (*entities.Task, error)
+	RestoreTask(ctx context.Context, id uint) (*entities.Task, error)
+	PurgeTask(ctx context.Context, id uint) error
+	PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) ([]uint, error)
+	// SearchTasks returns a page of the live tasks matching the query, ranked by relevance, and their total.
+	// The hits carry no highlight, which is left to the caller.
+	SearchTasks(ctx context.Context, query TaskSearchQuery) ([]*entities.TaskSearchHit, int, error)
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context, tx Repository) error) error
+	// Workspaces returns the workspaces holding tasks, live or trashed, in order.
+	Workspaces(ctx context.Context) ([]string, error)
+}
+
+// HistoryRepository stores the change history of tasks. It is append-only and keeps the history
+// of a task after the task itself is purged. As Repository, it works in the workspace of the context.
+type HistoryRepository interface {
+	AppendHistory(ctx context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error)
+	// ListHistoryByTaskID is listing the history of a task by page, newest first.
+	ListHistoryByTaskID(ctx context.Context, taskID uint, pageIndex, pageSize int) ([]*entities.TaskHistory, int, error)
+}
+
+// ListRepository stores the lists tasks belong to. As Repository, it works in the workspace of the
+// context, and every workspace holds entities.DefaultListID, which DeleteList refuses with ErrInvalidData. Deleting a list leaves its tasks alone: moving or trashing
+// them is up to the caller.
+type ListRepository interface {
+	CreateList(ctx context.Context, list *entities.List) (*entities.List, error)
+	GetListByID(ctx context.Context, id uint) (*entities.List, error)
+	// ListListsByPage is listing the lists by page, ordered by id.
+	ListListsByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.List, int, error)
+	UpdateList(ctx context.Context, list *entities.List) (*entities.List, error)
+	DeleteList(ctx context.Context, id uint) error
 }
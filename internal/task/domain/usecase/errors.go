@@ -74,3 +74,171 @@ func (e NotFoundError) Error() string {
 func (e NotFoundError) HTTPStatusCode() int {
 	return http.StatusNotFound
 }
+
+type PreconditionFailedError struct {
+	Resource string
+	ID       interface{}
+}
+
+func (e PreconditionFailedError) ErrorCode() string {
+	return "PRECONDITION_FAILED"
+}
+
+func (e PreconditionFailedError) ErrorMsg() string {
+	return fmt.Sprintf("%s %v has been modified by another request", e.Resource, e.ID)
+}
+
+func (e PreconditionFailedError) Error() string {
+	return fmt.Sprintf("%s %v has been modified by another request", e.Resource, e.ID)
+}
+
+func (e PreconditionFailedError) HTTPStatusCode() int {
+	return http.StatusPreconditionFailed
+}
+
+type InvalidArgumentError struct {
+	Argument string
+	Reason   string
+}
+
+func (e InvalidArgumentError) ErrorCode() string {
+	return "INVALID_ARGUMENT"
+}
+
+func (e InvalidArgumentError) ErrorMsg() string {
+	return fmt.Sprintf("invalid %s: %s", e.Argument, e.Reason)
+}
+
+func (e InvalidArgumentError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Argument, e.Reason)
+}
+
+func (e InvalidArgumentError) HTTPStatusCode() int {
+	return http.StatusBadRequest
+}
+
+// AbortedError is the result of an operation that was not applied because its atomic batch failed.
+type AbortedError struct {
+	// Index is the position of the operation whose failure aborted the batch.
+	Index int
+}
+
+func (e AbortedError) ErrorCode() string {
+	return "ABORTED"
+}
+
+func (e AbortedError) ErrorMsg() string {
+	return fmt.Sprintf("batch aborted by the failure of operation %d", e.Index)
+}
+
+func (e AbortedError) Error() string {
+	return fmt.Sprintf("batch aborted by the failure of operation %d", e.Index)
+}
+
+func (e AbortedError) HTTPStatusCode() int {
+	return http.StatusConflict
+}
+
+// ConflictError is returned when a request does not apply to the current state of a resource.
+type ConflictError struct {
+	Resource string
+	ID       any
+	Reason   string
+}
+
+func (e ConflictError) ErrorCode() string {
+	return "CONFLICT"
+}
+
+func (e ConflictError) ErrorMsg() string {
+	return fmt.Sprintf("%s %v: %s", e.Resource, e.ID, e.Reason)
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("%s %v: %s", e.Resource, e.ID, e.Reason)
+}
+
+func (e ConflictError) HTTPStatusCode() int {
+	return http.StatusConflict
+}
+
+// BlockedByDependenciesError is returned when a task is completed while some of the tasks it depends on are not.
+type BlockedByDependenciesError struct {
+	ID any
+	// Blockers are the ids of the incomplete tasks blocking the task.
+	Blockers []uint
+}
+
+func (e BlockedByDependenciesError) ErrorCode() string {
+	return "BLOCKED_BY_DEPENDENCIES"
+}
+
+func (e BlockedByDependenciesError) ErrorMsg() string {
+	return fmt.Sprintf("task %v is blocked by incomplete tasks %v", e.ID, e.Blockers)
+}
+
+func (e BlockedByDependenciesError) Error() string {
+	return fmt.Sprintf("task %v is blocked by incomplete tasks %v", e.ID, e.Blockers)
+}
+
+func (e BlockedByDependenciesError) HTTPStatusCode() int {
+	return http.StatusConflict
+}
+
+// InvalidStatusTransitionError is returned when the workflow does not let a task move from its status to another.
+// The statuses are given by name.
+type InvalidStatusTransitionError struct {
+	ID   any
+	From string
+	To   string
+}
+
+func (e InvalidStatusTransitionError) ErrorCode() string {
+	return "INVALID_STATUS_TRANSITION"
+}
+
+func (e InvalidStatusTransitionError) ErrorMsg() string {
+	return fmt.Sprintf("task %v cannot move from %s to %s", e.ID, e.From, e.To)
+}
+
+func (e InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("task %v cannot move from %s to %s", e.ID, e.From, e.To)
+}
+
+func (e InvalidStatusTransitionError) HTTPStatusCode() int {
+	return http.StatusConflict
+}
+
+// ForbiddenError is returned when the policy does not let the principal of a request take an action.
+type ForbiddenError struct {
+	Action   string
+	Resource string
+	// ID is the resource acted on, nil for an action on no particular one.
+	ID     any
+	Reason string
+}
+
+func (e ForbiddenError) ErrorCode() string {
+	return "FORBIDDEN"
+}
+
+func (e ForbiddenError) ErrorMsg() string {
+	msg := fmt.Sprintf("not allowed to %s %s", e.Action, e.Resource)
+	if e.ID != nil {
+		msg += fmt.Sprintf(" %v", e.ID)
+	}
+
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+
+	return msg
+}
+
+func (e ForbiddenError) Error() string {
+	return e.ErrorMsg()
+}
+
+func (e ForbiddenError) HTTPStatusCode() int {
+	return http.StatusForbidden
+}
@@ -4,32 +4,238 @@ import (
 	"context"
 	"ggltask/internal/task"
 	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"time"
 )
 
 //go:generate mockgen -source=./usecase.go -destination=../../mock/usecasemock/usecase_mock.go -package=usecasemock
 type TaskUseCase interface {
 	CreateTask(ctx context.Context, param CreateTaskParams) (*entities.Task, error)
+	GetTask(ctx context.Context, id uint) (*entities.Task, error)
 	ListTasks(ctx context.Context, param ListTasksParams) (*ListTasksResult, error)
 	UpdateTask(ctx context.Context, param UpdateTaskParams) (*entities.Task, error)
+	PatchTask(ctx context.Context, param PatchTaskParams) (*entities.Task, error)
 	DeleteTask(ctx context.Context, id uint) error
+	ListTrash(ctx context.Context, param ListTasksParams) (*ListTasksResult, error)
+	RestoreTask(ctx context.Context, id uint) (*entities.Task, error)
+	PurgeTask(ctx context.Context, id uint) error
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]uint, error)
+	// ListWorkspaces returns the workspaces holding tasks, for the jobs that go through every workspace.
+	ListWorkspaces(ctx context.Context) ([]string, error)
+	ListTaskHistory(ctx context.Context, param ListTaskHistoryParams) (*ListTaskHistoryResult, error)
+	SearchTasks(ctx context.Context, param SearchTasksParams) (*SearchTasksResult, error)
+	BatchTasks(ctx context.Context, param BatchTasksParams) (*BatchTasksResult, error)
+	ListSubtasks(ctx context.Context, id uint) (*ListSubtasksResult, error)
+	AddDependency(ctx context.Context, param DependencyParams) (*entities.Task, error)
+	RemoveDependency(ctx context.Context, param DependencyParams) (*entities.Task, error)
+	ListOccurrences(ctx context.Context, param ListOccurrencesParams) ([]time.Time, error)
+	GetWorkflow(ctx context.Context) (*entities.Workflow, error)
+	CreateList(ctx context.Context, param CreateListParams) (*entities.List, error)
+	GetList(ctx context.Context, id uint) (*entities.List, error)
+	ListLists(ctx context.Context, param ListListsParams) (*ListListsResult, error)
+	UpdateList(ctx context.Context, param UpdateListParams) (*entities.List, error)
+	DeleteList(ctx context.Context, param DeleteListParams) error
 }
 
 type CreateTaskParams struct {
-	Name string
+	Name        string
+	Description string
+	Priority    task.Priority
+	// DueAt is optional; nil creates a task without a due date.
+	DueAt *time.Time
+	Tags  []string
+	// ParentID makes the new task a subtask of a live task; nil creates a top-level task.
+	ParentID *uint
+	// Recurrence repeats the task from its due date, which it requires; nil creates a task that does not recur.
+	Recurrence *entities.Recurrence
+	// ListID is the list of the new task. Zero puts a subtask in the list of its parent,
+	// and a top-level task in the default list.
+	ListID uint
 }
 
 type UpdateTaskParams struct {
+	ID uint
+	// The fields are only validated and changed when set.
+	Name        *string
+	Description *string
+	// Status names a status of the workflow reachable from the current status, or the initial status when empty.
+	Status   *task.StatusRef
+	Priority *task.Priority
+	// DueAt points to the new due date, or to nil to remove it.
+	DueAt **time.Time
+	Tags  *[]string
+	// ParentID points to the new parent, or to nil to make the task top-level.
+	ParentID **uint
+	// Recurrence points to the new recurrence, or to nil to stop the task recurring.
+	Recurrence **entities.Recurrence
+	// BlockedBy is only changed through AddDependency and RemoveDependency.
+	BlockedBy *[]uint
+	// ListID moves the task to another list; its subtasks stay where they are.
+	ListID *uint
+	// ExpectedVersion makes the update conditional on the stored version. Zero updates unconditionally.
+	ExpectedVersion uint
+}
+
+type PatchFormat string
+
+const (
+	// PatchFormatMergePatch is a JSON Merge Patch, RFC 7396.
+	PatchFormatMergePatch PatchFormat = "merge-patch"
+	// PatchFormatJSONPatch is a JSON Patch, RFC 6902.
+	PatchFormatJSONPatch PatchFormat = "json-patch"
+)
+
+// PatchTaskParams patches the JSON representation of a task. Only the fields an update accepts can change.
+type PatchTaskParams struct {
 	ID     uint
-	Name   string
-	Status task.TaskStatus
+	Format PatchFormat
+	Patch  []byte
+	// ExpectedVersion makes the patch conditional on the stored version. Zero patches the current version.
+	ExpectedVersion uint
 }
 
 type ListTasksParams struct {
 	PageIndex int
 	PageSize  int
+	// Filter, Sort, Cursor and Status only apply to ListTasks.
+	Filter repository.TaskFilter
+	// Status lists the tasks in a status of the workflow, taking over the status of Filter.
+	Status *task.StatusRef
+	Sort   []repository.SortKey
+	// Cursor is a NextCursor or PrevCursor of an earlier result. When set, PageIndex is ignored.
+	Cursor string
 }
 
 type ListTasksResult struct {
 	Tasks []*entities.Task
 	Total int
+	// NextCursor and PrevCursor are opaque tokens for the adjacent pages, empty when there is none.
+	NextCursor string
+	PrevCursor string
+}
+
+type ListTaskHistoryParams struct {
+	TaskID    uint
+	PageIndex int
+	PageSize  int
+}
+
+type ListTaskHistoryResult struct {
+	History []*entities.TaskHistory
+	Total   int
+}
+
+type SearchTasksParams struct {
+	// Query is matched word by word against task names; its last word also matches as a prefix.
+	Query     string
+	PageIndex int
+	PageSize  int
+}
+
+type SearchTasksResult struct {
+	Hits  []*entities.TaskSearchHit
+	Total int
+}
+
+type BatchOperationType string
+
+const (
+	BatchOperationCreate BatchOperationType = "create"
+	BatchOperationUpdate BatchOperationType = "update"
+	BatchOperationDelete BatchOperationType = "delete"
+)
+
+// BatchOperation is one operation of a batch. A create uses the fields of CreateTaskParams, an update
+// replaces every field but ID and ExpectedVersion, and keeps the list when ListID is zero, and a delete only uses ID.
+type BatchOperation struct {
+	Type            BatchOperationType
+	ID              uint
+	Name            string
+	Description     string
+	Status          task.StatusRef
+	Priority        task.Priority
+	DueAt           *time.Time
+	Tags            []string
+	ParentID        *uint
+	Recurrence      *entities.Recurrence
+	ListID          uint
+	ExpectedVersion uint
+}
+
+type BatchTasksParams struct {
+	Operations []BatchOperation
+	// Atomic applies every operation or, as soon as one fails, none of them.
+	// Otherwise each operation is applied on its own, whatever became of the others.
+	Atomic bool
+}
+
+type BatchOperationResult struct {
+	// Task is the task after the operation, nil for a delete or when Err is set.
+	Task *entities.Task
+	Err  error
+}
+
+type BatchTasksResult struct {
+	// Results holds the result of every operation, in the order of the operations.
+	Results []BatchOperationResult
+}
+
+type ListSubtasksResult struct {
+	// Subtasks are the direct subtasks of the task, ordered by id.
+	Subtasks []*entities.Task
+	// Progress counts the subtasks at every depth.
+	Progress entities.TaskProgress
+}
+
+// DependencyParams is a dependency edge: the task cannot be completed before the blocker is.
+type DependencyParams struct {
+	TaskID    uint
+	BlockerID uint
+}
+
+type ListOccurrencesParams struct {
+	ID uint
+	// Count is the number of occurrences to list, after the current one.
+	Count int
+}
+
+type CreateListParams struct {
+	Name        string
+	Description string
+}
+
+type ListListsParams struct {
+	PageIndex int
+	PageSize  int
+}
+
+type ListListsResult struct {
+	Lists []*entities.List
+	Total int
+}
+
+// UpdateListParams replaces the name and description of a list.
+type UpdateListParams struct {
+	ID          uint
+	Name        string
+	Description string
+}
+
+// ListTasksPolicy decides what becomes of the tasks of a deleted list.
+type ListTasksPolicy string
+
+const (
+	// ListTasksPolicyArchive moves the live tasks to the default list and archives them.
+	ListTasksPolicyArchive ListTasksPolicy = "archive"
+	// ListTasksPolicyCascade moves the live tasks to the trash, with their subtasks as for a deleted task.
+	ListTasksPolicyCascade ListTasksPolicy = "cascade"
+)
+
+func (p ListTasksPolicy) Valid() bool {
+	return p == ListTasksPolicyArchive || p == ListTasksPolicyCascade
+}
+
+type DeleteListParams struct {
+	ID    uint
+	Tasks ListTasksPolicy
 }
@@ -7,7 +7,9 @@ package repositorymock
 import (
 	context "context"
 	entities "ggltask/internal/task/domain/entities"
+	repository "ggltask/internal/task/domain/repository"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -64,6 +66,21 @@ func (mr *MockRepositoryMockRecorder) DeleteTask(ctx, id interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockRepository)(nil).DeleteTask), ctx, id)
 }
 
+// GetDeletedTaskByID mocks base method.
+func (m *MockRepository) GetDeletedTaskByID(ctx context.Context, id uint) (*entities.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedTaskByID", ctx, id)
+	ret0, _ := ret[0].(*entities.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedTaskByID indicates an expected call of GetDeletedTaskByID.
+func (mr *MockRepositoryMockRecorder) GetDeletedTaskByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedTaskByID", reflect.TypeOf((*MockRepository)(nil).GetDeletedTaskByID), ctx, id)
+}
+
 // GetTaskByID mocks base method.
 func (m *MockRepository) GetTaskByID(ctx context.Context, id uint) (*entities.Task, error) {
 	m.ctrl.T.Helper()
@@ -79,10 +96,58 @@ func (mr *MockRepositoryMockRecorder) GetTaskByID(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockRepository)(nil).GetTaskByID), ctx, id)
 }
 
+// ListChildTasks mocks base method.
+func (m *MockRepository) ListChildTasks(ctx context.Context, parentID uint) ([]*entities.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChildTasks", ctx, parentID)
+	ret0, _ := ret[0].([]*entities.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChildTasks indicates an expected call of ListChildTasks.
+func (mr *MockRepositoryMockRecorder) ListChildTasks(ctx, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChildTasks", reflect.TypeOf((*MockRepository)(nil).ListChildTasks), ctx, parentID)
+}
+
+// ListDeletedTasksByPage mocks base method.
+func (m *MockRepository) ListDeletedTasksByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.Task, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedTasksByPage", ctx, pageIndex, pageSize)
+	ret0, _ := ret[0].([]*entities.Task)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeletedTasksByPage indicates an expected call of ListDeletedTasksByPage.
+func (mr *MockRepositoryMockRecorder) ListDeletedTasksByPage(ctx, pageIndex, pageSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedTasksByPage", reflect.TypeOf((*MockRepository)(nil).ListDeletedTasksByPage), ctx, pageIndex, pageSize)
+}
+
+// ListTasksByCursor mocks base method.
+func (m *MockRepository) ListTasksByCursor(ctx context.Context, query repository.TaskCursorQuery) ([]*entities.Task, int, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasksByCursor", ctx, query)
+	ret0, _ := ret[0].([]*entities.Task)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// ListTasksByCursor indicates an expected call of ListTasksByCursor.
+func (mr *MockRepositoryMockRecorder) ListTasksByCursor(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasksByCursor", reflect.TypeOf((*MockRepository)(nil).ListTasksByCursor), ctx, query)
+}
+
 // ListTasksByPage mocks base method.
-func (m *MockRepository) ListTasksByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.Task, int, error) {
+func (m *MockRepository) ListTasksByPage(ctx context.Context, query repository.TaskQuery) ([]*entities.Task, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTasksByPage", ctx, pageIndex, pageSize)
+	ret := m.ctrl.Call(m, "ListTasksByPage", ctx, query)
 	ret0, _ := ret[0].([]*entities.Task)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
@@ -90,9 +155,69 @@ func (m *MockRepository) ListTasksByPage(ctx context.Context, pageIndex, pageSiz
 }
 
 // ListTasksByPage indicates an expected call of ListTasksByPage.
-func (mr *MockRepositoryMockRecorder) ListTasksByPage(ctx, pageIndex, pageSize interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) ListTasksByPage(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasksByPage", reflect.TypeOf((*MockRepository)(nil).ListTasksByPage), ctx, query)
+}
+
+// PurgeDeletedTasks mocks base method.
+func (m *MockRepository) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedTasks", ctx, deletedBefore)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedTasks indicates an expected call of PurgeDeletedTasks.
+func (mr *MockRepositoryMockRecorder) PurgeDeletedTasks(ctx, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedTasks", reflect.TypeOf((*MockRepository)(nil).PurgeDeletedTasks), ctx, deletedBefore)
+}
+
+// PurgeTask mocks base method.
+func (m *MockRepository) PurgeTask(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockRepositoryMockRecorder) PurgeTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockRepository)(nil).PurgeTask), ctx, id)
+}
+
+// RestoreTask mocks base method.
+func (m *MockRepository) RestoreTask(ctx context.Context, id uint) (*entities.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", ctx, id)
+	ret0, _ := ret[0].(*entities.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockRepositoryMockRecorder) RestoreTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockRepository)(nil).RestoreTask), ctx, id)
+}
+
+// SearchTasks mocks base method.
+func (m *MockRepository) SearchTasks(ctx context.Context, query repository.TaskSearchQuery) ([]*entities.TaskSearchHit, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasks", ctx, query)
+	ret0, _ := ret[0].([]*entities.TaskSearchHit)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchTasks indicates an expected call of SearchTasks.
+func (mr *MockRepositoryMockRecorder) SearchTasks(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasksByPage", reflect.TypeOf((*MockRepository)(nil).ListTasksByPage), ctx, pageIndex, pageSize)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasks", reflect.TypeOf((*MockRepository)(nil).SearchTasks), ctx, query)
 }
 
 // UpdateTask mocks base method.
@@ -109,3 +234,184 @@ func (mr *MockRepositoryMockRecorder) UpdateTask(ctx, task interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockRepository)(nil).UpdateTask), ctx, task)
 }
+
+// WithinTransaction mocks base method.
+func (m *MockRepository) WithinTransaction(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockRepositoryMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockRepository)(nil).WithinTransaction), ctx, fn)
+}
+
+// Workspaces mocks base method.
+func (m *MockRepository) Workspaces(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Workspaces", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Workspaces indicates an expected call of Workspaces.
+func (mr *MockRepositoryMockRecorder) Workspaces(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Workspaces", reflect.TypeOf((*MockRepository)(nil).Workspaces), ctx)
+}
+
+// MockHistoryRepository is a mock of HistoryRepository interface.
+type MockHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRepositoryMockRecorder
+}
+
+// MockHistoryRepositoryMockRecorder is the mock recorder for MockHistoryRepository.
+type MockHistoryRepositoryMockRecorder struct {
+	mock *MockHistoryRepository
+}
+
+// NewMockHistoryRepository creates a new mock instance.
+func NewMockHistoryRepository(ctrl *gomock.Controller) *MockHistoryRepository {
+	mock := &MockHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRepository) EXPECT() *MockHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AppendHistory mocks base method.
+func (m *MockHistoryRepository) AppendHistory(ctx context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendHistory", ctx, entry)
+	ret0, _ := ret[0].(*entities.TaskHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendHistory indicates an expected call of AppendHistory.
+func (mr *MockHistoryRepositoryMockRecorder) AppendHistory(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendHistory", reflect.TypeOf((*MockHistoryRepository)(nil).AppendHistory), ctx, entry)
+}
+
+// ListHistoryByTaskID mocks base method.
+func (m *MockHistoryRepository) ListHistoryByTaskID(ctx context.Context, taskID uint, pageIndex, pageSize int) ([]*entities.TaskHistory, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHistoryByTaskID", ctx, taskID, pageIndex, pageSize)
+	ret0, _ := ret[0].([]*entities.TaskHistory)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListHistoryByTaskID indicates an expected call of ListHistoryByTaskID.
+func (mr *MockHistoryRepositoryMockRecorder) ListHistoryByTaskID(ctx, taskID, pageIndex, pageSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHistoryByTaskID", reflect.TypeOf((*MockHistoryRepository)(nil).ListHistoryByTaskID), ctx, taskID, pageIndex, pageSize)
+}
+
+// MockListRepository is a mock of ListRepository interface.
+type MockListRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockListRepositoryMockRecorder
+}
+
+// MockListRepositoryMockRecorder is the mock recorder for MockListRepository.
+type MockListRepositoryMockRecorder struct {
+	mock *MockListRepository
+}
+
+// NewMockListRepository creates a new mock instance.
+func NewMockListRepository(ctrl *gomock.Controller) *MockListRepository {
+	mock := &MockListRepository{ctrl: ctrl}
+	mock.recorder = &MockListRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListRepository) EXPECT() *MockListRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateList mocks base method.
+func (m *MockListRepository) CreateList(ctx context.Context, list *entities.List) (*entities.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", ctx, list)
+	ret0, _ := ret[0].(*entities.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockListRepositoryMockRecorder) CreateList(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockListRepository)(nil).CreateList), ctx, list)
+}
+
+// DeleteList mocks base method.
+func (m *MockListRepository) DeleteList(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteList", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockListRepositoryMockRecorder) DeleteList(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockListRepository)(nil).DeleteList), ctx, id)
+}
+
+// GetListByID mocks base method.
+func (m *MockListRepository) GetListByID(ctx context.Context, id uint) (*entities.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByID", ctx, id)
+	ret0, _ := ret[0].(*entities.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByID indicates an expected call of GetListByID.
+func (mr *MockListRepositoryMockRecorder) GetListByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByID", reflect.TypeOf((*MockListRepository)(nil).GetListByID), ctx, id)
+}
+
+// ListListsByPage mocks base method.
+func (m *MockListRepository) ListListsByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.List, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListListsByPage", ctx, pageIndex, pageSize)
+	ret0, _ := ret[0].([]*entities.List)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListListsByPage indicates an expected call of ListListsByPage.
+func (mr *MockListRepositoryMockRecorder) ListListsByPage(ctx, pageIndex, pageSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListListsByPage", reflect.TypeOf((*MockListRepository)(nil).ListListsByPage), ctx, pageIndex, pageSize)
+}
+
+// UpdateList mocks base method.
+func (m *MockListRepository) UpdateList(ctx context.Context, list *entities.List) (*entities.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", ctx, list)
+	ret0, _ := ret[0].(*entities.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockListRepositoryMockRecorder) UpdateList(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockListRepository)(nil).UpdateList), ctx, list)
+}
@@ -9,6 +9,7 @@ import (
 	entities "ggltask/internal/task/domain/entities"
 	usecase "ggltask/internal/task/domain/usecase"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -36,6 +37,51 @@ func (m *MockTaskUseCase) EXPECT() *MockTaskUseCaseMockRecorder {
 	return m.recorder
 }
 
+// AddDependency mocks base method.
+func (m *MockTaskUseCase) AddDependency(ctx context.Context, param usecase.DependencyParams) (*entities.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDependency", ctx, param)
+	ret0, _ := ret[0].(*entities.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDependency indicates an expected call of AddDependency.
+func (mr *MockTaskUseCaseMockRecorder) AddDependency(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockTaskUseCase)(nil).AddDependency), ctx, param)
+}
+
+// BatchTasks mocks base method.
+func (m *MockTaskUseCase) BatchTasks(ctx context.Context, param usecase.BatchTasksParams) (*usecase.BatchTasksResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchTasks", ctx, param)
+	ret0, _ := ret[0].(*usecase.BatchTasksResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchTasks indicates an expected call of BatchTasks.
+func (mr *MockTaskUseCaseMockRecorder) BatchTasks(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchTasks", reflect.TypeOf((*MockTaskUseCase)(nil).BatchTasks), ctx, param)
+}
+
+// CreateList mocks base method.
+func (m *MockTaskUseCase) CreateList(ctx context.Context, param usecase.CreateListParams) (*entities.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", ctx, param)
+	ret0, _ := ret[0].(*entities.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockTaskUseCaseMockRecorder) CreateList(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockTaskUseCase)(nil).CreateList), ctx, param)
+}
+
 // CreateTask mocks base method.
 func (m *MockTaskUseCase) CreateTask(ctx context.Context, param usecase.CreateTaskParams) (*entities.Task, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +97,20 @@ func (mr *MockTaskUseCaseMockRecorder) CreateTask(ctx, param interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockTaskUseCase)(nil).CreateTask), ctx, param)
 }
 
+// DeleteList mocks base method.
+func (m *MockTaskUseCase) DeleteList(ctx context.Context, param usecase.DeleteListParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteList", ctx, param)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockTaskUseCaseMockRecorder) DeleteList(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockTaskUseCase)(nil).DeleteList), ctx, param)
+}
+
 // DeleteTask mocks base method.
 func (m *MockTaskUseCase) DeleteTask(ctx context.Context, id uint) error {
 	m.ctrl.T.Helper()
@@ -65,6 +125,111 @@ func (mr *MockTaskUseCaseMockRecorder) DeleteTask(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskUseCase)(nil).DeleteTask), ctx, id)
 }
 
+// GetList mocks base method.
+func (m *MockTaskUseCase) GetList(ctx context.Context, id uint) (*entities.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, id)
+	ret0, _ := ret[0].(*entities.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockTaskUseCaseMockRecorder) GetList(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockTaskUseCase)(nil).GetList), ctx, id)
+}
+
+// GetTask mocks base method.
+func (m *MockTaskUseCase) GetTask(ctx context.Context, id uint) (*entities.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTask", ctx, id)
+	ret0, _ := ret[0].(*entities.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTask indicates an expected call of GetTask.
+func (mr *MockTaskUseCaseMockRecorder) GetTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockTaskUseCase)(nil).GetTask), ctx, id)
+}
+
+// GetWorkflow mocks base method.
+func (m *MockTaskUseCase) GetWorkflow(ctx context.Context) (*entities.Workflow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflow", ctx)
+	ret0, _ := ret[0].(*entities.Workflow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkflow indicates an expected call of GetWorkflow.
+func (mr *MockTaskUseCaseMockRecorder) GetWorkflow(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflow", reflect.TypeOf((*MockTaskUseCase)(nil).GetWorkflow), ctx)
+}
+
+// ListLists mocks base method.
+func (m *MockTaskUseCase) ListLists(ctx context.Context, param usecase.ListListsParams) (*usecase.ListListsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLists", ctx, param)
+	ret0, _ := ret[0].(*usecase.ListListsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLists indicates an expected call of ListLists.
+func (mr *MockTaskUseCaseMockRecorder) ListLists(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLists", reflect.TypeOf((*MockTaskUseCase)(nil).ListLists), ctx, param)
+}
+
+// ListOccurrences mocks base method.
+func (m *MockTaskUseCase) ListOccurrences(ctx context.Context, param usecase.ListOccurrencesParams) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOccurrences", ctx, param)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOccurrences indicates an expected call of ListOccurrences.
+func (mr *MockTaskUseCaseMockRecorder) ListOccurrences(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOccurrences", reflect.TypeOf((*MockTaskUseCase)(nil).ListOccurrences), ctx, param)
+}
+
+// ListSubtasks mocks base method.
+func (m *MockTaskUseCase) ListSubtasks(ctx context.Context, id uint) (*usecase.ListSubtasksResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtasks", ctx, id)
+	ret0, _ := ret[0].(*usecase.ListSubtasksResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtasks indicates an expected call of ListSubtasks.
+func (mr *MockTaskUseCaseMockRecorder) ListSubtasks(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtasks", reflect.TypeOf((*MockTaskUseCase)(nil).ListSubtasks), ctx, id)
+}
+
+// ListTaskHistory mocks base method.
+func (m *MockTaskUseCase) ListTaskHistory(ctx context.Context, param usecase.ListTaskHistoryParams) (*usecase.ListTaskHistoryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskHistory", ctx, param)
+	ret0, _ := ret[0].(*usecase.ListTaskHistoryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskHistory indicates an expected call of ListTaskHistory.
+func (mr *MockTaskUseCaseMockRecorder) ListTaskHistory(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskHistory", reflect.TypeOf((*MockTaskUseCase)(nil).ListTaskHistory), ctx, param)
+}
+
 // ListTasks mocks base method.
 func (m *MockTaskUseCase) ListTasks(ctx context.Context, param usecase.ListTasksParams) (*usecase.ListTasksResult, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +245,140 @@ func (mr *MockTaskUseCaseMockRecorder) ListTasks(ctx, param interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTaskUseCase)(nil).ListTasks), ctx, param)
 }
 
+// ListTrash mocks base method.
+func (m *MockTaskUseCase) ListTrash(ctx context.Context, param usecase.ListTasksParams) (*usecase.ListTasksResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, param)
+	ret0, _ := ret[0].(*usecase.ListTasksResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockTaskUseCaseMockRecorder) ListTrash(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockTaskUseCase)(nil).ListTrash), ctx, param)
+}
+
+// ListWorkspaces mocks base method.
+func (m *MockTaskUseCase) ListWorkspaces(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWorkspaces", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkspaces indicates an expected call of ListWorkspaces.
+func (mr *MockTaskUseCaseMockRecorder) ListWorkspaces(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkspaces", reflect.TypeOf((*MockTaskUseCase)(nil).ListWorkspaces), ctx)
+}
+
+// PatchTask mocks base method.
+func (m *MockTaskUseCase) PatchTask(ctx context.Context, param usecase.PatchTaskParams) (*entities.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTask", ctx, param)
+	ret0, _ := ret[0].(*entities.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchTask indicates an expected call of PatchTask.
+func (mr *MockTaskUseCaseMockRecorder) PatchTask(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTask", reflect.TypeOf((*MockTaskUseCase)(nil).PatchTask), ctx, param)
+}
+
+// PurgeTask mocks base method.
+func (m *MockTaskUseCase) PurgeTask(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockTaskUseCaseMockRecorder) PurgeTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockTaskUseCase)(nil).PurgeTask), ctx, id)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockTaskUseCase) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, deletedBefore)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockTaskUseCaseMockRecorder) PurgeTrash(ctx, deletedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockTaskUseCase)(nil).PurgeTrash), ctx, deletedBefore)
+}
+
+// RemoveDependency mocks base method.
+func (m *MockTaskUseCase) RemoveDependency(ctx context.Context, param usecase.DependencyParams) (*entities.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDependency", ctx, param)
+	ret0, _ := ret[0].(*entities.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveDependency indicates an expected call of RemoveDependency.
+func (mr *MockTaskUseCaseMockRecorder) RemoveDependency(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDependency", reflect.TypeOf((*MockTaskUseCase)(nil).RemoveDependency), ctx, param)
+}
+
+// RestoreTask mocks base method.
+func (m *MockTaskUseCase) RestoreTask(ctx context.Context, id uint) (*entities.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", ctx, id)
+	ret0, _ := ret[0].(*entities.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockTaskUseCaseMockRecorder) RestoreTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockTaskUseCase)(nil).RestoreTask), ctx, id)
+}
+
+// SearchTasks mocks base method.
+func (m *MockTaskUseCase) SearchTasks(ctx context.Context, param usecase.SearchTasksParams) (*usecase.SearchTasksResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasks", ctx, param)
+	ret0, _ := ret[0].(*usecase.SearchTasksResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTasks indicates an expected call of SearchTasks.
+func (mr *MockTaskUseCaseMockRecorder) SearchTasks(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasks", reflect.TypeOf((*MockTaskUseCase)(nil).SearchTasks), ctx, param)
+}
+
+// UpdateList mocks base method.
+func (m *MockTaskUseCase) UpdateList(ctx context.Context, param usecase.UpdateListParams) (*entities.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", ctx, param)
+	ret0, _ := ret[0].(*entities.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockTaskUseCaseMockRecorder) UpdateList(ctx, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockTaskUseCase)(nil).UpdateList), ctx, param)
+}
+
 // UpdateTask mocks base method.
 func (m *MockTaskUseCase) UpdateTask(ctx context.Context, param usecase.UpdateTaskParams) (*entities.Task, error) {
 	m.ctrl.T.Helper()
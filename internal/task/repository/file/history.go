@@ -0,0 +1,162 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/repository/memory"
+	"ggltask/pkg/workspace"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var _ repository.HistoryRepository = (*HistoryRepository)(nil)
+
+const historyFileName = "history.log"
+
+// historyRecord is one line of the history log: an entry and its workspace, empty for the default
+// workspace as in the lines written before workspaces.
+type historyRecord struct {
+	Workspace string `json:"workspace,omitempty"`
+	*entities.TaskHistory
+}
+
+// HistoryRepository is a repository for task history.
+// It serves reads from a memory.HistoryRepository and appends every entry to a log replayed on startup.
+// History is append-only, so the log is never compacted.
+type HistoryRepository struct {
+	mem *memory.HistoryRepository
+
+	mu         sync.Mutex
+	log        *os.File
+	syncWrites bool
+	// err is set once the log cannot be appended to, or after Close.
+	err error
+}
+
+// NewHistoryRepository opens the history stored in dir. Close must be called to release the log.
+func NewHistoryRepository(dir string, syncWrites bool) (*HistoryRepository, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll error: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, historyFileName), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open history log error: %w", err)
+	}
+
+	mem := memory.NewHistoryRepository()
+
+	// entries are replayed in log order, so the memory repository hands out the same ids again
+	offset, err := replayLog(f, func(line []byte) error {
+		var rec historyRecord
+		if err := decodeLine(line, &rec); err != nil {
+			return err
+		}
+
+		if rec.TaskHistory == nil {
+			return ErrCorruptedLog
+		}
+
+		ctx := context.Background()
+		if rec.Workspace != "" {
+			ctx = workspace.WithWorkspace(ctx, rec.Workspace)
+		}
+
+		if _, err := mem.AppendHistory(ctx, rec.TaskHistory); err != nil {
+			return fmt.Errorf("mem.AppendHistory error: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = f.Close()
+
+		return nil, fmt.Errorf("replay history log error: %w", err)
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		_ = f.Close()
+
+		return nil, fmt.Errorf("truncate history log error: %w", err)
+	}
+
+	return &HistoryRepository{
+		mem:        mem,
+		log:        f,
+		syncWrites: syncWrites,
+	}, nil
+}
+
+// AppendHistory is recording a change of a task.
+func (r *HistoryRepository) AppendHistory(ctx context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	appended, err := r.mem.AppendHistory(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("mem.AppendHistory error: %w", err)
+	}
+
+	rec := historyRecord{TaskHistory: appended}
+	if id := workspace.FromContext(ctx); id != workspace.Default {
+		rec.Workspace = id
+	}
+
+	line, err := encodeLine(rec)
+	if err != nil {
+		r.err = fmt.Errorf("encode history entry error: %w", err)
+
+		return nil, r.err
+	}
+
+	if _, err := r.log.Write(line); err != nil {
+		r.err = fmt.Errorf("write history log error: %w", err)
+
+		return nil, r.err
+	}
+
+	if r.syncWrites {
+		if err := r.log.Sync(); err != nil {
+			r.err = fmt.Errorf("sync history log error: %w", err)
+
+			return nil, r.err
+		}
+	}
+
+	return appended, nil
+}
+
+// ListHistoryByTaskID is listing the history of a task by page, newest first.
+func (r *HistoryRepository) ListHistoryByTaskID(ctx context.Context, taskID uint, pageIndex, pageSize int) ([]*entities.TaskHistory, int, error) {
+	return r.mem.ListHistoryByTaskID(ctx, taskID, pageIndex, pageSize) //nolint:wrapcheck
+}
+
+// Close closes the log.
+func (r *HistoryRepository) Close(_ context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.log == nil {
+		return nil
+	}
+
+	err := r.log.Close()
+	r.log = nil
+
+	if r.err == nil {
+		r.err = ErrClosed
+	}
+
+	if err != nil {
+		return fmt.Errorf("close history log error: %w", err)
+	}
+
+	return nil
+}
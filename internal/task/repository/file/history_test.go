@@ -0,0 +1,60 @@
+package file
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryRepository_ReplayAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r, err := NewHistoryRepository(dir, true)
+	require.NoError(t, err)
+
+	for _, action := range []entities.HistoryAction{entities.HistoryActionCreate, entities.HistoryActionUpdate} {
+		_, err := r.AppendHistory(ctx, &entities.TaskHistory{
+			TaskID:  1,
+			Action:  action,
+			Actor:   "alice",
+			Changes: []entities.FieldChange{{Field: "name", Before: "a", After: "b"}},
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.Close(ctx))
+
+	_, err = r.AppendHistory(ctx, &entities.TaskHistory{TaskID: 1, Action: entities.HistoryActionDelete})
+	assert.ErrorIs(t, err, ErrClosed)
+
+	// a torn last line is dropped on reopen
+	f, err := os.OpenFile(filepath.Join(dir, historyFileName), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`0badc0de {"id":3`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := NewHistoryRepository(dir, true)
+	require.NoError(t, err)
+	defer reopened.Close(ctx)
+
+	entries, total, err := reopened.ListHistoryByTaskID(ctx, 1, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, uint(2), entries[0].ID)
+	assert.Equal(t, entities.HistoryActionUpdate, entries[0].Action)
+	assert.Equal(t, "alice", entries[0].Actor)
+	assert.Equal(t, "b", entries[0].Changes[0].After)
+
+	appended, err := reopened.AppendHistory(ctx, &entities.TaskHistory{TaskID: 1, Action: entities.HistoryActionDelete})
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), appended.ID)
+}
@@ -0,0 +1,161 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/repository/memory"
+	"ggltask/pkg/atomicfile"
+	"ggltask/pkg/workspace"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var _ repository.ListRepository = (*ListRepository)(nil)
+
+const listsFileName = "lists.json"
+
+// listsFile holds the lists of the default workspace at the top level, where they were before
+// workspaces, and the lists of the other workspaces by id.
+type listsFile struct {
+	LastID     uint                          `json:"last_id"`
+	Lists      []*entities.List              `json:"lists"`
+	Workspaces map[string]workspaceListsFile `json:"workspaces,omitempty"`
+}
+
+type workspaceListsFile struct {
+	LastID uint             `json:"last_id"`
+	Lists  []*entities.List `json:"lists"`
+}
+
+// ListRepository is a repository for lists.
+// It serves reads from a memory.ListRepository and rewrites the whole file on every change:
+// lists are few and rarely change, so they need no log.
+type ListRepository struct {
+	mem *memory.ListRepository
+
+	// mu serializes writes so the file holds the last change.
+	mu  sync.Mutex
+	dir string
+}
+
+// NewListRepository opens the lists stored in dir.
+func NewListRepository(dir string) (*ListRepository, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll error: %w", err)
+	}
+
+	mem := memory.NewListRepository()
+
+	data, err := os.ReadFile(filepath.Join(dir, listsFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("os.ReadFile error: %w", err)
+	}
+
+	if err == nil {
+		var f listsFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal error: %w", err)
+		}
+
+		snapshots := map[string]memory.ListSnapshot{
+			workspace.Default: {Lists: f.Lists, LastID: f.LastID},
+		}
+		for id, ws := range f.Workspaces {
+			snapshots[id] = memory.ListSnapshot{Lists: ws.Lists, LastID: ws.LastID}
+		}
+
+		mem.Restore(snapshots)
+	}
+
+	return &ListRepository{
+		mem: mem,
+		dir: dir,
+	}, nil
+}
+
+// CreateList is creating a new list.
+func (r *ListRepository) CreateList(ctx context.Context, list *entities.List) (*entities.List, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created, err := r.mem.CreateList(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("mem.CreateList error: %w", err)
+	}
+
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetListByID is getting a list by id.
+func (r *ListRepository) GetListByID(ctx context.Context, id uint) (*entities.List, error) {
+	return r.mem.GetListByID(ctx, id) //nolint:wrapcheck
+}
+
+// ListListsByPage is listing the lists by page, ordered by id.
+func (r *ListRepository) ListListsByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.List, int, error) {
+	return r.mem.ListListsByPage(ctx, pageIndex, pageSize) //nolint:wrapcheck
+}
+
+// UpdateList is updating the name and description of a list.
+func (r *ListRepository) UpdateList(ctx context.Context, list *entities.List) (*entities.List, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated, err := r.mem.UpdateList(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("mem.UpdateList error: %w", err)
+	}
+
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteList is deleting a list, which must not be the default list.
+func (r *ListRepository) DeleteList(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.mem.DeleteList(ctx, id); err != nil {
+		return fmt.Errorf("mem.DeleteList error: %w", err)
+	}
+
+	return r.save()
+}
+
+// save atomically rewrites the file with the current lists. Callers must hold r.mu.
+func (r *ListRepository) save() error {
+	var f listsFile
+
+	for id, snapshot := range r.mem.Snapshot() {
+		if id == workspace.Default {
+			f.LastID, f.Lists = snapshot.LastID, snapshot.Lists
+
+			continue
+		}
+
+		if f.Workspaces == nil {
+			f.Workspaces = make(map[string]workspaceListsFile)
+		}
+
+		f.Workspaces[id] = workspaceListsFile{LastID: snapshot.LastID, Lists: snapshot.Lists}
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("json.Marshal error: %w", err)
+	}
+
+	return atomicfile.Write(r.dir, listsFileName, data)
+}
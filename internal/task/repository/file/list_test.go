@@ -0,0 +1,75 @@
+package file
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRepository_ReloadAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r, err := NewListRepository(dir)
+	require.NoError(t, err)
+
+	work, err := r.CreateList(ctx, &entities.List{Name: "work", Description: "office"})
+	require.NoError(t, err)
+	home, err := r.CreateList(ctx, &entities.List{Name: "home"})
+	require.NoError(t, err)
+	_, err = r.UpdateList(ctx, &entities.List{ID: work.ID, Name: "office"})
+	require.NoError(t, err)
+	require.NoError(t, r.DeleteList(ctx, home.ID))
+
+	err = r.DeleteList(ctx, entities.DefaultListID)
+	assert.ErrorIs(t, err, repository.ErrInvalidData)
+
+	reopened, err := NewListRepository(dir)
+	require.NoError(t, err)
+
+	lists, total, err := reopened.ListListsByPage(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, entities.DefaultListID, lists[0].ID)
+	assert.Equal(t, work.ID, lists[1].ID)
+	assert.Equal(t, "office", lists[1].Name)
+	assert.Empty(t, lists[1].Description)
+
+	_, err = reopened.GetListByID(ctx, home.ID)
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+
+	// the id of the deleted list is not handed out again
+	created, err := reopened.CreateList(ctx, &entities.List{Name: "garden"})
+	require.NoError(t, err)
+	assert.Equal(t, home.ID+1, created.ID)
+}
+
+func TestListRepository_WorkspacesAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	acme := workspace.WithWorkspace(context.Background(), "acme")
+	dir := t.TempDir()
+
+	r, err := NewListRepository(dir)
+	require.NoError(t, err)
+
+	work, err := r.CreateList(acme, &entities.List{Name: "work"})
+	require.NoError(t, err)
+
+	reopened, err := NewListRepository(dir)
+	require.NoError(t, err)
+
+	got, err := reopened.GetListByID(acme, work.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "work", got.Name)
+
+	_, err = reopened.GetListByID(context.Background(), work.ID)
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+}
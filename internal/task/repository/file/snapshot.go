@@ -0,0 +1,98 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/repository/memory"
+	"ggltask/pkg/atomicfile"
+	"ggltask/pkg/workspace"
+	"os"
+	"path/filepath"
+)
+
+const (
+	snapshotFileName = "snapshot.json"
+	walFileName      = "wal.log"
+)
+
+// snapshot holds the default workspace at the top level, where it was before workspaces,
+// and the other workspaces by id.
+type snapshot struct {
+	LastID     uint                         `json:"last_id"`
+	Tasks      []*entities.Task             `json:"tasks"`
+	Workspaces map[string]workspaceSnapshot `json:"workspaces,omitempty"`
+}
+
+type workspaceSnapshot struct {
+	LastID uint             `json:"last_id"`
+	Tasks  []*entities.Task `json:"tasks"`
+}
+
+// newSnapshot returns the snapshot of the content of a memory.TaskRepository.
+func newSnapshot(snapshots map[string]memory.TaskSnapshot) snapshot {
+	var snap snapshot
+
+	for id, ws := range snapshots {
+		if id == workspace.Default {
+			snap.LastID, snap.Tasks = ws.LastID, ws.Tasks
+
+			continue
+		}
+
+		if snap.Workspaces == nil {
+			snap.Workspaces = make(map[string]workspaceSnapshot, len(snapshots))
+		}
+
+		snap.Workspaces[id] = workspaceSnapshot{LastID: ws.LastID, Tasks: ws.Tasks}
+	}
+
+	return snap
+}
+
+// readSnapshot loads the snapshot of dir into a fresh state. A missing snapshot yields an empty state.
+func readSnapshot(dir string) (*state, error) {
+	s := newState()
+
+	data, err := os.ReadFile(filepath.Join(dir, snapshotFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("os.ReadFile error: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal error: %w", err)
+	}
+
+	if snap.Workspaces == nil {
+		snap.Workspaces = make(map[string]workspaceSnapshot, 1)
+	}
+
+	snap.Workspaces[workspace.Default] = workspaceSnapshot{LastID: snap.LastID, Tasks: snap.Tasks}
+
+	for id, wsSnap := range snap.Workspaces {
+		ws := s.workspace(id)
+		ws.lastID = wsSnap.LastID
+
+		for _, task := range wsSnap.Tasks {
+			ws.tasks[task.ID] = task
+		}
+	}
+
+	return s, nil
+}
+
+// writeSnapshot atomically replaces the snapshot of dir.
+func writeSnapshot(dir string, snap snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("json.Marshal error: %w", err)
+	}
+
+	return atomicfile.Write(dir, snapshotFileName, data)
+}
@@ -0,0 +1,512 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/repository/memory"
+	"ggltask/pkg/workspace"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var _ repository.Repository = (*TaskRepository)(nil)
+
+const defaultSnapshotInterval = time.Minute
+
+// ErrClosed is returned by writes after the repository has been closed.
+var ErrClosed = errors.New("file repository is closed")
+
+// TaskRepository is a repository for tasks.
+// It serves reads from a memory.TaskRepository, appends every change to a write-ahead log
+// and periodically compacts the log into a snapshot. Both are replayed on startup.
+// The log records and the snapshot carry the workspace of the tasks.
+type TaskRepository struct {
+	mem *memory.TaskRepository
+
+	// mu serializes writes so the log order matches the order changes are applied in memory.
+	mu      sync.Mutex
+	dir     string
+	wal     *os.File
+	pending int
+	// err is set once the log cannot be appended to; memory and disk may have diverged, so writes are refused.
+	err error
+	// batch collects the records of a transaction instead of writing them, in the repository handed to it.
+	batch *[]walRecord
+
+	syncWrites       bool
+	snapshotInterval time.Duration
+	logger           *zerolog.Logger
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Option is the options type to configure TaskRepository.
+type Option func(*TaskRepository)
+
+// WithSnapshotInterval sets how often the log is compacted into a snapshot. Default is 1 minute.
+func WithSnapshotInterval(interval time.Duration) Option {
+	return func(r *TaskRepository) {
+		if interval > 0 {
+			r.snapshotInterval = interval
+		}
+	}
+}
+
+// WithSyncWrites sets whether every log append is fsync'ed before the write returns. Default is true.
+func WithSyncWrites(syncWrites bool) Option {
+	return func(r *TaskRepository) {
+		r.syncWrites = syncWrites
+	}
+}
+
+// WithLogger sets the logger used by the background compaction.
+func WithLogger(logger *zerolog.Logger) Option {
+	return func(r *TaskRepository) {
+		r.logger = logger
+	}
+}
+
+// NewTaskRepository opens the repository stored in dir, replaying its snapshot and log,
+// and starts the background compaction. Close must be called to stop it.
+func NewTaskRepository(dir string, opts ...Option) (*TaskRepository, error) {
+	nop := zerolog.Nop()
+	r := &TaskRepository{
+		mem:              memory.NewTaskRepository(),
+		dir:              dir,
+		syncWrites:       true,
+		snapshotInterval: defaultSnapshotInterval,
+		logger:           &nop,
+		stop:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	go r.compactLoop()
+
+	return r, nil
+}
+
+func (r *TaskRepository) load() error {
+	if err := os.MkdirAll(r.dir, 0o750); err != nil {
+		return fmt.Errorf("os.MkdirAll error: %w", err)
+	}
+
+	s, err := readSnapshot(r.dir)
+	if err != nil {
+		return fmt.Errorf("read snapshot error: %w", err)
+	}
+
+	wal, err := os.OpenFile(filepath.Join(r.dir, walFileName), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open wal error: %w", err)
+	}
+
+	offset, err := replayWAL(wal, s)
+	if err != nil {
+		_ = wal.Close()
+
+		return fmt.Errorf("replay wal error: %w", err)
+	}
+
+	// drop a torn tail so new records are not appended after garbage
+	if err := wal.Truncate(offset); err != nil {
+		_ = wal.Close()
+
+		return fmt.Errorf("truncate wal error: %w", err)
+	}
+
+	r.mem.Restore(s.snapshots())
+	r.wal = wal
+
+	if offset > 0 {
+		r.pending = 1
+	}
+
+	return nil
+}
+
+// CreateTask is creating a new task.
+func (r *TaskRepository) CreateTask(ctx context.Context, taskEntity *entities.Task) (*entities.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var created *entities.Task
+
+	err := r.write(ctx, func(mem *memory.TaskRepository) ([]walRecord, error) {
+		var err error
+		if created, err = mem.CreateTask(ctx, taskEntity); err != nil {
+			return nil, fmt.Errorf("mem.CreateTask error: %w", err)
+		}
+
+		return []walRecord{{Op: walOpPut, Task: created}}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetTaskByID is getting a task by id.
+func (r *TaskRepository) GetTaskByID(ctx context.Context, id uint) (*entities.Task, error) {
+	return r.mem.GetTaskByID(ctx, id) //nolint:wrapcheck
+}
+
+// ListTasksByPage is listing the tasks matching the query by page.
+func (r *TaskRepository) ListTasksByPage(ctx context.Context, query repository.TaskQuery) ([]*entities.Task, int, error) {
+	return r.mem.ListTasksByPage(ctx, query) //nolint:wrapcheck
+}
+
+// ListTasksByCursor is listing the tasks matching the query that follow or precede its cursor.
+func (r *TaskRepository) ListTasksByCursor(ctx context.Context, query repository.TaskCursorQuery) ([]*entities.Task, int, bool, error) {
+	return r.mem.ListTasksByCursor(ctx, query) //nolint:wrapcheck
+}
+
+// ListChildTasks is listing the live subtasks of a task.
+func (r *TaskRepository) ListChildTasks(ctx context.Context, parentID uint) ([]*entities.Task, error) {
+	return r.mem.ListChildTasks(ctx, parentID) //nolint:wrapcheck
+}
+
+// SearchTasks is searching the live tasks by name, most relevant first.
+func (r *TaskRepository) SearchTasks(ctx context.Context, query repository.TaskSearchQuery) ([]*entities.TaskSearchHit, int, error) {
+	return r.mem.SearchTasks(ctx, query) //nolint:wrapcheck
+}
+
+// UpdateTask is updating a task.
+func (r *TaskRepository) UpdateTask(ctx context.Context, taskEntity *entities.Task) (*entities.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var updated *entities.Task
+
+	err := r.write(ctx, func(mem *memory.TaskRepository) ([]walRecord, error) {
+		var err error
+		if updated, err = mem.UpdateTask(ctx, taskEntity); err != nil {
+			return nil, fmt.Errorf("mem.UpdateTask error: %w", err)
+		}
+
+		return []walRecord{{Op: walOpPut, Task: updated}}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteTask is moving a task to the trash.
+func (r *TaskRepository) DeleteTask(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	return r.write(ctx, func(mem *memory.TaskRepository) ([]walRecord, error) {
+		if err := mem.DeleteTask(ctx, id); err != nil {
+			return nil, fmt.Errorf("mem.DeleteTask error: %w", err)
+		}
+
+		trashed, _ := mem.Lookup(ctx, id)
+
+		return []walRecord{{Op: walOpPut, Task: trashed}}, nil
+	})
+}
+
+// ListDeletedTasksByPage is listing trashed tasks by page, most recently deleted first.
+func (r *TaskRepository) ListDeletedTasksByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.Task, int, error) {
+	return r.mem.ListDeletedTasksByPage(ctx, pageIndex, pageSize) //nolint:wrapcheck
+}
+
+// GetDeletedTaskByID is getting a trashed task by id.
+func (r *TaskRepository) GetDeletedTaskByID(ctx context.Context, id uint) (*entities.Task, error) {
+	return r.mem.GetDeletedTaskByID(ctx, id) //nolint:wrapcheck
+}
+
+// RestoreTask is moving a task out of the trash.
+func (r *TaskRepository) RestoreTask(ctx context.Context, id uint) (*entities.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var restored *entities.Task
+
+	err := r.write(ctx, func(mem *memory.TaskRepository) ([]walRecord, error) {
+		var err error
+		if restored, err = mem.RestoreTask(ctx, id); err != nil {
+			return nil, fmt.Errorf("mem.RestoreTask error: %w", err)
+		}
+
+		return []walRecord{{Op: walOpPut, Task: restored}}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// PurgeTask is permanently removing a trashed task.
+func (r *TaskRepository) PurgeTask(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	return r.write(ctx, func(mem *memory.TaskRepository) ([]walRecord, error) {
+		if err := mem.PurgeTask(ctx, id); err != nil {
+			return nil, fmt.Errorf("mem.PurgeTask error: %w", err)
+		}
+
+		return []walRecord{{Op: walOpDelete, ID: id}}, nil
+	})
+}
+
+// PurgeDeletedTasks is permanently removing the tasks trashed before the given time.
+func (r *TaskRepository) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) ([]uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var purged []uint
+
+	err := r.write(ctx, func(mem *memory.TaskRepository) ([]walRecord, error) {
+		var err error
+		if purged, err = mem.PurgeDeletedTasks(ctx, deletedBefore); err != nil {
+			return nil, fmt.Errorf("mem.PurgeDeletedTasks error: %w", err)
+		}
+
+		records := make([]walRecord, 0, len(purged))
+		for _, id := range purged {
+			records = append(records, walRecord{Op: walOpDelete, ID: id})
+		}
+
+		return records, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
+
+// Workspaces is listing the workspaces holding tasks, live or trashed, in order.
+func (r *TaskRepository) Workspaces(ctx context.Context) ([]string, error) {
+	return r.mem.Workspaces(ctx) //nolint:wrapcheck
+}
+
+// WithinTransaction runs fn against a transactional copy of the workspace of ctx. The records of the
+// transaction are written to the log as one batch record before its changes are applied in memory.
+func (r *TaskRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context, tx repository.Repository) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	return r.mem.Transaction(ctx, func(memTx *memory.TaskRepository) error {
+		batch := make([]walRecord, 0)
+		tx := &TaskRepository{mem: memTx, batch: &batch}
+
+		if err := fn(ctx, tx); err != nil {
+			return err
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		return r.append(ctx, walRecord{Op: walOpBatch, Records: batch})
+	})
+}
+
+// write applies fn to a copy of the workspace of ctx and writes the records fn returns to the log, as one
+// record, before the copy replaces the workspace, so a change the log does not hold never reaches memory.
+// In a transaction, fn changes the copy of the transaction and the records join its batch.
+// Callers must hold r.mu.
+func (r *TaskRepository) write(ctx context.Context, fn func(mem *memory.TaskRepository) ([]walRecord, error)) error {
+	if r.batch != nil {
+		records, err := fn(r.mem)
+		if err != nil {
+			return err
+		}
+
+		for _, rec := range records {
+			_ = r.append(ctx, rec) // adding to the batch cannot fail
+		}
+
+		return nil
+	}
+
+	return r.mem.Transaction(ctx, func(memTx *memory.TaskRepository) error {
+		records, err := fn(memTx)
+		if err != nil {
+			return err
+		}
+
+		switch len(records) {
+		case 0:
+			return nil
+		case 1:
+			return r.append(ctx, records[0])
+		}
+
+		if id := workspace.FromContext(ctx); id != workspace.Default {
+			for i := range records {
+				records[i].Workspace = id
+			}
+		}
+
+		return r.append(ctx, walRecord{Op: walOpBatch, Records: records})
+	})
+}
+
+// append writes a record of the workspace of ctx to the log, or adds it to the batch of a transaction.
+// Callers must hold r.mu.
+func (r *TaskRepository) append(ctx context.Context, rec walRecord) error {
+	if id := workspace.FromContext(ctx); id != workspace.Default {
+		rec.Workspace = id
+	}
+
+	if r.batch != nil {
+		*r.batch = append(*r.batch, rec)
+
+		return nil
+	}
+
+	line, err := encodeRecord(rec)
+	if err != nil {
+		r.err = fmt.Errorf("encode wal record error: %w", err)
+
+		return r.err
+	}
+
+	if _, err := r.wal.Write(line); err != nil {
+		r.err = fmt.Errorf("write wal error: %w", err)
+
+		return r.err
+	}
+
+	if r.syncWrites {
+		if err := r.wal.Sync(); err != nil {
+			r.err = fmt.Errorf("sync wal error: %w", err)
+
+			return r.err
+		}
+	}
+
+	r.pending++
+
+	return nil
+}
+
+// Compact writes a snapshot of the current state and truncates the log.
+func (r *TaskRepository) Compact() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.compact()
+}
+
+func (r *TaskRepository) compact() error {
+	if r.err != nil || r.pending == 0 {
+		return r.err
+	}
+
+	if err := writeSnapshot(r.dir, newSnapshot(r.mem.Snapshot())); err != nil {
+		return err
+	}
+
+	// the snapshot already holds every record; replaying a leftover log after a crash here is harmless
+	if err := r.wal.Truncate(0); err != nil {
+		return fmt.Errorf("truncate wal error: %w", err)
+	}
+
+	if err := r.wal.Sync(); err != nil {
+		return fmt.Errorf("sync wal error: %w", err)
+	}
+
+	r.pending = 0
+
+	return nil
+}
+
+func (r *TaskRepository) compactLoop() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			if err := r.Compact(); err != nil {
+				r.logger.Error().Err(err).Str("dir", r.dir).Msg("task repository compaction failed")
+			}
+		}
+	}
+}
+
+// Close stops the background compaction, writes a final snapshot and closes the log.
+func (r *TaskRepository) Close(_ context.Context) error {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.wal == nil {
+		return nil
+	}
+
+	compactErr := r.compact()
+
+	if err := r.wal.Close(); err != nil {
+		return fmt.Errorf("close wal error: %w", err)
+	}
+
+	r.wal = nil
+	if r.err == nil {
+		r.err = ErrClosed
+	}
+
+	return compactErr
+}
@@ -0,0 +1,415 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "use leak detector")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+func openRepository(t *testing.T, dir string, opts ...Option) *TaskRepository {
+	t.Helper()
+
+	r, err := NewTaskRepository(dir, append([]Option{WithSnapshotInterval(time.Hour)}, opts...)...)
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestTaskRepository_ReplayAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r := openRepository(t, dir)
+
+	for _, name := range []string{"task 1", "task 2", "task 3"} {
+		_, err := r.CreateTask(ctx, &entities.Task{Name: name, Status: task.TaskStatusIncomplete})
+		require.NoError(t, err)
+	}
+
+	dueAt := time.Date(2030, 1, 2, 9, 0, 0, 0, time.FixedZone("", 2*60*60))
+
+	_, err := r.UpdateTask(ctx, &entities.Task{
+		ID:       1,
+		Name:     "task 1 done",
+		Status:   task.TaskStatusCompleted,
+		Priority: task.PriorityUrgent,
+		DueAt:    &dueAt,
+		Tags:     []string{"ops"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, r.DeleteTask(ctx, 3))
+
+	// simulate a crash: close the log without compacting
+	require.NoError(t, r.wal.Close())
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+
+	reopened := openRepository(t, dir)
+	defer reopened.Close(ctx)
+
+	got, err := reopened.GetTaskByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "task 1 done", got.Name)
+	assert.Equal(t, task.TaskStatusCompleted, got.Status)
+	assert.Equal(t, task.PriorityUrgent, got.Priority)
+	assert.Equal(t, []string{"ops"}, got.Tags)
+	require.NotNil(t, got.DueAt)
+	assert.True(t, dueAt.Equal(*got.DueAt))
+	// the due date keeps the time zone offset it was set with
+	_, offset := got.DueAt.Zone()
+	assert.Equal(t, 2*60*60, offset)
+
+	_, err = reopened.GetTaskByID(ctx, 3)
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+
+	// the search index is rebuilt from the log
+	hits, total, err := reopened.SearchTasks(ctx, repository.TaskSearchQuery{Text: "don", PageIndex: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, uint(1), hits[0].Task.ID)
+
+	// the deleted task 3 must not have its id reused
+	created, err := reopened.CreateTask(ctx, &entities.Task{Name: "task 4"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), created.ID)
+}
+
+func TestTaskRepository_CompactAndReopen(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r := openRepository(t, dir)
+
+	for i := 0; i < 3; i++ {
+		_, err := r.CreateTask(ctx, &entities.Task{Name: "task"})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.DeleteTask(ctx, 3))
+	require.NoError(t, r.Compact())
+
+	info, err := os.Stat(filepath.Join(dir, walFileName))
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+
+	_, err = r.CreateTask(ctx, &entities.Task{Name: "task"})
+	require.NoError(t, err)
+	require.NoError(t, r.Close(ctx))
+
+	_, err = r.CreateTask(ctx, &entities.Task{Name: "task"})
+	assert.ErrorIs(t, err, ErrClosed)
+
+	reopened := openRepository(t, dir)
+	defer reopened.Close(ctx)
+
+	tasks, total, err := reopened.ListTasksByPage(ctx, repository.TaskQuery{PageIndex: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, uint(4), tasks[2].ID)
+
+	created, err := reopened.CreateTask(ctx, &entities.Task{Name: "task"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(5), created.ID)
+}
+
+func TestTaskRepository_TornTail(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r := openRepository(t, dir)
+	_, err := r.CreateTask(ctx, &entities.Task{Name: "task 1"})
+	require.NoError(t, err)
+	require.NoError(t, r.wal.Close())
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+
+	walPath := filepath.Join(dir, walFileName)
+	f, err := os.OpenFile(walPath, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`0badc0de {"op":"put","task":{"id":2`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened := openRepository(t, dir)
+	defer reopened.Close(ctx)
+
+	_, total, err := reopened.ListTasksByPage(ctx, repository.TaskQuery{PageIndex: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	created, err := reopened.CreateTask(ctx, &entities.Task{Name: "task 2"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), created.ID)
+}
+
+func TestTaskRepository_CorruptedLog(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	line, err := encodeRecord(walRecord{Op: walOpPut, Task: &entities.Task{ID: 1, Name: "task 1"}})
+	require.NoError(t, err)
+
+	content := append([]byte("00000000 {}\n"), line...)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, walFileName), content, 0o600))
+
+	_, err = NewTaskRepository(dir)
+	assert.True(t, errors.Is(err, ErrCorruptedLog))
+}
+
+func TestTaskRepository_InvalidData(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := openRepository(t, t.TempDir())
+	defer r.Close(ctx)
+
+	_, err := r.CreateTask(ctx, &entities.Task{Name: ""})
+	assert.ErrorIs(t, err, repository.ErrInvalidData)
+
+	_, err = r.UpdateTask(ctx, &entities.Task{ID: 9, Name: "task"})
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+
+	assert.ErrorIs(t, r.DeleteTask(ctx, 9), repository.ErrDataNotFound)
+	assert.Zero(t, r.pending)
+}
+
+func TestTaskRepository_TrashReplay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r := openRepository(t, dir)
+
+	for i := 0; i < 3; i++ {
+		_, err := r.CreateTask(ctx, &entities.Task{Name: "task"})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, r.DeleteTask(ctx, 1))
+	require.NoError(t, r.DeleteTask(ctx, 2))
+	require.NoError(t, r.DeleteTask(ctx, 3))
+	require.NoError(t, r.PurgeTask(ctx, 1))
+	_, err := r.RestoreTask(ctx, 2)
+	require.NoError(t, err)
+
+	// simulate a crash: close the log without compacting
+	require.NoError(t, r.wal.Close())
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+
+	reopened := openRepository(t, dir)
+	defer reopened.Close(ctx)
+
+	_, ok := reopened.mem.Lookup(ctx, 1)
+	assert.False(t, ok)
+
+	restored, err := reopened.GetTaskByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+
+	trashed, total, err := reopened.ListDeletedTasksByPage(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, uint(3), trashed[0].ID)
+
+	purged, err := reopened.PurgeDeletedTasks(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3}, purged)
+}
+
+func TestTaskRepository_TransactionReplay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r := openRepository(t, dir)
+
+	_, err := r.CreateTask(ctx, &entities.Task{Name: "task 1"})
+	require.NoError(t, err)
+
+	errRollback := errors.New("rollback")
+	err = r.WithinTransaction(ctx, func(ctx context.Context, tx repository.Repository) error {
+		_, err := tx.CreateTask(ctx, &entities.Task{Name: "rolled back"})
+		require.NoError(t, err)
+
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+	assert.Equal(t, 1, r.pending)
+
+	err = r.WithinTransaction(ctx, func(ctx context.Context, tx repository.Repository) error {
+		if _, err := tx.UpdateTask(ctx, &entities.Task{ID: 1, Name: "task 1 done"}); err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateTask(ctx, &entities.Task{Name: "task 2"}); err != nil {
+			return err
+		}
+
+		return tx.DeleteTask(ctx, 2)
+	})
+	require.NoError(t, err)
+	// the whole transaction is one record
+	assert.Equal(t, 2, r.pending)
+
+	// simulate a crash: close the log without compacting
+	require.NoError(t, r.wal.Close())
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+
+	reopened := openRepository(t, dir)
+	defer reopened.Close(ctx)
+
+	got, err := reopened.GetTaskByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "task 1 done", got.Name)
+
+	trashed, total, err := reopened.ListDeletedTasksByPage(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "task 2", trashed[0].Name)
+}
+
+func TestTaskRepository_WorkspacesAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	acme := workspace.WithWorkspace(context.Background(), "acme")
+	globex := workspace.WithWorkspace(context.Background(), "globex")
+	dir := t.TempDir()
+
+	r := openRepository(t, dir)
+
+	for _, ctx := range []context.Context{acme, acme, globex} {
+		_, err := r.CreateTask(ctx, &entities.Task{Name: workspace.FromContext(ctx)})
+		require.NoError(t, err)
+	}
+
+	// half of the workspaces go through the snapshot, the rest through the log
+	require.NoError(t, r.Compact())
+
+	_, err := r.CreateTask(globex, &entities.Task{Name: "globex"})
+	require.NoError(t, err)
+	require.NoError(t, r.Close(context.Background()))
+
+	reopened := openRepository(t, dir)
+	defer reopened.Close(context.Background())
+
+	workspaces, err := reopened.Workspaces(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "globex"}, workspaces)
+
+	for _, ctx := range []context.Context{acme, globex} {
+		tasks, total, err := reopened.ListTasksByPage(ctx, repository.TaskQuery{PageIndex: 1, PageSize: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+
+		for _, got := range tasks {
+			assert.Equal(t, workspace.FromContext(ctx), got.Name)
+		}
+
+		created, err := reopened.CreateTask(ctx, &entities.Task{Name: "task"})
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), created.ID)
+	}
+
+	_, total, err := reopened.ListTasksByPage(context.Background(), repository.TaskQuery{PageIndex: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestTaskRepository_FailedAppend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, r *TaskRepository) error
+	}{
+		{
+			name: "create",
+			write: func(ctx context.Context, r *TaskRepository) error {
+				_, err := r.CreateTask(ctx, &entities.Task{Name: "task 2"})
+
+				return err
+			},
+		},
+		{
+			name: "update",
+			write: func(ctx context.Context, r *TaskRepository) error {
+				_, err := r.UpdateTask(ctx, &entities.Task{ID: 1, Name: "task 1 done", Status: task.TaskStatusCompleted})
+
+				return err
+			},
+		},
+		{
+			name: "delete",
+			write: func(ctx context.Context, r *TaskRepository) error {
+				return r.DeleteTask(ctx, 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			r := openRepository(t, dir)
+
+			_, err := r.CreateTask(ctx, &entities.Task{Name: "task 1", Status: task.TaskStatusIncomplete})
+			require.NoError(t, err)
+
+			// make the next append fail
+			require.NoError(t, r.wal.Close())
+			r.stopOnce.Do(func() { close(r.stop) })
+			<-r.done
+
+			require.Error(t, tt.write(ctx, r))
+
+			got, err := r.GetTaskByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "task 1", got.Name)
+			assert.Equal(t, task.TaskStatusIncomplete, got.Status)
+			assert.Nil(t, got.DeletedAt)
+
+			_, ok := r.mem.Lookup(ctx, 2)
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/repository/memory"
+	"ggltask/pkg/workspace"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+)
+
+// ErrCorruptedLog is returned when a record in the middle of the write-ahead log cannot be decoded.
+var ErrCorruptedLog = errors.New("write-ahead log is corrupted")
+
+type walOp string
+
+const (
+	walOpPut walOp = "put"
+	// walOpDelete removes a task for good; moving a task to the trash is a put with DeletedAt set.
+	walOpDelete walOp = "delete"
+	// walOpBatch groups the records of a transaction in one line, so a crash cannot leave half of it applied.
+	walOpBatch walOp = "batch"
+)
+
+// walRecord is one entry of the write-ahead log.
+// A put record carries the full task state after the change, so replaying it is idempotent.
+type walRecord struct {
+	Op   walOp          `json:"op"`
+	Task *entities.Task `json:"task,omitempty"`
+	ID   uint           `json:"id,omitempty"`
+	// Workspace is the workspace of the task; it is empty for the default workspace,
+	// as in the records written before workspaces.
+	Workspace string      `json:"workspace,omitempty"`
+	Records   []walRecord `json:"records,omitempty"`
+}
+
+// state is the repository content rebuilt from a snapshot and the log, by workspace.
+type state struct {
+	workspaces map[string]*workspaceState
+}
+
+type workspaceState struct {
+	tasks  map[uint]*entities.Task
+	lastID uint
+}
+
+func newState() *state {
+	return &state{workspaces: make(map[string]*workspaceState)}
+}
+
+// workspace returns the content of a workspace, creating it when missing.
+func (s *state) workspace(id string) *workspaceState {
+	if id == "" {
+		id = workspace.Default
+	}
+
+	ws, ok := s.workspaces[id]
+	if !ok {
+		ws = &workspaceState{tasks: make(map[uint]*entities.Task)}
+		s.workspaces[id] = ws
+	}
+
+	return ws
+}
+
+// snapshots returns the content of every workspace, as memory.TaskRepository restores it.
+func (s *state) snapshots() map[string]memory.TaskSnapshot {
+	snapshots := make(map[string]memory.TaskSnapshot, len(s.workspaces))
+
+	for id, ws := range s.workspaces {
+		tasks := make([]*entities.Task, 0, len(ws.tasks))
+		for _, task := range ws.tasks {
+			tasks = append(tasks, task)
+		}
+
+		snapshots[id] = memory.TaskSnapshot{Tasks: tasks, LastID: ws.lastID}
+	}
+
+	return snapshots
+}
+
+func (s *state) apply(rec walRecord) error {
+	switch rec.Op {
+	case walOpPut:
+		if rec.Task == nil {
+			return fmt.Errorf("put record without task: %w", ErrCorruptedLog)
+		}
+
+		ws := s.workspace(rec.Workspace)
+		ws.tasks[rec.Task.ID] = rec.Task
+		if rec.Task.ID > ws.lastID {
+			ws.lastID = rec.Task.ID
+		}
+	case walOpDelete:
+		delete(s.workspace(rec.Workspace).tasks, rec.ID)
+	case walOpBatch:
+		for _, r := range rec.Records {
+			if err := s.apply(r); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown op %q: %w", rec.Op, ErrCorruptedLog)
+	}
+
+	return nil
+}
+
+// encodeRecord encodes a record as one `<crc32> <json>\n` line.
+func encodeRecord(rec walRecord) ([]byte, error) {
+	return encodeLine(rec)
+}
+
+// encodeLine encodes a value as one `<crc32> <json>\n` line.
+func encodeLine(v any) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal error: %w", err)
+	}
+
+	line := make([]byte, 0, len(payload)+10) //nolint:mnd
+	line = fmt.Appendf(line, "%08x ", crc32.ChecksumIEEE(payload))
+	line = append(line, payload...)
+	line = append(line, '\n')
+
+	return line, nil
+}
+
+// decodeLine checks the checksum of a line and decodes its payload into v.
+func decodeLine(line []byte, v any) error {
+	sum, payload, ok := bytes.Cut(bytes.TrimSuffix(line, []byte("\n")), []byte(" "))
+	if !ok {
+		return ErrCorruptedLog
+	}
+
+	want, err := strconv.ParseUint(string(sum), 16, 32)
+	if err != nil || uint32(want) != crc32.ChecksumIEEE(payload) {
+		return ErrCorruptedLog
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("json.Unmarshal error: %w", ErrCorruptedLog)
+	}
+
+	return nil
+}
+
+// replayWAL applies every record of the log to the state and returns the offset of the last complete record.
+func replayWAL(f *os.File, s *state) (int64, error) {
+	return replayLog(f, func(line []byte) error {
+		var rec walRecord
+		if err := decodeLine(line, &rec); err != nil {
+			return err
+		}
+
+		return s.apply(rec)
+	})
+}
+
+// replayLog calls apply with every line of the log and returns the offset of the last complete line.
+// A torn line at the tail, left by a crash in the middle of a write, is ignored.
+func replayLog(f *os.File, apply func(line []byte) error) (int64, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("f.Seek error: %w", err)
+	}
+
+	reader := bufio.NewReader(f)
+
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// incomplete last line, the write never finished
+			return offset, nil
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("read log error: %w", err)
+		}
+
+		if err := apply(line); err != nil {
+			if _, peekErr := reader.Peek(1); errors.Is(peekErr, io.EOF) && errors.Is(err, ErrCorruptedLog) {
+				return offset, nil
+			}
+
+			return 0, fmt.Errorf("record at offset %d: %w", offset, err)
+		}
+
+		offset += int64(len(line))
+	}
+}
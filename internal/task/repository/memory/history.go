@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"sync"
+	"time"
+)
+
+var _ repository.HistoryRepository = (*HistoryRepository)(nil)
+
+// HistoryRepository is a repository for task history.
+// It is a memory repository that keeps the entries of each task in insertion order, per workspace.
+type HistoryRepository struct {
+	mu      sync.RWMutex
+	entries map[string]map[uint][]*entities.TaskHistory
+	lastID  uint
+}
+
+func NewHistoryRepository() *HistoryRepository {
+	return &HistoryRepository{
+		entries: make(map[string]map[uint][]*entities.TaskHistory),
+	}
+}
+
+// AppendHistory is recording a change of a task.
+func (r *HistoryRepository) AppendHistory(ctx context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error) {
+	if entry.TaskID == 0 || entry.Action == "" {
+		return nil, repository.ErrInvalidData
+	}
+
+	id := workspace.FromContext(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	entry.ID = r.lastID
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	if r.entries[id] == nil {
+		r.entries[id] = make(map[uint][]*entities.TaskHistory)
+	}
+
+	r.entries[id][entry.TaskID] = append(r.entries[id][entry.TaskID], entry)
+
+	return entry, nil
+}
+
+// ListHistoryByTaskID is listing the history of a task by page, newest first.
+func (r *HistoryRepository) ListHistoryByTaskID(ctx context.Context, taskID uint, pageIndex, pageSize int) ([]*entities.TaskHistory, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := r.entries[workspace.FromContext(ctx)][taskID]
+	total := len(entries)
+
+	start := (pageIndex - 1) * pageSize
+	if start >= total {
+		return []*entities.TaskHistory{}, total, nil
+	}
+
+	end := min(start+pageSize, total)
+
+	page := make([]*entities.TaskHistory, 0, end-start)
+	for i := total - 1 - start; i >= total-end; i-- {
+		page = append(page, entries[i])
+	}
+
+	return page, total, nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"testing"
+)
+
+func TestHistoryRepository_ListHistoryByTaskID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewHistoryRepository()
+
+	for _, taskID := range []uint{1, 2, 1, 1} {
+		if _, err := r.AppendHistory(ctx, &entities.TaskHistory{TaskID: taskID, Action: entities.HistoryActionUpdate}); err != nil {
+			t.Fatalf("AppendHistory() error = %v", err)
+		}
+	}
+
+	if _, err := r.AppendHistory(ctx, &entities.TaskHistory{TaskID: 1}); err != repository.ErrInvalidData {
+		t.Errorf("AppendHistory() without action error = %v, want %v", err, repository.ErrInvalidData)
+	}
+
+	tests := []struct {
+		name      string
+		pageIndex int
+		pageSize  int
+		wantIDs   []uint
+		wantTotal int
+		wantErr   error
+	}{
+		{name: "first page is newest", pageIndex: 1, pageSize: 2, wantIDs: []uint{4, 3}, wantTotal: 3},
+		{name: "last page", pageIndex: 2, pageSize: 2, wantIDs: []uint{1}, wantTotal: 3},
+		{name: "past the end", pageIndex: 3, pageSize: 2, wantIDs: []uint{}, wantTotal: 3},
+		{name: "invalid page", pageIndex: 0, pageSize: 2, wantErr: repository.ErrInvalidData},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, total, err := r.ListHistoryByTaskID(ctx, 1, tt.pageIndex, tt.pageSize)
+			if err != tt.wantErr {
+				t.Fatalf("ListHistoryByTaskID() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			ids := make([]uint, 0, len(got))
+			for _, entry := range got {
+				ids = append(ids, entry.ID)
+			}
+
+			if total != tt.wantTotal || len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ListHistoryByTaskID() got ids %v total %d, want %v total %d", ids, total, tt.wantIDs, tt.wantTotal)
+			}
+
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("ListHistoryByTaskID() got ids %v, want %v", ids, tt.wantIDs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var _ repository.ListRepository = (*ListRepository)(nil)
+
+// ListRepository is a repository for lists.
+// It is a memory repository that keeps the lists of each workspace in a map of their own,
+// each starting with the default list.
+type ListRepository struct {
+	// mu guards the workspaces map; each workspace has its own lock for its lists.
+	mu         sync.RWMutex
+	workspaces map[string]*listSpace
+}
+
+// listSpace holds the lists of a workspace.
+type listSpace struct {
+	mu     sync.RWMutex
+	lists  map[uint]*entities.List
+	lastID uint
+}
+
+// ListSnapshot is the content of a workspace: its lists ordered by id and the last allocated id.
+type ListSnapshot struct {
+	Lists  []*entities.List
+	LastID uint
+}
+
+func NewListRepository() *ListRepository {
+	return &ListRepository{
+		workspaces: make(map[string]*listSpace),
+	}
+}
+
+// newListSpace returns the lists of a workspace, adding the default list when missing.
+func newListSpace(snapshot ListSnapshot) *listSpace {
+	s := &listSpace{
+		lists:  make(map[uint]*entities.List, len(snapshot.Lists)+1),
+		lastID: max(snapshot.LastID, entities.DefaultListID),
+	}
+
+	for _, list := range snapshot.Lists {
+		s.lists[list.ID] = list
+		s.lastID = max(s.lastID, list.ID)
+	}
+
+	if _, ok := s.lists[entities.DefaultListID]; !ok {
+		now := time.Now()
+		s.lists[entities.DefaultListID] = &entities.List{ID: entities.DefaultListID, Name: "Inbox", CreatedAt: now, UpdatedAt: now}
+	}
+
+	return s
+}
+
+// space returns the lists of the workspace of ctx, creating them on first use.
+func (r *ListRepository) space(ctx context.Context) *listSpace {
+	id := workspace.FromContext(ctx)
+
+	r.mu.RLock()
+	s, ok := r.workspaces[id]
+	r.mu.RUnlock()
+
+	if ok {
+		return s
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok = r.workspaces[id]; !ok {
+		s = newListSpace(ListSnapshot{})
+		r.workspaces[id] = s
+	}
+
+	return s
+}
+
+// CreateList is creating a new list.
+func (r *ListRepository) CreateList(ctx context.Context, list *entities.List) (*entities.List, error) {
+	if list.Name == "" || utf8.RuneCountInString(list.Name) > 50 {
+		return nil, repository.ErrInvalidData
+	}
+
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	list.ID = s.lastID
+	list.CreatedAt = time.Now()
+	list.UpdatedAt = list.CreatedAt
+	s.lists[list.ID] = list
+
+	return list, nil
+}
+
+// GetListByID is getting a list by id.
+func (r *ListRepository) GetListByID(ctx context.Context, id uint) (*entities.List, error) {
+	s := r.space(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, ok := s.lists[id]
+	if !ok {
+		return nil, repository.ErrDataNotFound
+	}
+
+	return list, nil
+}
+
+// ListListsByPage is listing the lists by page, ordered by id.
+func (r *ListRepository) ListListsByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.List, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	lists := r.space(ctx).snapshot().Lists
+	total := len(lists)
+
+	start := min((pageIndex-1)*pageSize, total)
+	end := min(start+pageSize, total)
+
+	return lists[start:end], total, nil
+}
+
+// UpdateList is updating the name and description of a list.
+func (r *ListRepository) UpdateList(ctx context.Context, list *entities.List) (*entities.List, error) {
+	if list.Name == "" || utf8.RuneCountInString(list.Name) > 50 {
+		return nil, repository.ErrInvalidData
+	}
+
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.lists[list.ID]
+	if !ok {
+		return nil, repository.ErrDataNotFound
+	}
+
+	stored.Name = list.Name
+	stored.Description = list.Description
+	stored.UpdatedAt = time.Now()
+
+	return stored, nil
+}
+
+// DeleteList is deleting a list, which must not be the default list.
+func (r *ListRepository) DeleteList(ctx context.Context, id uint) error {
+	if id == entities.DefaultListID {
+		return repository.ErrInvalidData
+	}
+
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lists[id]; !ok {
+		return repository.ErrDataNotFound
+	}
+
+	delete(s.lists, id)
+
+	return nil
+}
+
+// Snapshot is returning the content of every workspace used so far, by workspace.
+func (r *ListRepository) Snapshot() map[string]ListSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := make(map[string]ListSnapshot, len(r.workspaces))
+	for id, s := range r.workspaces {
+		snapshots[id] = s.snapshot()
+	}
+
+	return snapshots
+}
+
+// snapshot is returning the lists of the workspace ordered by id together with the last allocated id.
+func (s *listSpace) snapshot() ListSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lists := make([]*entities.List, 0, len(s.lists))
+	for _, list := range s.lists {
+		lists = append(lists, list)
+	}
+
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].ID < lists[j].ID
+	})
+
+	return ListSnapshot{Lists: lists, LastID: s.lastID}
+}
+
+// Restore is replacing the repository content, e.g. with state recovered from disk.
+// The default list is added to the workspaces missing it.
+func (r *ListRepository) Restore(snapshots map[string]ListSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.workspaces = make(map[string]*listSpace, len(snapshots))
+	for id, snapshot := range snapshots {
+		r.workspaces[id] = newListSpace(snapshot)
+	}
+}
+
+// listOrDefault returns the list id, or the default list for a task without one.
+func listOrDefault(id uint) uint {
+	if id == 0 {
+		return entities.DefaultListID
+	}
+
+	return id
+}
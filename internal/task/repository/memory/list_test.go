@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"strings"
+	"testing"
+)
+
+func TestListRepository(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewListRepository()
+
+	inbox, err := r.GetListByID(ctx, entities.DefaultListID)
+	if err != nil || inbox.Name != "Inbox" {
+		t.Fatalf("GetListByID() default list = %v, %v, want Inbox", inbox, err)
+	}
+
+	work, err := r.CreateList(ctx, &entities.List{Name: "work"})
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	if work.ID != 2 {
+		t.Errorf("CreateList() id = %d, want 2", work.ID)
+	}
+
+	if _, err := r.CreateList(ctx, &entities.List{}); err != repository.ErrInvalidData {
+		t.Errorf("CreateList() without name error = %v, want %v", err, repository.ErrInvalidData)
+	}
+
+	// names are counted in characters, not bytes
+	if _, err := r.UpdateList(ctx, &entities.List{ID: work.ID, Name: strings.Repeat("é", 50)}); err != nil {
+		t.Errorf("UpdateList() multibyte name error = %v", err)
+	}
+
+	updated, err := r.UpdateList(ctx, &entities.List{ID: work.ID, Name: "office", Description: "desk"})
+	if err != nil || updated.Name != "office" || updated.Description != "desk" {
+		t.Fatalf("UpdateList() = %v, %v", updated, err)
+	}
+
+	if _, err := r.UpdateList(ctx, &entities.List{ID: 9, Name: "office"}); err != repository.ErrDataNotFound {
+		t.Errorf("UpdateList() unknown list error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	lists, total, err := r.ListListsByPage(ctx, 2, 1)
+	if err != nil || total != 2 || len(lists) != 1 || lists[0].ID != work.ID {
+		t.Fatalf("ListListsByPage() = %v, %d, %v, want list %d of 2", lists, total, err, work.ID)
+	}
+
+	if err := r.DeleteList(ctx, entities.DefaultListID); err != repository.ErrInvalidData {
+		t.Errorf("DeleteList() default list error = %v, want %v", err, repository.ErrInvalidData)
+	}
+
+	if err := r.DeleteList(ctx, work.ID); err != nil {
+		t.Fatalf("DeleteList() error = %v", err)
+	}
+
+	if err := r.DeleteList(ctx, work.ID); err != repository.ErrDataNotFound {
+		t.Errorf("DeleteList() twice error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	// ids are not reused after a delete
+	next, err := r.CreateList(ctx, &entities.List{Name: "home"})
+	if err != nil || next.ID != 3 {
+		t.Errorf("CreateList() after delete = %v, %v, want id 3", next, err)
+	}
+}
+
+func TestListRepository_Restore(t *testing.T) {
+	t.Parallel()
+
+	r := NewListRepository()
+	r.Restore(map[string]ListSnapshot{workspace.Default: {Lists: []*entities.List{{ID: 4, Name: "work"}}, LastID: 6}})
+
+	snapshot := r.Snapshot()[workspace.Default]
+	if len(snapshot.Lists) != 2 || snapshot.Lists[0].ID != entities.DefaultListID || snapshot.Lists[1].ID != 4 || snapshot.LastID != 6 {
+		t.Errorf("Snapshot() = %v, want the default list, list 4 and last id 6", snapshot)
+	}
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"cmp"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+// matchTask reports whether a task passes every set field of the filter at the given time.
+func matchTask(t *entities.Task, f repository.TaskFilter, now time.Time) bool {
+	switch {
+	case f.Status != nil && t.Status != *f.Status:
+		return false
+	case f.ListID != 0 && t.ListID != f.ListID:
+		return false
+	case f.NameContains != "" && !strings.Contains(strings.ToLower(t.Name), strings.ToLower(f.NameContains)):
+		return false
+	case !f.CreatedSince.IsZero() && t.CreatedAt.Before(f.CreatedSince):
+		return false
+	case !f.CreatedBefore.IsZero() && !t.CreatedAt.Before(f.CreatedBefore):
+		return false
+	case !f.UpdatedSince.IsZero() && t.UpdatedAt.Before(f.UpdatedSince):
+		return false
+	case !f.UpdatedBefore.IsZero() && !t.UpdatedAt.Before(f.UpdatedBefore):
+		return false
+	case len(f.IDs) > 0 && !slices.Contains(f.IDs, t.ID):
+		return false
+	case f.MinPriority != nil && t.Priority < *f.MinPriority:
+		return false
+	case f.MaxPriority != nil && t.Priority > *f.MaxPriority:
+		return false
+	case slices.ContainsFunc(f.Tags, func(tag string) bool { return !slices.Contains(t.Tags, tag) }):
+		return false
+	case (!f.DueSince.IsZero() || !f.DueBefore.IsZero() || f.Overdue) && t.DueAt == nil:
+		return false
+	case !f.DueSince.IsZero() && t.DueAt.Before(f.DueSince):
+		return false
+	case !f.DueBefore.IsZero() && !t.DueAt.Before(f.DueBefore):
+		return false
+	case f.Overdue && (t.Status.Closed() || !t.DueAt.Before(now)):
+		return false
+	default:
+		return true
+	}
+}
+
+// ready reports whether a task is open and none of its blockers is a live open task.
+// Callers must hold s.mu.
+func (s *taskSpace) ready(t *entities.Task) bool {
+	if t.Status.Closed() {
+		return false
+	}
+
+	for _, id := range t.BlockedBy {
+		if blocker, ok := s.tasks[id]; ok && blocker.DeletedAt == nil && !blocker.Status.Closed() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareTasks orders two tasks by the sort keys, then by id.
+func compareTasks(a, b *entities.Task, keys []repository.SortKey) int {
+	for _, key := range keys {
+		var c int
+
+		switch key.Field {
+		case repository.SortFieldID:
+			c = cmp.Compare(a.ID, b.ID)
+		case repository.SortFieldName:
+			c = strings.Compare(a.Name, b.Name)
+		case repository.SortFieldStatus:
+			c = cmp.Compare(a.Status, b.Status)
+		case repository.SortFieldPriority:
+			c = cmp.Compare(a.Priority, b.Priority)
+		case repository.SortFieldCreatedAt:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		case repository.SortFieldUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		}
+
+		if key.Desc {
+			c = -c
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return cmp.Compare(a.ID, b.ID)
+}
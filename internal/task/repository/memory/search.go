@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/fulltext"
+	"math"
+	"slices"
+	"strings"
+)
+
+// prefixWeight discounts a word matched only by its prefix against one matched whole.
+const prefixWeight = 0.5
+
+// searchIndex is an inverted index of task names, kept up to date as tasks are written.
+// It indexes trashed tasks too, so moving a task in or out of the trash costs nothing; searches skip them.
+type searchIndex struct {
+	// postings maps a term to the ids of the tasks whose name contains it.
+	postings map[string]map[uint]struct{}
+	// terms holds the keys of postings in order, for prefix lookups.
+	terms []string
+	// docs maps a task id to the distinct terms of its name.
+	docs map[uint][]string
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings: make(map[string]map[uint]struct{}),
+		docs:     make(map[uint][]string),
+	}
+}
+
+// add indexes a task name, replacing what was indexed for the task before.
+func (idx *searchIndex) add(id uint, name string) {
+	idx.remove(id)
+
+	terms := fulltext.Terms(name)
+	idx.docs[id] = terms
+
+	for _, term := range terms {
+		ids, ok := idx.postings[term]
+		if !ok {
+			ids = make(map[uint]struct{})
+			idx.postings[term] = ids
+
+			i, _ := slices.BinarySearch(idx.terms, term)
+			idx.terms = slices.Insert(idx.terms, i, term)
+		}
+
+		ids[id] = struct{}{}
+	}
+}
+
+// remove drops a task from the index.
+func (idx *searchIndex) remove(id uint) {
+	for _, term := range idx.docs[id] {
+		ids := idx.postings[term]
+		delete(ids, id)
+
+		if len(ids) == 0 {
+			delete(idx.postings, term)
+
+			if i, ok := slices.BinarySearch(idx.terms, term); ok {
+				idx.terms = slices.Delete(idx.terms, i, i+1)
+			}
+		}
+	}
+
+	delete(idx.docs, id)
+}
+
+// search scores the tasks matching every term of the query. A term scores its inverse document
+// frequency, halved when it only matches as a prefix, and the sum is damped by the name length
+// so that a short name matching the query ranks above a long one.
+func (idx *searchIndex) search(q fulltext.Query) map[uint]float64 {
+	var scores map[uint]float64
+
+	for i, want := range q.Terms {
+		termScores := make(map[uint]float64)
+
+		for _, term := range idx.matchingTerms(q, i) {
+			weight := idf(len(idx.postings[term]), len(idx.docs))
+			if term != want {
+				weight *= prefixWeight
+			}
+
+			for id := range idx.postings[term] {
+				// a name may hold several words with the prefix; the best one counts
+				termScores[id] = max(termScores[id], weight)
+			}
+		}
+
+		if scores == nil {
+			scores = termScores
+
+			continue
+		}
+
+		for id := range scores {
+			if s, ok := termScores[id]; ok {
+				scores[id] += s
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	for id := range scores {
+		scores[id] /= math.Sqrt(float64(len(idx.docs[id])))
+	}
+
+	return scores
+}
+
+// matchingTerms returns the indexed terms matching the i-th term of the query.
+func (idx *searchIndex) matchingTerms(q fulltext.Query, i int) []string {
+	want := q.Terms[i]
+
+	if !q.Prefix || i != len(q.Terms)-1 {
+		if _, ok := idx.postings[want]; ok {
+			return []string{want}
+		}
+
+		return nil
+	}
+
+	start, _ := slices.BinarySearch(idx.terms, want)
+	end := start
+
+	for end < len(idx.terms) && strings.HasPrefix(idx.terms[end], want) {
+		end++
+	}
+
+	return idx.terms[start:end]
+}
+
+// idf is the smoothed inverse document frequency of a term found in df of n documents.
+func idf(df, n int) float64 {
+	return math.Log(1 + float64(n)/float64(df))
+}
+
+// SearchTasks is searching the live tasks by name, most relevant first.
+func (r *TaskRepository) SearchTasks(ctx context.Context, query repository.TaskSearchQuery) ([]*entities.TaskSearchHit, int, error) {
+	if !query.Valid() {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	s := r.space(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hits := make([]*entities.TaskSearchHit, 0)
+
+	for id, score := range s.index.search(fulltext.ParseQuery(query.Text)) {
+		if task := s.tasks[id]; task.DeletedAt == nil {
+			hits = append(hits, &entities.TaskSearchHit{Task: task, Score: score})
+		}
+	}
+
+	slices.SortFunc(hits, func(a, b *entities.TaskSearchHit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Task.ID, b.Task.ID)
+	})
+
+	total := len(hits)
+	start := min((query.PageIndex-1)*query.PageSize, total)
+	end := min(start+query.PageSize, total)
+
+	return hits[start:end], total, nil
+}
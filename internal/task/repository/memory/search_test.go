@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_SearchTasks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewTaskRepository()
+
+	for _, name := range []string{"Buy milk", "buy MILKSHAKE and cookies", "Walk the dog", "買牛奶", "milk"} {
+		_, err := r.CreateTask(ctx, &entities.Task{Name: name})
+		require.NoError(t, err)
+	}
+
+	search := func(text string) []uint {
+		return searchIDs(t, r, text)
+	}
+
+	assert.Equal(t, []uint{5, 1, 2}, search("milk"), "whole words outrank prefixes, short names long ones")
+	assert.Equal(t, []uint{5, 1}, search("milk "), "a complete word is not a prefix")
+	assert.Equal(t, []uint{1, 2}, search("BUY mil"))
+	assert.Equal(t, []uint{4}, search("買牛奶"))
+	assert.Empty(t, search("buy dog"))
+
+	// the index follows renames, trash and purges
+	_, err := r.UpdateTask(ctx, &entities.Task{ID: 3, Name: "Walk the milkman"})
+	require.NoError(t, err)
+	require.NoError(t, r.DeleteTask(ctx, 5))
+	assert.Equal(t, []uint{3, 2, 1}, search("mil"), "rarer words outrank common ones")
+
+	require.NoError(t, r.PurgeTask(ctx, 5))
+	_, err = r.RestoreTask(ctx, 5)
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+	assert.Empty(t, search("dog"))
+
+	restored := NewTaskRepository()
+	restored.Restore(r.Snapshot())
+	assert.Equal(t, []uint{3}, searchIDs(t, restored, "walk"))
+
+	_, _, err = r.SearchTasks(ctx, repository.TaskSearchQuery{Text: "!!", PageIndex: 1, PageSize: 10})
+	assert.ErrorIs(t, err, repository.ErrInvalidData)
+}
+
+func searchIDs(t *testing.T, r *TaskRepository, text string) []uint {
+	t.Helper()
+
+	hits, total, err := r.SearchTasks(context.Background(), repository.TaskSearchQuery{Text: text, PageIndex: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, hits, total)
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.Task.ID)
+	}
+
+	return ids
+}
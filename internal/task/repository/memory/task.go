@@ -1,127 +1,468 @@
 package memory
 
 import (
+	"cmp"
 	"context"
 	"ggltask/internal/task/domain/entities"
 	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"slices"
 	"sort"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 var _ repository.Repository = (*TaskRepository)(nil)
 
 // TaskRepository is a repository for tasks.
-// It is a memory repository that uses a map to store tasks.
+// It is a memory repository that keeps the tasks of each workspace in a map of their own,
+// with ids allocated per workspace. Every method works in the workspace of its context.
 type TaskRepository struct {
+	// mu guards the workspaces map; each workspace has its own lock for its tasks.
+	mu         sync.RWMutex
+	workspaces map[string]*taskSpace
+}
+
+// taskSpace holds the tasks of a workspace.
+type taskSpace struct {
 	mu     sync.RWMutex
 	tasks  map[uint]*entities.Task
+	index  *searchIndex
 	lastID uint
 }
 
+// TaskSnapshot is the content of a workspace: its tasks ordered by id and the last allocated id.
+type TaskSnapshot struct {
+	Tasks  []*entities.Task
+	LastID uint
+}
+
 func NewTaskRepository() *TaskRepository {
 	return &TaskRepository{
+		workspaces: make(map[string]*taskSpace),
+	}
+}
+
+func newTaskSpace() *taskSpace {
+	return &taskSpace{
 		tasks: make(map[uint]*entities.Task),
+		index: newSearchIndex(),
+	}
+}
+
+// space returns the tasks of the workspace of ctx, creating them on first use.
+func (r *TaskRepository) space(ctx context.Context) *taskSpace {
+	id := workspace.FromContext(ctx)
+
+	r.mu.RLock()
+	s, ok := r.workspaces[id]
+	r.mu.RUnlock()
+
+	if ok {
+		return s
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok = r.workspaces[id]; !ok {
+		s = newTaskSpace()
+		r.workspaces[id] = s
 	}
+
+	return s
+}
+
+// Workspaces is listing the workspaces holding tasks, live or trashed, in order.
+func (r *TaskRepository) Workspaces(_ context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.workspaces))
+
+	for id, s := range r.workspaces {
+		s.mu.RLock()
+		if len(s.tasks) > 0 {
+			ids = append(ids, id)
+		}
+		s.mu.RUnlock()
+	}
+
+	slices.Sort(ids)
+
+	return ids, nil
 }
 
 // CreateTask is creating a new task.
-func (r *TaskRepository) CreateTask(_ context.Context, taskEntity *entities.Task) (*entities.Task, error) {
-	if taskEntity.Name == "" || len(taskEntity.Name) > 50 || !taskEntity.Status.Valid() {
+func (r *TaskRepository) CreateTask(ctx context.Context, taskEntity *entities.Task) (*entities.Task, error) {
+	if taskEntity.Name == "" || utf8.RuneCountInString(taskEntity.Name) > 50 || !taskEntity.Status.Valid() {
 		return nil, repository.ErrInvalidData
 	}
 
-	r.mu.Lock()
-	taskEntity.ID = r.lastID + 1
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	taskEntity.ID = s.lastID + 1
+	taskEntity.ListID = listOrDefault(taskEntity.ListID)
+	taskEntity.Version = 1
 	taskEntity.CreatedAt = time.Now()
 	taskEntity.UpdatedAt = time.Now()
 
-	r.lastID++
-	r.tasks[taskEntity.ID] = taskEntity
-	r.mu.Unlock()
+	s.lastID++
+	s.tasks[taskEntity.ID] = taskEntity
+	s.index.add(taskEntity.ID, taskEntity.Name)
+	s.mu.Unlock()
 
 	return taskEntity, nil
 }
 
 // GetTaskByID is getting a task by id.
-func (r *TaskRepository) GetTaskByID(_ context.Context, id uint) (*entities.Task, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+func (r *TaskRepository) GetTaskByID(ctx context.Context, id uint) (*entities.Task, error) {
+	s := r.space(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	task, ok := r.tasks[id]
-	if !ok {
+	task, ok := s.tasks[id]
+	if !ok || task.DeletedAt != nil {
 		return nil, repository.ErrDataNotFound
 	}
 
 	return task, nil
 }
 
-// ListTasksByPage is listing tasks by page.
-func (r *TaskRepository) ListTasksByPage(_ context.Context, pageIndex, pageSize int) ([]*entities.Task, int, error) {
-	if pageIndex < 1 || pageSize < 1 {
+// ListTasksByPage is listing the tasks matching the query by page.
+func (r *TaskRepository) ListTasksByPage(ctx context.Context, query repository.TaskQuery) ([]*entities.Task, int, error) {
+	if !query.Valid() {
 		return nil, 0, repository.ErrInvalidData
 	}
 
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	s := r.space(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return paginate(s.sortedTasks(query.Filter, query.Sort), query.PageIndex, query.PageSize)
+}
 
-	tasks := make([]*entities.Task, 0, len(r.tasks))
-	for _, task := range r.tasks {
-		tasks = append(tasks, task)
+// ListTasksByCursor is listing the tasks matching the query that follow or precede its cursor.
+func (r *TaskRepository) ListTasksByCursor(ctx context.Context, query repository.TaskCursorQuery) ([]*entities.Task, int, bool, error) {
+	if !query.Valid() {
+		return nil, 0, false, repository.ErrInvalidData
 	}
 
-	sort.Slice(tasks, func(i, j int) bool {
-		return tasks[i].ID < tasks[j].ID
-	})
+	s := r.space(ctx)
 
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := s.sortedTasks(query.Filter, query.Sort)
 	total := len(tasks)
 
-	start := (pageIndex - 1) * pageSize
-	end := start + pageSize
-	if end > total {
-		end = len(tasks)
+	if query.Backward {
+		end := len(tasks)
+		if query.Cursor != nil {
+			end, _ = slices.BinarySearchFunc(tasks, query.Cursor, func(t, cursor *entities.Task) int {
+				return compareTasks(t, cursor, query.Sort)
+			})
+		}
+
+		start := max(end-query.Limit, 0)
+
+		return tasks[start:end], total, start > 0, nil
 	}
 
-	if start > total {
-		return nil, 0, nil
+	start := 0
+	if query.Cursor != nil {
+		start, _ = slices.BinarySearchFunc(tasks, query.Cursor, func(t, cursor *entities.Task) int {
+			// ties sort before the cursor, so the search lands just past it
+			if c := compareTasks(t, cursor, query.Sort); c != 0 {
+				return c
+			}
+
+			return -1
+		})
+	}
+
+	end := min(start+query.Limit, len(tasks))
+
+	return tasks[start:end], total, end < len(tasks), nil
+}
+
+// sortedTasks returns the live tasks matching the filter in sort order. Callers must hold s.mu.
+func (s *taskSpace) sortedTasks(filter repository.TaskFilter, sort []repository.SortKey) []*entities.Task {
+	now := time.Now()
+
+	tasks := make([]*entities.Task, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		if task.DeletedAt == nil && matchTask(task, filter, now) && (!filter.Ready || s.ready(task)) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	slices.SortFunc(tasks, func(a, b *entities.Task) int {
+		return compareTasks(a, b, sort)
+	})
+
+	return tasks
+}
+
+// ListChildTasks is listing the live subtasks of a task.
+func (r *TaskRepository) ListChildTasks(ctx context.Context, parentID uint) ([]*entities.Task, error) {
+	s := r.space(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	children := make([]*entities.Task, 0)
+	for _, task := range s.tasks {
+		if task.DeletedAt == nil && task.ParentID != nil && *task.ParentID == parentID {
+			children = append(children, task)
+		}
 	}
 
+	slices.SortFunc(children, func(a, b *entities.Task) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return children, nil
+}
+
+// paginate returns a page of tasks and the total. A page past the end is empty, not nil.
+func paginate(tasks []*entities.Task, pageIndex, pageSize int) ([]*entities.Task, int, error) {
+	total := len(tasks)
+
+	start := min((pageIndex-1)*pageSize, total)
+	end := min(start+pageSize, total)
+
 	return tasks[start:end], total, nil
 }
 
 // UpdateTask is updating a task.
-func (r *TaskRepository) UpdateTask(_ context.Context, taskEntity *entities.Task) (*entities.Task, error) {
-	if taskEntity.Name == "" || len(taskEntity.Name) > 50 || !taskEntity.Status.Valid() {
+func (r *TaskRepository) UpdateTask(ctx context.Context, taskEntity *entities.Task) (*entities.Task, error) {
+	if taskEntity.Name == "" || utf8.RuneCountInString(taskEntity.Name) > 50 || !taskEntity.Status.Valid() {
 		return nil, repository.ErrInvalidData
 	}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	task, ok := r.tasks[taskEntity.ID]
-	if !ok {
+	task, ok := s.tasks[taskEntity.ID]
+	if !ok || task.DeletedAt != nil {
 		return nil, repository.ErrDataNotFound
 	}
 
+	if taskEntity.Version != 0 && taskEntity.Version != task.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
 	task.Name = taskEntity.Name
+	task.ListID = listOrDefault(taskEntity.ListID)
+	task.Description = taskEntity.Description
 	task.Status = taskEntity.Status
+	task.Priority = taskEntity.Priority
+	task.DueAt = taskEntity.DueAt
+	task.Tags = taskEntity.Tags
+	task.ParentID = taskEntity.ParentID
+	task.BlockedBy = taskEntity.BlockedBy
+	task.Recurrence = taskEntity.Recurrence
+	task.Version++
 	task.UpdatedAt = time.Now()
-	r.tasks[taskEntity.ID] = task
+	s.tasks[taskEntity.ID] = task
+	s.index.add(task.ID, task.Name)
 
 	return task, nil
 }
 
-// DeleteTask is deleting a task.
-func (r *TaskRepository) DeleteTask(_ context.Context, id uint) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// DeleteTask is moving a task to the trash.
+func (r *TaskRepository) DeleteTask(ctx context.Context, id uint) error {
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	_, ok := r.tasks[id]
-	if !ok {
+	task, ok := s.tasks[id]
+	if !ok || task.DeletedAt != nil {
 		return repository.ErrDataNotFound
 	}
 
-	delete(r.tasks, id)
+	now := time.Now()
+	task.DeletedAt = &now
 
 	return nil
 }
+
+// ListDeletedTasksByPage is listing trashed tasks by page, most recently deleted first.
+func (r *TaskRepository) ListDeletedTasksByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.Task, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	s := r.space(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := make([]*entities.Task, 0)
+	for _, task := range s.tasks {
+		if task.DeletedAt != nil {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].DeletedAt.Equal(*tasks[j].DeletedAt) {
+			return tasks[i].DeletedAt.After(*tasks[j].DeletedAt)
+		}
+
+		return tasks[i].ID < tasks[j].ID
+	})
+
+	return paginate(tasks, pageIndex, pageSize)
+}
+
+// GetDeletedTaskByID is getting a trashed task by id.
+func (r *TaskRepository) GetDeletedTaskByID(ctx context.Context, id uint) (*entities.Task, error) {
+	s := r.space(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, ok := s.tasks[id]
+	if !ok || task.DeletedAt == nil {
+		return nil, repository.ErrDataNotFound
+	}
+
+	return task, nil
+}
+
+// RestoreTask is moving a task out of the trash.
+func (r *TaskRepository) RestoreTask(ctx context.Context, id uint) (*entities.Task, error) {
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[id]
+	if !ok || task.DeletedAt == nil {
+		return nil, repository.ErrDataNotFound
+	}
+
+	task.DeletedAt = nil
+	task.Version++
+	task.UpdatedAt = time.Now()
+
+	return task, nil
+}
+
+// PurgeTask is permanently removing a trashed task.
+func (r *TaskRepository) PurgeTask(ctx context.Context, id uint) error {
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[id]
+	if !ok || task.DeletedAt == nil {
+		return repository.ErrDataNotFound
+	}
+
+	delete(s.tasks, id)
+	s.index.remove(id)
+
+	return nil
+}
+
+// PurgeDeletedTasks is permanently removing the tasks trashed before the given time.
+func (r *TaskRepository) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) ([]uint, error) {
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := make([]uint, 0)
+
+	for id, task := range s.tasks {
+		if task.DeletedAt != nil && task.DeletedAt.Before(deletedBefore) {
+			delete(s.tasks, id)
+			s.index.remove(id)
+			purged = append(purged, id)
+		}
+	}
+
+	sort.Slice(purged, func(i, j int) bool {
+		return purged[i] < purged[j]
+	})
+
+	return purged, nil
+}
+
+// Lookup is getting a task by id, whether it is in the trash or not.
+func (r *TaskRepository) Lookup(ctx context.Context, id uint) (*entities.Task, bool) {
+	s := r.space(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, ok := s.tasks[id]
+
+	return task, ok
+}
+
+// Snapshot is returning the content of every workspace holding tasks, by workspace.
+func (r *TaskRepository) Snapshot() map[string]TaskSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := make(map[string]TaskSnapshot, len(r.workspaces))
+
+	for id, s := range r.workspaces {
+		s.mu.RLock()
+
+		if len(s.tasks) > 0 || s.lastID > 0 {
+			tasks := make([]*entities.Task, 0, len(s.tasks))
+			for _, task := range s.tasks {
+				tasks = append(tasks, task)
+			}
+
+			sort.Slice(tasks, func(i, j int) bool {
+				return tasks[i].ID < tasks[j].ID
+			})
+
+			snapshots[id] = TaskSnapshot{Tasks: tasks, LastID: s.lastID}
+		}
+
+		s.mu.RUnlock()
+	}
+
+	return snapshots
+}
+
+// Restore is replacing the repository content, e.g. with state recovered from disk.
+// Tasks stored before lists existed are put in the default list.
+func (r *TaskRepository) Restore(snapshots map[string]TaskSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.workspaces = make(map[string]*taskSpace, len(snapshots))
+
+	for id, snapshot := range snapshots {
+		s := newTaskSpace()
+		s.lastID = snapshot.LastID
+
+		for _, task := range snapshot.Tasks {
+			task.ListID = listOrDefault(task.ListID)
+			s.tasks[task.ID] = task
+			s.index.add(task.ID, task.Name)
+
+			if task.ID > s.lastID {
+				s.lastID = task.ID
+			}
+		}
+
+		r.workspaces[id] = s
+	}
+}
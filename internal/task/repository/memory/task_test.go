@@ -7,9 +7,12 @@ import (
 	"ggltask/internal/task"
 	"ggltask/internal/task/domain/entities"
 	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/goleak"
 )
@@ -67,11 +70,27 @@ func TestTaskRepository_TestCreateTask(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "name of 50 multibyte characters",
+			task: &entities.Task{
+				Name:   strings.Repeat("é", 50),
+				Status: task.TaskStatusIncomplete,
+			},
+			wantErr: false,
+		},
+		{
+			name: "name of 51 multibyte characters",
+			task: &entities.Task{
+				Name:   strings.Repeat("é", 51),
+				Status: task.TaskStatusIncomplete,
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid status",
 			task: &entities.Task{
 				Name:   "test task",
-				Status: task.TaskStatus(2),
+				Status: task.TaskStatus(-1),
 			},
 			wantErr: true,
 		},
@@ -128,7 +147,7 @@ func TestTaskRepository_GetTaskByID(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(r *TaskRepository) {
-				r.tasks[1] = &entities.Task{
+				r.space(context.Background()).tasks[1] = &entities.Task{
 					ID:     1,
 					Name:   "test task",
 					Status: task.TaskStatusIncomplete,
@@ -150,7 +169,7 @@ func TestTaskRepository_GetTaskByID(t *testing.T) {
 			name: "zero id",
 			id:   0,
 			setup: func(r *TaskRepository) {
-				r.tasks[0] = &entities.Task{
+				r.space(context.Background()).tasks[0] = &entities.Task{
 					ID:     0,
 					Name:   "test task",
 					Status: task.TaskStatusIncomplete,
@@ -166,7 +185,7 @@ func TestTaskRepository_GetTaskByID(t *testing.T) {
 			name: "max uint id",
 			id:   ^uint(0),
 			setup: func(r *TaskRepository) {
-				r.tasks[^uint(0)] = &entities.Task{
+				r.space(context.Background()).tasks[^uint(0)] = &entities.Task{
 					ID:     ^uint(0),
 					Name:   "test task",
 					Status: task.TaskStatusIncomplete,
@@ -248,8 +267,8 @@ func TestTaskRepository_ListTasksByPage(t *testing.T) {
 			pageIndex: 1,
 			pageSize:  10,
 			setup: func(r *TaskRepository) {
-				r.tasks[1] = &entities.Task{ID: 1, Name: "task 1", Status: task.TaskStatusIncomplete}
-				r.tasks[2] = &entities.Task{ID: 2, Name: "task 2", Status: task.TaskStatusCompleted}
+				r.space(context.Background()).tasks[1] = &entities.Task{ID: 1, Name: "task 1", Status: task.TaskStatusIncomplete}
+				r.space(context.Background()).tasks[2] = &entities.Task{ID: 2, Name: "task 2", Status: task.TaskStatusCompleted}
 			},
 			want: []*entities.Task{
 				{ID: 1, Name: "task 1", Status: task.TaskStatusIncomplete},
@@ -263,7 +282,7 @@ func TestTaskRepository_ListTasksByPage(t *testing.T) {
 			pageSize:  2,
 			setup: func(r *TaskRepository) {
 				for i := uint(1); i <= 5; i++ {
-					r.tasks[i] = &entities.Task{ID: i, Name: fmt.Sprintf("task %d", i), Status: task.TaskStatusIncomplete}
+					r.space(context.Background()).tasks[i] = &entities.Task{ID: i, Name: fmt.Sprintf("task %d", i), Status: task.TaskStatusIncomplete}
 				}
 			},
 			want: []*entities.Task{
@@ -278,7 +297,7 @@ func TestTaskRepository_ListTasksByPage(t *testing.T) {
 			pageSize:  2,
 			setup: func(r *TaskRepository) {
 				for i := uint(1); i <= 5; i++ {
-					r.tasks[i] = &entities.Task{ID: i, Name: fmt.Sprintf("task %d", i), Status: task.TaskStatusIncomplete}
+					r.space(context.Background()).tasks[i] = &entities.Task{ID: i, Name: fmt.Sprintf("task %d", i), Status: task.TaskStatusIncomplete}
 				}
 			},
 			want: []*entities.Task{
@@ -292,11 +311,11 @@ func TestTaskRepository_ListTasksByPage(t *testing.T) {
 			pageSize:  2,
 			setup: func(r *TaskRepository) {
 				for i := uint(1); i <= 5; i++ {
-					r.tasks[i] = &entities.Task{ID: i, Name: fmt.Sprintf("task %d", i), Status: task.TaskStatusIncomplete}
+					r.space(context.Background()).tasks[i] = &entities.Task{ID: i, Name: fmt.Sprintf("task %d", i), Status: task.TaskStatusIncomplete}
 				}
 			},
-			want:      nil,
-			wantTotal: 0,
+			want:      []*entities.Task{},
+			wantTotal: 5,
 		},
 	}
 
@@ -308,7 +327,7 @@ func TestTaskRepository_ListTasksByPage(t *testing.T) {
 			r := NewTaskRepository()
 			tt.setup(r)
 
-			got, total, err := r.ListTasksByPage(context.Background(), tt.pageIndex, tt.pageSize)
+			got, total, err := r.ListTasksByPage(context.Background(), repository.TaskQuery{PageIndex: tt.pageIndex, PageSize: tt.pageSize})
 			if err != tt.wantErr {
 				t.Errorf("ListTasksByPage() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -321,8 +340,8 @@ func TestTaskRepository_ListTasksByPage(t *testing.T) {
 				t.Errorf("ListTasksByPage() total = %v, want %v", total, tt.wantTotal)
 			}
 
-			if len(got) != len(tt.want) {
-				t.Errorf("ListTasksByPage() got = %v items, want %v items", len(got), len(tt.want))
+			if got == nil || len(got) != len(tt.want) {
+				t.Errorf("ListTasksByPage() got = %v items, want %v items", got, len(tt.want))
 				return
 			}
 
@@ -354,7 +373,7 @@ func TestTaskRepository_UpdateTask(t *testing.T) {
 		{
 			name: "success",
 			setup: func(r *TaskRepository) {
-				r.tasks[1] = &entities.Task{ID: 1, Name: "task 1", Status: task.TaskStatusIncomplete}
+				r.space(context.Background()).tasks[1] = &entities.Task{ID: 1, Name: "task 1", Status: task.TaskStatusIncomplete}
 			},
 			task: &entities.Task{ID: 1, Name: "updated task", Status: task.TaskStatusCompleted},
 			want: &entities.Task{ID: 1, Name: "updated task", Status: task.TaskStatusCompleted},
@@ -374,7 +393,7 @@ func TestTaskRepository_UpdateTask(t *testing.T) {
 		{
 			name:    "invalid status",
 			setup:   func(r *TaskRepository) {},
-			task:    &entities.Task{ID: 1, Name: "task", Status: task.TaskStatus(2)},
+			task:    &entities.Task{ID: 1, Name: "task", Status: task.TaskStatus(-1)},
 			wantErr: repository.ErrInvalidData,
 		},
 		{
@@ -383,6 +402,22 @@ func TestTaskRepository_UpdateTask(t *testing.T) {
 			task:    &entities.Task{ID: 999, Name: "task", Status: task.TaskStatusCompleted},
 			wantErr: repository.ErrDataNotFound,
 		},
+		{
+			name: "matching version",
+			setup: func(r *TaskRepository) {
+				r.space(context.Background()).tasks[1] = &entities.Task{ID: 1, Name: "task 1", Status: task.TaskStatusIncomplete, Version: 2}
+			},
+			task: &entities.Task{ID: 1, Name: "updated task", Status: task.TaskStatusCompleted, Version: 2},
+			want: &entities.Task{ID: 1, Name: "updated task", Status: task.TaskStatusCompleted, Version: 3},
+		},
+		{
+			name: "stale version",
+			setup: func(r *TaskRepository) {
+				r.space(context.Background()).tasks[1] = &entities.Task{ID: 1, Name: "task 1", Status: task.TaskStatusIncomplete, Version: 2}
+			},
+			task:    &entities.Task{ID: 1, Name: "updated task", Status: task.TaskStatusCompleted, Version: 1},
+			wantErr: repository.ErrVersionConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -414,6 +449,9 @@ func TestTaskRepository_UpdateTask(t *testing.T) {
 			if got.UpdatedAt.IsZero() {
 				t.Error("UpdateTask() got.UpdatedAt is zero")
 			}
+			if tt.want.Version != 0 && got.Version != tt.want.Version {
+				t.Errorf("UpdateTask() got.Version = %v, want %v", got.Version, tt.want.Version)
+			}
 		})
 	}
 }
@@ -430,7 +468,7 @@ func TestTaskRepository_DeleteTask(t *testing.T) {
 		{
 			name: "success",
 			setup: func(r *TaskRepository) {
-				r.tasks[1] = &entities.Task{
+				r.space(context.Background()).tasks[1] = &entities.Task{
 					ID:     1,
 					Name:   "task",
 					Status: task.TaskStatusCompleted,
@@ -472,3 +510,344 @@ func TestTaskRepository_DeleteTask(t *testing.T) {
 	}
 }
 
+func TestTaskRepository_SnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	r := NewTaskRepository()
+	for i := 0; i < 3; i++ {
+		if _, err := r.CreateTask(context.Background(), &entities.Task{Name: fmt.Sprintf("task %d", i)}); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+
+	if err := r.DeleteTask(context.Background(), 3); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	snapshot := r.Snapshot()[workspace.Default]
+	if len(snapshot.Tasks) != 3 || snapshot.LastID != 3 {
+		t.Fatalf("Snapshot() got %d tasks and lastID %d, want 3 and 3", len(snapshot.Tasks), snapshot.LastID)
+	}
+
+	restored := NewTaskRepository()
+	restored.Restore(r.Snapshot())
+
+	if _, err := restored.GetTaskByID(context.Background(), 3); err != repository.ErrDataNotFound {
+		t.Errorf("GetTaskByID() after Restore got error = %v, want trashed task hidden", err)
+	}
+
+	created, err := restored.CreateTask(context.Background(), &entities.Task{Name: "task 4"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	if created.ID != 4 {
+		t.Errorf("CreateTask() after Restore got ID = %d, want 4", created.ID)
+	}
+}
+
+func TestTaskRepository_Trash(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewTaskRepository()
+
+	for i := 1; i <= 3; i++ {
+		if _, err := r.CreateTask(ctx, &entities.Task{Name: fmt.Sprintf("task %d", i)}); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+
+	for _, id := range []uint{1, 2} {
+		if err := r.DeleteTask(ctx, id); err != nil {
+			t.Fatalf("DeleteTask() error = %v", err)
+		}
+	}
+
+	if err := r.DeleteTask(ctx, 1); err != repository.ErrDataNotFound {
+		t.Errorf("DeleteTask() of a trashed task error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	if _, total, _ := r.ListTasksByPage(ctx, repository.TaskQuery{PageIndex: 1, PageSize: 10}); total != 1 {
+		t.Errorf("ListTasksByPage() total = %d, want 1", total)
+	}
+
+	trashed, total, err := r.ListDeletedTasksByPage(ctx, 1, 10)
+	if err != nil || total != 2 {
+		t.Fatalf("ListDeletedTasksByPage() got total = %d, error = %v, want 2 and nil", total, err)
+	}
+
+	if trashed[0].DeletedAt == nil || trashed[1].DeletedAt == nil {
+		t.Errorf("ListDeletedTasksByPage() got tasks without DeletedAt")
+	}
+
+	if got, err := r.GetDeletedTaskByID(ctx, 2); err != nil || got.ID != 2 {
+		t.Errorf("GetDeletedTaskByID() got %v, error = %v, want task 2", got, err)
+	}
+
+	if _, err := r.GetDeletedTaskByID(ctx, 3); err != repository.ErrDataNotFound {
+		t.Errorf("GetDeletedTaskByID() of a live task error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	restored, err := r.RestoreTask(ctx, 1)
+	if err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+
+	if restored.DeletedAt != nil || restored.Version != 2 {
+		t.Errorf("RestoreTask() got DeletedAt = %v, Version = %d, want nil and 2", restored.DeletedAt, restored.Version)
+	}
+
+	if _, err := r.RestoreTask(ctx, 3); err != repository.ErrDataNotFound {
+		t.Errorf("RestoreTask() of a live task error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	if err := r.PurgeTask(ctx, 3); err != repository.ErrDataNotFound {
+		t.Errorf("PurgeTask() of a live task error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	purged, err := r.PurgeDeletedTasks(ctx, time.Now().Add(time.Minute))
+	if err != nil || !reflect.DeepEqual(purged, []uint{2}) {
+		t.Errorf("PurgeDeletedTasks() got %v, error = %v, want [2] and nil", purged, err)
+	}
+
+	if _, ok := r.Lookup(ctx, 2); ok {
+		t.Errorf("Lookup() found purged task 2")
+	}
+}
+
+func TestTaskRepository_ListChildTasks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewTaskRepository()
+	parentID := uint(1)
+
+	tasks := []*entities.Task{
+		{Name: "parent"},
+		{Name: "child", ParentID: &parentID},
+		{Name: "top-level"},
+		{Name: "trashed child", ParentID: &parentID},
+		{Name: "other child", ParentID: &parentID},
+	}
+	for _, task := range tasks {
+		if _, err := r.CreateTask(ctx, task); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+
+	if err := r.DeleteTask(ctx, 4); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	children, err := r.ListChildTasks(ctx, parentID)
+	if err != nil {
+		t.Fatalf("ListChildTasks() error = %v", err)
+	}
+
+	ids := make([]uint, 0, len(children))
+	for _, child := range children {
+		ids = append(ids, child.ID)
+	}
+
+	if !reflect.DeepEqual(ids, []uint{2, 5}) {
+		t.Errorf("ListChildTasks() got ids %v, want [2 5]", ids)
+	}
+
+	if children, _ := r.ListChildTasks(ctx, 3); len(children) != 0 {
+		t.Errorf("ListChildTasks() of a task without subtasks got %d tasks, want none", len(children))
+	}
+}
+
+func TestTaskRepository_ListTasksByPage_FilterAndSort(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	completed := task.TaskStatusCompleted
+	low, medium := task.PriorityLow, task.PriorityMedium
+	future := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	dueDates := []*time.Time{&base, &base, &future, nil}
+	tags := [][]string{{"ops"}, {"db", "ops"}, {}, {"db"}}
+	blockedBy := [][]uint{{2}, nil, {1}, nil}
+
+	r := NewTaskRepository()
+	for i, name := range []string{"Buy milk", "buy bread", "walk dog", "Buy eggs"} {
+		id := uint(i + 1)
+		r.space(context.Background()).tasks[id] = &entities.Task{
+			ID:        id,
+			Name:      name,
+			Status:    task.TaskStatus(i % 2),
+			Priority:  task.Priority(i),
+			DueAt:     dueDates[i],
+			Tags:      tags[i],
+			BlockedBy: blockedBy[i],
+			CreatedAt: base.Add(time.Duration(i) * time.Hour),
+			UpdatedAt: base.Add(time.Duration(4-i) * time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   repository.TaskQuery
+		wantIDs []uint
+		wantErr error
+	}{
+		{
+			name:    "name substring is case-insensitive",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{NameContains: "BUY"}},
+			wantIDs: []uint{1, 2, 4},
+		},
+		{
+			name:    "status",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{Status: &completed}},
+			wantIDs: []uint{2, 4},
+		},
+		{
+			name: "created range is half-open",
+			query: repository.TaskQuery{Filter: repository.TaskFilter{
+				CreatedSince:  base.Add(time.Hour),
+				CreatedBefore: base.Add(3 * time.Hour),
+			}},
+			wantIDs: []uint{2, 3},
+		},
+		{
+			name:    "updated since",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{UpdatedSince: base.Add(3 * time.Hour)}},
+			wantIDs: []uint{1, 2},
+		},
+		{
+			name:    "ids",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{IDs: []uint{4, 1, 9}}},
+			wantIDs: []uint{1, 4},
+		},
+		{
+			name:    "minimum priority",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{MinPriority: &medium}},
+			wantIDs: []uint{3, 4},
+		},
+		{
+			name:    "priority range",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{MinPriority: &low, MaxPriority: &medium}},
+			wantIDs: []uint{2, 3},
+		},
+		{
+			name:    "every tag",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{Tags: []string{"ops", "db"}}},
+			wantIDs: []uint{2},
+		},
+		{
+			name:    "due before skips tasks without a due date",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{DueBefore: future}},
+			wantIDs: []uint{1, 2},
+		},
+		{
+			name:    "overdue",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{Overdue: true}},
+			wantIDs: []uint{1},
+		},
+		{
+			name:    "ready skips the tasks an incomplete task blocks",
+			query:   repository.TaskQuery{Filter: repository.TaskFilter{Ready: true}},
+			wantIDs: []uint{1},
+		},
+		{
+			name:    "descending priority",
+			query:   repository.TaskQuery{Sort: []repository.SortKey{{Field: repository.SortFieldPriority, Desc: true}}},
+			wantIDs: []uint{4, 3, 2, 1},
+		},
+		{
+			name:    "multi-key sort",
+			query:   repository.TaskQuery{Sort: []repository.SortKey{{Field: repository.SortFieldStatus, Desc: true}, {Field: repository.SortFieldCreatedAt}}},
+			wantIDs: []uint{2, 4, 1, 3},
+		},
+		{
+			name:    "descending updated_at",
+			query:   repository.TaskQuery{Sort: []repository.SortKey{{Field: repository.SortFieldUpdatedAt, Desc: true}}},
+			wantIDs: []uint{1, 2, 3, 4},
+		},
+		{
+			name:    "unknown sort field",
+			query:   repository.TaskQuery{Sort: []repository.SortKey{{Field: "owner"}}},
+			wantErr: repository.ErrInvalidData,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.query.PageIndex, tt.query.PageSize = 1, 10
+
+			got, total, err := r.ListTasksByPage(context.Background(), tt.query)
+			if err != tt.wantErr {
+				t.Fatalf("ListTasksByPage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			ids := make([]uint, 0, len(got))
+			for _, task := range got {
+				ids = append(ids, task.ID)
+			}
+
+			if tt.wantErr == nil && (total != len(tt.wantIDs) || !reflect.DeepEqual(ids, tt.wantIDs)) {
+				t.Errorf("ListTasksByPage() got ids %v total %d, want %v", ids, total, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestTaskRepository_ListTasksByCursor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewTaskRepository()
+
+	for i := 1; i <= 5; i++ {
+		if _, err := r.CreateTask(ctx, &entities.Task{Name: fmt.Sprintf("task %d", i)}); err != nil {
+			t.Fatalf("CreateTask() error = %v", err)
+		}
+	}
+
+	byNameDesc := []repository.SortKey{{Field: repository.SortFieldName, Desc: true}}
+	ids := func(tasks []*entities.Task) []uint {
+		got := make([]uint, 0, len(tasks))
+		for _, task := range tasks {
+			got = append(got, task.ID)
+		}
+
+		return got
+	}
+
+	first, total, hasMore, err := r.ListTasksByCursor(ctx, repository.TaskCursorQuery{Sort: byNameDesc, Limit: 2})
+	if err != nil || total != 5 || !hasMore || !reflect.DeepEqual(ids(first), []uint{5, 4}) {
+		t.Fatalf("ListTasksByCursor() first page got %v total %d hasMore %v error %v", ids(first), total, hasMore, err)
+	}
+
+	// a task deleted before the cursor and one created ahead of it do not shift the next page
+	if err := r.DeleteTask(ctx, 5); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	if _, err := r.CreateTask(ctx, &entities.Task{Name: "task 9"}); err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	second, _, hasMore, err := r.ListTasksByCursor(ctx, repository.TaskCursorQuery{Sort: byNameDesc, Cursor: first[1], Limit: 2})
+	if err != nil || !hasMore || !reflect.DeepEqual(ids(second), []uint{3, 2}) {
+		t.Fatalf("ListTasksByCursor() second page got %v hasMore %v error %v", ids(second), hasMore, err)
+	}
+
+	last, _, hasMore, err := r.ListTasksByCursor(ctx, repository.TaskCursorQuery{Sort: byNameDesc, Cursor: second[1], Limit: 2})
+	if err != nil || hasMore || !reflect.DeepEqual(ids(last), []uint{1}) {
+		t.Fatalf("ListTasksByCursor() last page got %v hasMore %v error %v", ids(last), hasMore, err)
+	}
+
+	prev, _, hasMore, err := r.ListTasksByCursor(ctx, repository.TaskCursorQuery{Sort: byNameDesc, Cursor: second[0], Backward: true, Limit: 2})
+	if err != nil || hasMore || !reflect.DeepEqual(ids(prev), []uint{6, 4}) {
+		t.Fatalf("ListTasksByCursor() backward page got %v hasMore %v error %v", ids(prev), hasMore, err)
+	}
+
+	if _, _, _, err := r.ListTasksByCursor(ctx, repository.TaskCursorQuery{Limit: 0}); err != repository.ErrInvalidData {
+		t.Errorf("ListTasksByCursor() with zero limit error = %v, want %v", err, repository.ErrInvalidData)
+	}
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"maps"
+	"slices"
+)
+
+// WithinTransaction runs fn against a copy of the repository and keeps the copy only when fn succeeds.
+func (r *TaskRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context, tx repository.Repository) error) error {
+	return r.Transaction(ctx, func(tx *TaskRepository) error {
+		return fn(ctx, tx)
+	})
+}
+
+// Transaction runs fn against a copy of the workspace of ctx and, when fn returns nil, replaces the
+// workspace content with the copy. Writes to the workspace wait until the transaction ends, so none
+// is lost; reads wait too, so none sees a half-applied transaction. The copy holds that workspace
+// only: the transaction cannot reach the tasks of another one.
+func (r *TaskRepository) Transaction(ctx context.Context, fn func(tx *TaskRepository) error) error {
+	s := r.space(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txSpace := s.clone()
+	tx := &TaskRepository{
+		workspaces: map[string]*taskSpace{workspace.FromContext(ctx): txSpace},
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.tasks = txSpace.tasks
+	s.index = txSpace.index
+	s.lastID = txSpace.lastID
+
+	return nil
+}
+
+// clone returns a deep copy of the workspace. Callers must hold s.mu.
+func (s *taskSpace) clone() *taskSpace {
+	tasks := make(map[uint]*entities.Task, len(s.tasks))
+	for id, task := range s.tasks {
+		// the tasks are updated in place, so the copy gets its own
+		t := *task
+		tasks[id] = &t
+	}
+
+	return &taskSpace{
+		tasks:  tasks,
+		index:  s.index.clone(),
+		lastID: s.lastID,
+	}
+}
+
+// clone returns a deep copy of the index.
+func (idx *searchIndex) clone() *searchIndex {
+	postings := make(map[string]map[uint]struct{}, len(idx.postings))
+	for term, ids := range idx.postings {
+		postings[term] = maps.Clone(ids)
+	}
+
+	return &searchIndex{
+		postings: postings,
+		terms:    slices.Clone(idx.terms),
+		// the term lists are replaced, never modified, so they can be shared
+		docs: maps.Clone(idx.docs),
+	}
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_WithinTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewTaskRepository()
+
+	_, err := r.CreateTask(ctx, &entities.Task{Name: "task 1"})
+	require.NoError(t, err)
+
+	errRollback := errors.New("rollback")
+
+	err = r.WithinTransaction(ctx, func(ctx context.Context, tx repository.Repository) error {
+		_, err := tx.UpdateTask(ctx, &entities.Task{ID: 1, Name: "renamed"})
+		require.NoError(t, err)
+		_, err = tx.CreateTask(ctx, &entities.Task{Name: "task 2"})
+		require.NoError(t, err)
+
+		// the transaction sees its own writes, the repository does not
+		got, err := tx.GetTaskByID(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "task 2", got.Name)
+
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	got, err := r.GetTaskByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "task 1", got.Name)
+	assert.Equal(t, uint(1), got.Version)
+
+	_, err = r.GetTaskByID(ctx, 2)
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+	assert.Empty(t, searchIDs(t, r, "renamed"))
+
+	err = r.WithinTransaction(ctx, func(ctx context.Context, tx repository.Repository) error {
+		_, err := tx.UpdateTask(ctx, &entities.Task{ID: 1, Name: "renamed"})
+		require.NoError(t, err)
+		_, err = tx.CreateTask(ctx, &entities.Task{Name: "task 2"})
+		require.NoError(t, err)
+
+		return tx.DeleteTask(ctx, 2)
+	})
+	require.NoError(t, err)
+
+	got, err = r.GetTaskByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", got.Name)
+	assert.Equal(t, []uint{1}, searchIDs(t, r, "renamed"))
+
+	trashed, total, err := r.ListDeletedTasksByPage(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, uint(2), trashed[0].ID)
+}
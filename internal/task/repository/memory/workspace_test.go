@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTaskRepository_WorkspaceIsolation(t *testing.T) {
+	t.Parallel()
+
+	r := NewTaskRepository()
+	acme := workspace.WithWorkspace(context.Background(), "acme")
+	globex := workspace.WithWorkspace(context.Background(), "globex")
+
+	secret, err := r.CreateTask(acme, &entities.Task{Name: "acme secret"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	// ids are handed out per workspace, so both workspaces own a task 1
+	own, err := r.CreateTask(globex, &entities.Task{Name: "globex task"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	if secret.ID != 1 || own.ID != 1 {
+		t.Errorf("CreateTask() ids = %d, %d, want 1, 1", secret.ID, own.ID)
+	}
+
+	if got, err := r.GetTaskByID(globex, 1); err != nil || got.Name != "globex task" {
+		t.Errorf("GetTaskByID() = %v, %v, want the globex task", got, err)
+	}
+
+	second, err := r.CreateTask(acme, &entities.Task{Name: "acme plan"})
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	if _, err := r.GetTaskByID(globex, second.ID); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("GetTaskByID() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	if _, err := r.UpdateTask(globex, &entities.Task{ID: second.ID, Name: "stolen"}); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("UpdateTask() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	if err := r.DeleteTask(globex, second.ID); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("DeleteTask() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	if err := r.PurgeTask(globex, second.ID); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("PurgeTask() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	tasks, total, err := r.ListTasksByPage(globex, repository.TaskQuery{PageIndex: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListTasksByPage() error = %v", err)
+	}
+
+	if total != 1 || tasks[0].Name != "globex task" {
+		t.Errorf("ListTasksByPage() = %v, %d, want the globex task only", tasks, total)
+	}
+
+	hits, total, err := r.SearchTasks(globex, repository.TaskSearchQuery{Text: "acme", PageIndex: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("SearchTasks() error = %v", err)
+	}
+
+	if total != 0 || len(hits) != 0 {
+		t.Errorf("SearchTasks() = %v, %d, want no hit", hits, total)
+	}
+
+	// the trash is partitioned too: globex can neither see, restore nor purge acme's deleted task
+	if err := r.DeleteTask(acme, second.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	if _, total, _ := r.ListDeletedTasksByPage(globex, 1, 10); total != 0 {
+		t.Errorf("ListDeletedTasksByPage() total = %d, want 0", total)
+	}
+
+	if _, err := r.RestoreTask(globex, second.ID); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("RestoreTask() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	if purged, _ := r.PurgeDeletedTasks(globex, time.Now().Add(time.Hour)); len(purged) != 0 {
+		t.Errorf("PurgeDeletedTasks() = %v, want none", purged)
+	}
+
+	if _, err := r.RestoreTask(acme, second.ID); err != nil {
+		t.Errorf("RestoreTask() error = %v", err)
+	}
+
+	workspaces, err := r.Workspaces(context.Background())
+	if err != nil {
+		t.Fatalf("Workspaces() error = %v", err)
+	}
+
+	if want := []string{"acme", "globex"}; !reflect.DeepEqual(workspaces, want) {
+		t.Errorf("Workspaces() = %v, want %v", workspaces, want)
+	}
+}
+
+func TestTaskRepository_WorkspaceTransaction(t *testing.T) {
+	t.Parallel()
+
+	r := NewTaskRepository()
+	acme := workspace.WithWorkspace(context.Background(), "acme")
+	globex := workspace.WithWorkspace(context.Background(), "globex")
+
+	if _, err := r.CreateTask(acme, &entities.Task{Name: "acme task"}); err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+
+	err := r.WithinTransaction(globex, func(ctx context.Context, tx repository.Repository) error {
+		if _, err := tx.GetTaskByID(ctx, 1); !errors.Is(err, repository.ErrDataNotFound) {
+			t.Errorf("GetTaskByID() error = %v, want %v", err, repository.ErrDataNotFound)
+		}
+
+		// even a context switched to another workspace cannot reach beyond the transaction
+		if _, err := tx.GetTaskByID(acme, 1); !errors.Is(err, repository.ErrDataNotFound) {
+			t.Errorf("GetTaskByID() error = %v, want %v", err, repository.ErrDataNotFound)
+		}
+
+		_, err := tx.CreateTask(ctx, &entities.Task{Name: "globex task"})
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction() error = %v", err)
+	}
+
+	if got, err := r.GetTaskByID(acme, 1); err != nil || got.Name != "acme task" {
+		t.Errorf("GetTaskByID() = %v, %v, want the acme task", got, err)
+	}
+
+	if got, err := r.GetTaskByID(globex, 1); err != nil || got.Name != "globex task" {
+		t.Errorf("GetTaskByID() = %v, %v, want the globex task", got, err)
+	}
+}
+
+func TestListRepository_WorkspaceIsolation(t *testing.T) {
+	t.Parallel()
+
+	r := NewListRepository()
+	acme := workspace.WithWorkspace(context.Background(), "acme")
+	globex := workspace.WithWorkspace(context.Background(), "globex")
+
+	work, err := r.CreateList(acme, &entities.List{Name: "work"})
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+
+	if _, err := r.GetListByID(globex, work.ID); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("GetListByID() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	if _, err := r.UpdateList(globex, &entities.List{ID: work.ID, Name: "stolen"}); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("UpdateList() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	if err := r.DeleteList(globex, work.ID); !errors.Is(err, repository.ErrDataNotFound) {
+		t.Errorf("DeleteList() error = %v, want %v", err, repository.ErrDataNotFound)
+	}
+
+	// every workspace starts with its own default list
+	lists, total, err := r.ListListsByPage(globex, 1, 10)
+	if err != nil {
+		t.Fatalf("ListListsByPage() error = %v", err)
+	}
+
+	if total != 1 || lists[0].ID != entities.DefaultListID {
+		t.Errorf("ListListsByPage() = %v, %d, want the default list only", lists, total)
+	}
+}
+
+func TestHistoryRepository_WorkspaceIsolation(t *testing.T) {
+	t.Parallel()
+
+	r := NewHistoryRepository()
+	acme := workspace.WithWorkspace(context.Background(), "acme")
+	globex := workspace.WithWorkspace(context.Background(), "globex")
+
+	if _, err := r.AppendHistory(acme, &entities.TaskHistory{TaskID: 1, Action: entities.HistoryActionCreate}); err != nil {
+		t.Fatalf("AppendHistory() error = %v", err)
+	}
+
+	entries, total, err := r.ListHistoryByTaskID(globex, 1, 1, 10)
+	if err != nil {
+		t.Fatalf("ListHistoryByTaskID() error = %v", err)
+	}
+
+	if total != 0 || len(entries) != 0 {
+		t.Errorf("ListHistoryByTaskID() = %v, %d, want no entry", entries, total)
+	}
+}
@@ -0,0 +1,96 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq" // postgres driver
+)
+
+// Config describes how to reach the database.
+type Config struct {
+	Dialect      Dialect
+	Host         string
+	Port         string
+	User         string
+	Password     string
+	Database     string
+	SSLMode      string
+	MaxConns     int
+	MaxIdleConns int
+	MaxLifeTime  time.Duration
+}
+
+// DSN builds the driver specific data source name.
+func (c Config) DSN() (string, error) {
+	switch c.Dialect {
+	case DialectPostgres:
+		query := url.Values{}
+		if c.SSLMode != "" {
+			query.Set("sslmode", c.SSLMode)
+		}
+
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(c.User, c.Password),
+			Host:     net.JoinHostPort(c.Host, c.Port),
+			Path:     "/" + c.Database,
+			RawQuery: query.Encode(),
+		}
+
+		return dsn.String(), nil
+	case DialectMySQL:
+		mysqlCfg := mysql.NewConfig()
+		mysqlCfg.User = c.User
+		mysqlCfg.Passwd = c.Password
+		mysqlCfg.Net = "tcp"
+		mysqlCfg.Addr = net.JoinHostPort(c.Host, c.Port)
+		mysqlCfg.DBName = c.Database
+		mysqlCfg.ParseTime = true
+		mysqlCfg.Loc = time.UTC
+		// report matched rows instead of changed rows, so an update with identical values is not a miss
+		mysqlCfg.ClientFoundRows = true
+
+		return mysqlCfg.FormatDSN(), nil
+	default:
+		return "", fmt.Errorf("unsupported sql dialect %q", c.Dialect)
+	}
+}
+
+// OpenDB opens a connection pool for the given config and verifies it is reachable.
+func OpenDB(ctx context.Context, cfg Config) (*dbsql.DB, error) {
+	dsn, err := cfg.DSN()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := dbsql.Open(cfg.Dialect.DriverName(), dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sql.Open error: %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxConns)
+	}
+
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+
+	if cfg.MaxLifeTime > 0 {
+		db.SetConnMaxLifetime(cfg.MaxLifeTime)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("db.PingContext error: %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,72 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the SQL flavour spoken by the underlying database.
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectMySQL    Dialect = "mysql"
+)
+
+// ParseDialect converts a config value into a Dialect.
+func ParseDialect(s string) (Dialect, error) {
+	d := Dialect(strings.ToLower(strings.TrimSpace(s)))
+	if !d.Valid() {
+		return "", fmt.Errorf("unsupported sql dialect %q", s)
+	}
+
+	return d, nil
+}
+
+func (d Dialect) Valid() bool {
+	return d == DialectPostgres || d == DialectMySQL
+}
+
+// DriverName returns the database/sql driver name registered for the dialect.
+func (d Dialect) DriverName() string {
+	return string(d)
+}
+
+// Rebind converts the `?` placeholders of a query into the dialect's bind variables.
+func (d Dialect) Rebind(query string) string {
+	if d != DialectPostgres {
+		return query
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(query) + 8) //nolint:mnd
+
+	n := 0
+
+	for _, r := range query {
+		if r != '?' {
+			sb.WriteRune(r)
+
+			continue
+		}
+
+		n++
+
+		sb.WriteByte('$')
+		sb.WriteString(strconv.Itoa(n))
+	}
+
+	return sb.String()
+}
+
+// insertIgnore turns an INSERT INTO statement into one that leaves the row alone when its key, the
+// given columns, already exists.
+func (d Dialect) insertIgnore(query, key string) string {
+	if d == DialectPostgres {
+		return query + " ON CONFLICT (" + key + ") DO NOTHING"
+	}
+
+	return strings.Replace(query, "INSERT INTO", "INSERT IGNORE INTO", 1)
+}
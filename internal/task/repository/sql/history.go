@@ -0,0 +1,112 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"encoding/json"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"time"
+)
+
+var _ repository.HistoryRepository = (*HistoryRepository)(nil)
+
+// HistoryRepository is a repository for task history.
+// It stores entries in the `task_history` table, with the field changes encoded as JSON and the workspace
+// of the task.
+type HistoryRepository struct {
+	db      querier
+	dialect Dialect
+}
+
+func NewHistoryRepository(db *dbsql.DB, dialect Dialect) *HistoryRepository {
+	return &HistoryRepository{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// AppendHistory is recording a change of a task.
+func (r *HistoryRepository) AppendHistory(ctx context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error) {
+	if entry.TaskID == 0 || entry.Action == "" {
+		return nil, repository.ErrInvalidData
+	}
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal error: %w", err)
+	}
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	entry.CreatedAt = entry.CreatedAt.UTC()
+
+	id, err := insertReturningID(
+		ctx, r.db, r.dialect,
+		"INSERT INTO task_history (workspace_id, task_id, action, actor, changes, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		workspace.FromContext(ctx), entry.TaskID, entry.Action, entry.Actor, string(changes), entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert task history error: %w", err)
+	}
+
+	entry.ID = uint(id)
+
+	return entry, nil
+}
+
+// ListHistoryByTaskID is listing the history of a task by page, newest first.
+func (r *HistoryRepository) ListHistoryByTaskID(ctx context.Context, taskID uint, pageIndex, pageSize int) ([]*entities.TaskHistory, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	ws := workspace.FromContext(ctx)
+
+	var total int
+	if err := r.db.QueryRowContext(
+		ctx, r.dialect.Rebind("SELECT COUNT(*) FROM task_history WHERE workspace_id = ? AND task_id = ?"), ws, taskID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count task history error: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		r.dialect.Rebind("SELECT id, task_id, action, actor, changes, created_at FROM task_history "+
+			"WHERE workspace_id = ? AND task_id = ? ORDER BY id DESC LIMIT ? OFFSET ?"),
+		ws, taskID, pageSize, (pageIndex-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("select task history error: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*entities.TaskHistory, 0, pageSize)
+
+	for rows.Next() {
+		var (
+			entry   entities.TaskHistory
+			changes string
+		)
+
+		if err := rows.Scan(&entry.ID, &entry.TaskID, &entry.Action, &entry.Actor, &changes, &entry.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("rows.Scan error: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+			return nil, 0, fmt.Errorf("json.Unmarshal error: %w", err)
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows.Err error: %w", err)
+	}
+
+	return entries, total, nil
+}
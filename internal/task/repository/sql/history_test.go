@@ -0,0 +1,79 @@
+package sql
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockHistoryRepository(t *testing.T, dialect Dialect) (*HistoryRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewHistoryRepository(db, dialect), mock
+}
+
+func TestHistoryRepository_AppendHistory(t *testing.T) {
+	t.Parallel()
+
+	r, mock := newMockHistoryRepository(t, DialectPostgres)
+	mock.ExpectQuery(regexp.QuoteMeta(
+		"INSERT INTO task_history (workspace_id, task_id, action, actor, changes, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+	)).
+		WithArgs("default", 1, "update", "alice", `[{"field":"name","before":"a","after":"b"}]`, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+
+	got, err := r.AppendHistory(context.Background(), &entities.TaskHistory{
+		TaskID:  1,
+		Action:  entities.HistoryActionUpdate,
+		Actor:   "alice",
+		Changes: []entities.FieldChange{{Field: "name", Before: "a", After: "b"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(9), got.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = r.AppendHistory(context.Background(), &entities.TaskHistory{Action: entities.HistoryActionUpdate})
+	assert.ErrorIs(t, err, repository.ErrInvalidData)
+}
+
+func TestHistoryRepository_ListHistoryByTaskID(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+
+	r, mock := newMockHistoryRepository(t, DialectMySQL)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM task_history WHERE workspace_id = ? AND task_id = ?")).
+		WithArgs("acme", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT id, task_id, action, actor, changes, created_at FROM task_history WHERE workspace_id = ? AND task_id = ? ORDER BY id DESC LIMIT ? OFFSET ?",
+	)).
+		WithArgs("acme", 1, 2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_id", "action", "actor", "changes", "created_at"}).
+			AddRow(3, 1, "delete", "bob", `[{"field":"deleted","before":false,"after":true}]`, now).
+			AddRow(2, 1, "update", "alice", `[]`, now))
+
+	got, total, err := r.ListHistoryByTaskID(workspace.WithWorkspace(context.Background(), "acme"), 1, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, got, 2)
+	assert.Equal(t, entities.HistoryActionDelete, got[0].Action)
+	assert.Equal(t, []entities.FieldChange{{Field: "deleted", Before: false, After: true}}, got[0].Changes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,207 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var _ repository.ListRepository = (*ListRepository)(nil)
+
+const listColumns = "id, name, description, created_at, updated_at"
+
+// ListRepository is a repository for lists.
+// It stores lists in the `lists` table, keyed by workspace and id. The migration creates the default
+// list of the default workspace; the repository creates that of another workspace on its first use.
+type ListRepository struct {
+	db      querier
+	dialect Dialect
+	// seeded holds the workspaces known to have their default list.
+	seeded sync.Map
+}
+
+func NewListRepository(db *dbsql.DB, dialect Dialect) *ListRepository {
+	r := &ListRepository{
+		db:      db,
+		dialect: dialect,
+	}
+	r.seeded.Store(workspace.Default, struct{}{})
+
+	return r
+}
+
+// seed creates the default list of the workspace of ctx and its id counter when missing.
+func (r *ListRepository) seed(ctx context.Context) (string, error) {
+	ws := workspace.FromContext(ctx)
+	if _, ok := r.seeded.Load(ws); ok {
+		return ws, nil
+	}
+
+	now := time.Now().UTC()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		r.dialect.Rebind(r.dialect.insertIgnore(
+			"INSERT INTO lists (workspace_id, id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)", "workspace_id, id",
+		)),
+		ws, entities.DefaultListID, "Inbox", "", now, now,
+	)
+	if err != nil {
+		return "", fmt.Errorf("insert default list error: %w", err)
+	}
+
+	// the counter starts after the default list
+	_, err = r.db.ExecContext(
+		ctx,
+		r.dialect.Rebind(r.dialect.insertIgnore(
+			"INSERT INTO workspace_sequences (workspace_id, name, last_id) VALUES (?, ?, ?)", "workspace_id, name",
+		)),
+		ws, "lists", entities.DefaultListID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("insert list sequence error: %w", err)
+	}
+
+	r.seeded.Store(ws, struct{}{})
+
+	return ws, nil
+}
+
+// CreateList is creating a new list.
+func (r *ListRepository) CreateList(ctx context.Context, list *entities.List) (*entities.List, error) {
+	if list.Name == "" || utf8.RuneCountInString(list.Name) > 50 {
+		return nil, repository.ErrInvalidData
+	}
+
+	ws, err := r.seed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := nextID(ctx, r.db, r.dialect, "lists")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	_, err = r.db.ExecContext(
+		ctx,
+		r.dialect.Rebind("INSERT INTO lists (workspace_id, id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"),
+		ws, id, list.Name, list.Description, now, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert list error: %w", err)
+	}
+
+	list.ID = id
+	list.CreatedAt = now
+	list.UpdatedAt = now
+
+	return list, nil
+}
+
+// GetListByID is getting a list by id.
+func (r *ListRepository) GetListByID(ctx context.Context, id uint) (*entities.List, error) {
+	ws, err := r.seed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list entities.List
+
+	err = r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT "+listColumns+" FROM lists WHERE workspace_id = ? AND id = ?"), ws, id).
+		Scan(&list.ID, &list.Name, &list.Description, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, dbsql.ErrNoRows) {
+			return nil, repository.ErrDataNotFound
+		}
+
+		return nil, fmt.Errorf("select list error: %w", err)
+	}
+
+	return &list, nil
+}
+
+// ListListsByPage is listing the lists by page, ordered by id.
+func (r *ListRepository) ListListsByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.List, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	ws, err := r.seed(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*) FROM lists WHERE workspace_id = ?"), ws).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count lists error: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		r.dialect.Rebind("SELECT "+listColumns+" FROM lists WHERE workspace_id = ? ORDER BY id LIMIT ? OFFSET ?"),
+		ws, pageSize, (pageIndex-1)*pageSize,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("select lists error: %w", err)
+	}
+	defer rows.Close()
+
+	lists := make([]*entities.List, 0, pageSize)
+
+	for rows.Next() {
+		var list entities.List
+		if err := rows.Scan(&list.ID, &list.Name, &list.Description, &list.CreatedAt, &list.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("rows.Scan error: %w", err)
+		}
+
+		lists = append(lists, &list)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows.Err error: %w", err)
+	}
+
+	return lists, total, nil
+}
+
+// UpdateList is updating the name and description of a list.
+func (r *ListRepository) UpdateList(ctx context.Context, list *entities.List) (*entities.List, error) {
+	if list.Name == "" || utf8.RuneCountInString(list.Name) > 50 {
+		return nil, repository.ErrInvalidData
+	}
+
+	ws, err := r.seed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = execOne(
+		ctx, r.db, r.dialect,
+		"UPDATE lists SET name = ?, description = ?, updated_at = ? WHERE workspace_id = ? AND id = ?",
+		list.Name, list.Description, time.Now().UTC(), ws, list.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetListByID(ctx, list.ID)
+}
+
+// DeleteList is deleting a list, which must not be the default list.
+func (r *ListRepository) DeleteList(ctx context.Context, id uint) error {
+	if id == entities.DefaultListID {
+		return repository.ErrInvalidData
+	}
+
+	return execOne(ctx, r.db, r.dialect, "DELETE FROM lists WHERE workspace_id = ? AND id = ?", workspace.FromContext(ctx), id)
+}
@@ -0,0 +1,144 @@
+package sql
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockListRepository(t *testing.T, dialect Dialect) (*ListRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewListRepository(db, dialect), mock
+}
+
+func TestListRepository_CreateList(t *testing.T) {
+	t.Parallel()
+
+	r, mock := newMockListRepository(t, DialectPostgres)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO workspace_sequences")).
+		WithArgs("default", "lists").
+		WillReturnRows(sqlmock.NewRows([]string{"last_id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO lists (workspace_id, id, name, description, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)")).
+		WithArgs("default", 2, "work", "office", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	got, err := r.CreateList(context.Background(), &entities.List{Name: "work", Description: "office"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), got.ID)
+	assert.False(t, got.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = r.CreateList(context.Background(), &entities.List{})
+	assert.ErrorIs(t, err, repository.ErrInvalidData)
+}
+
+func TestListRepository_SeedWorkspace(t *testing.T) {
+	t.Parallel()
+
+	ctx := workspace.WithWorkspace(context.Background(), "acme")
+	now := time.Now().UTC()
+
+	r, mock := newMockListRepository(t, DialectMySQL)
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO lists (workspace_id, id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)")).
+		WithArgs("acme", entities.DefaultListID, "Inbox", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO workspace_sequences (workspace_id, name, last_id) VALUES (?, ?, ?)")).
+		WithArgs("acme", "lists", entities.DefaultListID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description, created_at, updated_at FROM lists WHERE workspace_id = ? AND id = ?")).
+		WithArgs("acme", entities.DefaultListID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
+			AddRow(1, "Inbox", "", now, now))
+	// the workspace is seeded once
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description, created_at, updated_at FROM lists WHERE workspace_id = ? AND id = ?")).
+		WithArgs("acme", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}))
+
+	inbox, err := r.GetListByID(ctx, entities.DefaultListID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Inbox", inbox.Name)
+
+	_, err = r.GetListByID(ctx, 2)
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListRepository_ListListsByPage(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+
+	r, mock := newMockListRepository(t, DialectMySQL)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM lists WHERE workspace_id = ?")).
+		WithArgs("default").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description, created_at, updated_at FROM lists WHERE workspace_id = ? ORDER BY id LIMIT ? OFFSET ?")).
+		WithArgs("default", 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
+			AddRow(3, "home", "", now, now))
+
+	got, total, err := r.ListListsByPage(context.Background(), 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []*entities.List{{ID: 3, Name: "home", CreatedAt: now, UpdatedAt: now}}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListRepository_UpdateList(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+
+	r, mock := newMockListRepository(t, DialectPostgres)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE lists SET name = $1, description = $2, updated_at = $3 WHERE workspace_id = $4 AND id = $5")).
+		WithArgs("office", "desk", sqlmock.AnyArg(), "default", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description, created_at, updated_at FROM lists WHERE workspace_id = $1 AND id = $2")).
+		WithArgs("default", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "created_at", "updated_at"}).
+			AddRow(2, "office", "desk", now, now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE lists SET")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	got, err := r.UpdateList(context.Background(), &entities.List{ID: 2, Name: "office", Description: "desk"})
+	assert.NoError(t, err)
+	assert.Equal(t, &entities.List{ID: 2, Name: "office", Description: "desk", CreatedAt: now, UpdatedAt: now}, got)
+
+	_, err = r.UpdateList(context.Background(), &entities.List{ID: 9, Name: "office"})
+	assert.ErrorIs(t, err, repository.ErrDataNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListRepository_DeleteList(t *testing.T) {
+	t.Parallel()
+
+	r, mock := newMockListRepository(t, DialectMySQL)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM lists WHERE workspace_id = ? AND id = ?")).
+		WithArgs("default", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM lists WHERE workspace_id = ? AND id = ?")).
+		WithArgs("default", 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, r.DeleteList(context.Background(), 2))
+	assert.ErrorIs(t, r.DeleteList(context.Background(), 9), repository.ErrDataNotFound)
+	assert.ErrorIs(t, r.DeleteList(context.Background(), entities.DefaultListID), repository.ErrInvalidData)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,188 @@
+package sql
+
+import (
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sortColumns maps the sort fields to their columns; it doubles as the whitelist of sortable columns.
+var sortColumns = map[repository.SortField]string{
+	repository.SortFieldID:        "id",
+	repository.SortFieldName:      "name",
+	repository.SortFieldStatus:    "status",
+	repository.SortFieldPriority:  "priority",
+	repository.SortFieldCreatedAt: "created_at",
+	repository.SortFieldUpdatedAt: "updated_at",
+}
+
+// likeEscaper escapes the LIKE wildcards, so a name filter matches them literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// filterClause returns the WHERE condition selecting the live tasks of the workspace that match the filter,
+// with its arguments.
+func (r *TaskRepository) filterClause(ws string, f repository.TaskFilter) (string, []any) {
+	conds := []string{"workspace_id = ?", "deleted_at IS NULL"}
+	args := []any{ws}
+
+	if f.Status != nil {
+		conds = append(conds, "status = ?")
+		args = append(args, *f.Status)
+	}
+
+	if f.ListID != 0 {
+		conds = append(conds, "list_id = ?")
+		args = append(args, f.ListID)
+	}
+
+	if f.NameContains != "" {
+		// MySQL's default collations already compare case-insensitively
+		if r.dialect == DialectPostgres {
+			conds = append(conds, "name ILIKE ?")
+		} else {
+			conds = append(conds, "name LIKE ?")
+		}
+
+		args = append(args, "%"+likeEscaper.Replace(f.NameContains)+"%")
+	}
+
+	for _, bound := range []struct {
+		cond  string
+		value any
+		set   bool
+	}{
+		{"created_at >= ?", f.CreatedSince.UTC(), !f.CreatedSince.IsZero()},
+		{"created_at < ?", f.CreatedBefore.UTC(), !f.CreatedBefore.IsZero()},
+		{"updated_at >= ?", f.UpdatedSince.UTC(), !f.UpdatedSince.IsZero()},
+		{"updated_at < ?", f.UpdatedBefore.UTC(), !f.UpdatedBefore.IsZero()},
+		{"due_at >= ?", f.DueSince.UTC(), !f.DueSince.IsZero()},
+		{"due_at < ?", f.DueBefore.UTC(), !f.DueBefore.IsZero()},
+		{"priority >= ?", derefPriority(f.MinPriority), f.MinPriority != nil},
+		{"priority <= ?", derefPriority(f.MaxPriority), f.MaxPriority != nil},
+	} {
+		if bound.set {
+			conds = append(conds, bound.cond)
+			args = append(args, bound.value)
+		}
+	}
+
+	if len(f.IDs) > 0 {
+		conds = append(conds, "id IN ("+placeholders(len(f.IDs))+")")
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+	}
+
+	for _, tag := range f.Tags {
+		conds = append(conds, "tags LIKE ?")
+		args = append(args, "%,"+likeEscaper.Replace(tag)+",%")
+	}
+
+	if f.Overdue {
+		conds = append(conds, "status NOT IN (?, ?) AND due_at < ?")
+		args = append(args, task.TaskStatusCompleted, task.TaskStatusArchived, time.Now().UTC())
+	}
+
+	if f.Ready {
+		// blocked_by holds `,1,2,`, so a blocker matches a LIKE on `%,id,%`
+		conds = append(conds, "status NOT IN (?, ?) AND NOT EXISTS (SELECT 1 FROM tasks AS blocker WHERE blocker.workspace_id = tasks.workspace_id "+
+			"AND blocker.deleted_at IS NULL AND blocker.status NOT IN (?, ?) AND tasks.blocked_by LIKE CONCAT('%,', blocker.id, ',%'))")
+		args = append(args, task.TaskStatusCompleted, task.TaskStatusArchived, task.TaskStatusCompleted, task.TaskStatusArchived)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+func derefPriority(p *task.Priority) task.Priority {
+	if p == nil {
+		return task.PriorityNone
+	}
+
+	return *p
+}
+
+// keysetClause returns the condition selecting the tasks that sort after the cursor task, with its arguments.
+// For keys (a, b) it expands to `a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?)`,
+// the comparison of each key following its direction.
+func keysetClause(keys []repository.SortKey, cursor *entities.Task) (string, []any) {
+	keys = withIDTiebreak(keys)
+
+	terms := make([]string, 0, len(keys))
+	args := make([]any, 0)
+
+	for i, key := range keys {
+		conds := make([]string, 0, i+1)
+
+		for _, prev := range keys[:i] {
+			conds = append(conds, sortColumns[prev.Field]+" = ?")
+			args = append(args, keyValue(prev.Field, cursor))
+		}
+
+		op := " > ?"
+		if key.Desc {
+			op = " < ?"
+		}
+
+		conds = append(conds, sortColumns[key.Field]+op)
+		args = append(args, keyValue(key.Field, cursor))
+
+		terms = append(terms, "("+strings.Join(conds, " AND ")+")")
+	}
+
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// keyValue returns the value of a sort field of the cursor task, as stored in the database.
+func keyValue(field repository.SortField, cursor *entities.Task) any {
+	if t, ok := field.Value(cursor).(time.Time); ok {
+		return t.UTC()
+	}
+
+	return field.Value(cursor)
+}
+
+// reverseSort flips the direction of every sort key, including the id tiebreak.
+func reverseSort(keys []repository.SortKey) []repository.SortKey {
+	keys = withIDTiebreak(keys)
+
+	reversed := make([]repository.SortKey, 0, len(keys))
+	for _, key := range keys {
+		reversed = append(reversed, repository.SortKey{Field: key.Field, Desc: !key.Desc})
+	}
+
+	return reversed
+}
+
+// withIDTiebreak appends id to the sort keys unless they already order by it, so the order is total.
+func withIDTiebreak(keys []repository.SortKey) []repository.SortKey {
+	if slices.ContainsFunc(keys, func(key repository.SortKey) bool { return key.Field == repository.SortFieldID }) {
+		return keys
+	}
+
+	return append(slices.Clip(keys), repository.SortKey{Field: repository.SortFieldID})
+}
+
+// orderByClause returns the ORDER BY list for the sort keys, ending with id so pages are stable.
+func orderByClause(keys []repository.SortKey) string {
+	keys = withIDTiebreak(keys)
+
+	terms := make([]string, 0, len(keys))
+	for _, key := range keys {
+		term := sortColumns[key.Field]
+		if key.Desc {
+			term += " DESC"
+		}
+
+		terms = append(terms, term)
+	}
+
+	return strings.Join(terms, ", ")
+}
+
+// placeholders returns n comma-separated bind placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
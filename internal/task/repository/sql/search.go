@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/fulltext"
+	"ggltask/pkg/workspace"
+	"strings"
+)
+
+// searchClause returns the full-text match condition and the relevance expression for the query, each
+// taking the returned search string as its only argument. The query terms hold nothing but letters and
+// digits, so they never carry operators of the search syntax.
+func (r *TaskRepository) searchClause(q fulltext.Query) (match, score, search string) {
+	terms := make([]string, 0, len(q.Terms))
+
+	if r.dialect == DialectPostgres {
+		terms = append(terms, q.Terms...)
+
+		if q.Prefix {
+			terms[len(terms)-1] += ":*"
+		}
+
+		vector := "to_tsvector('simple', name)"
+		query := "to_tsquery('simple', ?)"
+
+		return vector + " @@ " + query, "ts_rank(" + vector + ", " + query + ")", strings.Join(terms, " & ")
+	}
+
+	for _, term := range q.Terms {
+		terms = append(terms, "+"+term)
+	}
+
+	if q.Prefix {
+		terms[len(terms)-1] += "*"
+	}
+
+	match = "MATCH(name) AGAINST(? IN BOOLEAN MODE)"
+
+	return match, match, strings.Join(terms, " ")
+}
+
+// SearchTasks is searching the live tasks by name with the database's full-text index, most relevant first.
+func (r *TaskRepository) SearchTasks(ctx context.Context, query repository.TaskSearchQuery) ([]*entities.TaskSearchHit, int, error) {
+	if !query.Valid() {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	ws := workspace.FromContext(ctx)
+	match, score, search := r.searchClause(fulltext.ParseQuery(query.Text))
+	where := "workspace_id = ? AND deleted_at IS NULL AND " + match
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*) FROM tasks WHERE "+where), ws, search).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count tasks error: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		r.dialect.Rebind("SELECT "+taskColumns+", "+score+" AS score FROM tasks WHERE "+where+" ORDER BY score DESC, id LIMIT ? OFFSET ?"),
+		search, ws, search, query.PageSize, (query.PageIndex-1)*query.PageSize,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search tasks error: %w", err)
+	}
+	defer rows.Close()
+
+	hits := make([]*entities.TaskSearchHit, 0, query.PageSize)
+
+	for rows.Next() {
+		hit := &entities.TaskSearchHit{}
+
+		if hit.Task, err = scanTask(scoredRow{row: rows, score: &hit.Score}); err != nil {
+			return nil, 0, fmt.Errorf("scan task error: %w", err)
+		}
+
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows.Err error: %w", err)
+	}
+
+	return hits, total, nil
+}
+
+// scoredRow scans a task row followed by its relevance score.
+type scoredRow struct {
+	row   scanner
+	score *float64
+}
+
+func (s scoredRow) Scan(dest ...any) error {
+	return s.row.Scan(append(dest, s.score)...) //nolint:wrapcheck
+}
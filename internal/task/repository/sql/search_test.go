@@ -0,0 +1,83 @@
+package sql
+
+import (
+	"context"
+	"ggltask/internal/task/domain/repository"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskRepository_SearchTasks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		text    string
+		count   string
+		score   string
+		where   string
+		search  string
+		orderBy string
+	}{
+		{
+			name:    "postgres",
+			dialect: DialectPostgres,
+			text:    "Buy MI",
+			count:   "workspace_id = $1 AND deleted_at IS NULL AND to_tsvector('simple', name) @@ to_tsquery('simple', $2)",
+			score:   "ts_rank(to_tsvector('simple', name), to_tsquery('simple', $1))",
+			// postgres numbers the placeholders, so the where of the select binds them after the score
+			where:   "workspace_id = $2 AND deleted_at IS NULL AND to_tsvector('simple', name) @@ to_tsquery('simple', $3)",
+			search:  "buy & mi:*",
+			orderBy: "ORDER BY score DESC, id LIMIT $4 OFFSET $5",
+		},
+		{
+			name:    "mysql with a complete last word",
+			dialect: DialectMySQL,
+			text:    "buy & milk ",
+			count:   "workspace_id = ? AND deleted_at IS NULL AND MATCH(name) AGAINST(? IN BOOLEAN MODE)",
+			score:   "MATCH(name) AGAINST(? IN BOOLEAN MODE)",
+			where:   "workspace_id = ? AND deleted_at IS NULL AND MATCH(name) AGAINST(? IN BOOLEAN MODE)",
+			search:  "+buy +milk",
+			orderBy: "ORDER BY score DESC, id LIMIT ? OFFSET ?",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Now().UTC()
+
+			r, mock := newMockRepository(t, tt.dialect)
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM tasks WHERE "+tt.count)).
+				WithArgs("default", tt.search).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT "+taskColumns+", "+tt.score+" AS score FROM tasks WHERE "+tt.where+" "+tt.orderBy)).
+				WithArgs(tt.search, "default", tt.search, 2, 2).
+				WillReturnRows(sqlmock.NewRows(append(taskRowColumns, "score")).AddRow(4, "buy milk", 0, 1, now, now, nil, "", 0, nil, 0, "", nil, "", "", "", 1, "", 0.5))
+
+			hits, total, err := r.SearchTasks(context.Background(), repository.TaskSearchQuery{Text: tt.text, PageIndex: 2, PageSize: 2})
+			assert.NoError(t, err)
+			assert.Equal(t, 3, total)
+			assert.Len(t, hits, 1)
+			assert.Equal(t, uint(4), hits[0].Task.ID)
+			assert.Equal(t, 0.5, hits[0].Score)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTaskRepository_SearchTasks_InvalidQuery(t *testing.T) {
+	t.Parallel()
+
+	r, _ := newMockRepository(t, DialectPostgres)
+
+	_, _, err := r.SearchTasks(context.Background(), repository.TaskSearchQuery{Text: "--", PageIndex: 1, PageSize: 10})
+	assert.ErrorIs(t, err, repository.ErrInvalidData)
+}
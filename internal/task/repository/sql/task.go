@@ -0,0 +1,587 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/pkg/workspace"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var _ repository.Repository = (*TaskRepository)(nil)
+
+const taskColumns = "id, name, status, version, created_at, updated_at, deleted_at, description, priority, due_at, due_offset, tags, parent_id, blocked_by, recurrence_rule, recurrence_time_zone, list_id, created_by"
+
+// querier is the subset of *sql.DB and *sql.Tx used by the repository.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (dbsql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*dbsql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *dbsql.Row
+}
+
+// TaskRepository is a repository for tasks.
+// It stores tasks in the `tasks` table of a Postgres or MySQL database, keyed by workspace and id;
+// every statement is restricted to the workspace of its context.
+type TaskRepository struct {
+	db      querier
+	dialect Dialect
+}
+
+func NewTaskRepository(db *dbsql.DB, dialect Dialect) *TaskRepository {
+	return &TaskRepository{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// CreateTask is creating a new task.
+func (r *TaskRepository) CreateTask(ctx context.Context, taskEntity *entities.Task) (*entities.Task, error) {
+	if taskEntity.Name == "" || utf8.RuneCountInString(taskEntity.Name) > 50 || !taskEntity.Status.Valid() {
+		return nil, repository.ErrInvalidData
+	}
+
+	id, err := nextID(ctx, r.db, r.dialect, "tasks")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	dueAt, dueOffset := dueColumns(taskEntity.DueAt)
+	rule, timeZone := recurrenceColumns(taskEntity.Recurrence)
+	query := "INSERT INTO tasks (workspace_id, id, name, description, status, priority, due_at, due_offset, tags, parent_id, blocked_by, " +
+		"recurrence_rule, recurrence_time_zone, list_id, created_by, version, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	args := []any{
+		workspace.FromContext(ctx), id, taskEntity.Name, taskEntity.Description, taskEntity.Status, taskEntity.Priority, dueAt, dueOffset,
+		formatTags(taskEntity.Tags), parentColumn(taskEntity.ParentID), formatIDs(taskEntity.BlockedBy), rule, timeZone,
+		listColumn(taskEntity.ListID), taskEntity.CreatedBy, 1, now, now,
+	}
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("insert task error: %w", err)
+	}
+
+	taskEntity.ID = id
+	taskEntity.ListID = listColumn(taskEntity.ListID)
+	taskEntity.Version = 1
+	taskEntity.CreatedAt = now
+	taskEntity.UpdatedAt = now
+
+	return taskEntity, nil
+}
+
+// GetTaskByID is getting a task by id.
+func (r *TaskRepository) GetTaskByID(ctx context.Context, id uint) (*entities.Task, error) {
+	row := r.db.QueryRowContext(
+		ctx,
+		r.dialect.Rebind("SELECT "+taskColumns+" FROM tasks WHERE workspace_id = ? AND id = ? AND deleted_at IS NULL"),
+		workspace.FromContext(ctx), id,
+	)
+
+	task, err := scanTask(row)
+	if err != nil {
+		if errors.Is(err, dbsql.ErrNoRows) {
+			return nil, repository.ErrDataNotFound
+		}
+
+		return nil, fmt.Errorf("select task error: %w", err)
+	}
+
+	return task, nil
+}
+
+// ListTasksByPage is listing the tasks matching the query by page.
+func (r *TaskRepository) ListTasksByPage(ctx context.Context, query repository.TaskQuery) ([]*entities.Task, int, error) {
+	if !query.Valid() {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	where, args := r.filterClause(workspace.FromContext(ctx), query.Filter)
+
+	return r.listTasksByPage(ctx, where, orderByClause(query.Sort), args, query.PageIndex, query.PageSize)
+}
+
+// ListDeletedTasksByPage is listing trashed tasks by page, most recently deleted first.
+func (r *TaskRepository) ListDeletedTasksByPage(ctx context.Context, pageIndex, pageSize int) ([]*entities.Task, int, error) {
+	if pageIndex < 1 || pageSize < 1 {
+		return nil, 0, repository.ErrInvalidData
+	}
+
+	return r.listTasksByPage(
+		ctx, "workspace_id = ? AND deleted_at IS NOT NULL", "deleted_at DESC, id", []any{workspace.FromContext(ctx)}, pageIndex, pageSize,
+	)
+}
+
+// GetDeletedTaskByID is getting a trashed task by id.
+func (r *TaskRepository) GetDeletedTaskByID(ctx context.Context, id uint) (*entities.Task, error) {
+	row := r.db.QueryRowContext(
+		ctx,
+		r.dialect.Rebind("SELECT "+taskColumns+" FROM tasks WHERE workspace_id = ? AND id = ? AND deleted_at IS NOT NULL"),
+		workspace.FromContext(ctx), id,
+	)
+
+	task, err := scanTask(row)
+	if err != nil {
+		if errors.Is(err, dbsql.ErrNoRows) {
+			return nil, repository.ErrDataNotFound
+		}
+
+		return nil, fmt.Errorf("select task error: %w", err)
+	}
+
+	return task, nil
+}
+
+func (r *TaskRepository) listTasksByPage(ctx context.Context, where, orderBy string, args []any, pageIndex, pageSize int) ([]*entities.Task, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*) FROM tasks WHERE "+where), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count tasks error: %w", err)
+	}
+
+	// a page past the end is empty, not nil
+	start := (pageIndex - 1) * pageSize
+	if start >= total {
+		return []*entities.Task{}, total, nil
+	}
+
+	tasks, err := r.selectTasks(ctx, where, orderBy, args, pageSize, start)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+// ListTasksByCursor is listing the tasks matching the query that follow or precede its cursor.
+func (r *TaskRepository) ListTasksByCursor(ctx context.Context, query repository.TaskCursorQuery) ([]*entities.Task, int, bool, error) {
+	if !query.Valid() {
+		return nil, 0, false, repository.ErrInvalidData
+	}
+
+	where, args := r.filterClause(workspace.FromContext(ctx), query.Filter)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*) FROM tasks WHERE "+where), args...).Scan(&total); err != nil {
+		return nil, 0, false, fmt.Errorf("count tasks error: %w", err)
+	}
+
+	sort := query.Sort
+	if query.Backward {
+		sort = reverseSort(sort)
+	}
+
+	if query.Cursor != nil {
+		keyset, keysetArgs := keysetClause(sort, query.Cursor)
+		where += " AND " + keyset
+		args = append(args, keysetArgs...)
+	}
+
+	// fetch one extra row to learn whether another page follows
+	tasks, err := r.selectTasks(ctx, where, orderByClause(sort), args, query.Limit+1, 0)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	hasMore := len(tasks) > query.Limit
+	if hasMore {
+		tasks = tasks[:query.Limit]
+	}
+
+	if query.Backward {
+		slices.Reverse(tasks)
+	}
+
+	return tasks, total, hasMore, nil
+}
+
+// ListChildTasks is listing the live subtasks of a task.
+func (r *TaskRepository) ListChildTasks(ctx context.Context, parentID uint) ([]*entities.Task, error) {
+	return r.queryTasks(
+		ctx,
+		"SELECT "+taskColumns+" FROM tasks WHERE workspace_id = ? AND parent_id = ? AND deleted_at IS NULL ORDER BY id",
+		[]any{workspace.FromContext(ctx), parentID},
+	)
+}
+
+func (r *TaskRepository) selectTasks(ctx context.Context, where, orderBy string, args []any, limit, offset int) ([]*entities.Task, error) {
+	return r.queryTasks(ctx, "SELECT "+taskColumns+" FROM tasks WHERE "+where+" ORDER BY "+orderBy+" LIMIT ? OFFSET ?", append(args, limit, offset))
+}
+
+func (r *TaskRepository) queryTasks(ctx context.Context, query string, args []any) ([]*entities.Task, error) {
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("select tasks error: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]*entities.Task, 0)
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan task error: %w", err)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err error: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// UpdateTask is updating a task.
+func (r *TaskRepository) UpdateTask(ctx context.Context, taskEntity *entities.Task) (*entities.Task, error) {
+	if taskEntity.Name == "" || utf8.RuneCountInString(taskEntity.Name) > 50 || !taskEntity.Status.Valid() {
+		return nil, repository.ErrInvalidData
+	}
+
+	dueAt, dueOffset := dueColumns(taskEntity.DueAt)
+	rule, timeZone := recurrenceColumns(taskEntity.Recurrence)
+	query := "UPDATE tasks SET name = ?, description = ?, status = ?, priority = ?, due_at = ?, due_offset = ?, tags = ?, parent_id = ?, blocked_by = ?, " +
+		"recurrence_rule = ?, recurrence_time_zone = ?, list_id = ?, version = version + 1, updated_at = ? " +
+		"WHERE workspace_id = ? AND id = ? AND deleted_at IS NULL"
+	args := []any{
+		taskEntity.Name, taskEntity.Description, taskEntity.Status, taskEntity.Priority, dueAt, dueOffset, formatTags(taskEntity.Tags),
+		parentColumn(taskEntity.ParentID), formatIDs(taskEntity.BlockedBy), rule, timeZone, listColumn(taskEntity.ListID), time.Now().UTC(),
+		workspace.FromContext(ctx), taskEntity.ID,
+	}
+
+	// the version check is part of the UPDATE, so the compare-and-swap is atomic in the database
+	if taskEntity.Version != 0 {
+		query += " AND version = ?"
+		args = append(args, taskEntity.Version)
+	}
+
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("update task error: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("result.RowsAffected error: %w", err)
+	}
+
+	if affected == 0 {
+		if taskEntity.Version == 0 {
+			return nil, repository.ErrDataNotFound
+		}
+
+		// tell a missing task apart from a stale version
+		if _, err := r.GetTaskByID(ctx, taskEntity.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, repository.ErrVersionConflict
+	}
+
+	return r.GetTaskByID(ctx, taskEntity.ID)
+}
+
+// DeleteTask is moving a task to the trash.
+func (r *TaskRepository) DeleteTask(ctx context.Context, id uint) error {
+	return r.execOne(
+		ctx,
+		"UPDATE tasks SET deleted_at = ? WHERE workspace_id = ? AND id = ? AND deleted_at IS NULL",
+		time.Now().UTC(), workspace.FromContext(ctx), id,
+	)
+}
+
+// RestoreTask is moving a task out of the trash.
+func (r *TaskRepository) RestoreTask(ctx context.Context, id uint) (*entities.Task, error) {
+	err := r.execOne(
+		ctx,
+		"UPDATE tasks SET deleted_at = NULL, version = version + 1, updated_at = ? WHERE workspace_id = ? AND id = ? AND deleted_at IS NOT NULL",
+		time.Now().UTC(), workspace.FromContext(ctx), id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetTaskByID(ctx, id)
+}
+
+// PurgeTask is permanently removing a trashed task.
+func (r *TaskRepository) PurgeTask(ctx context.Context, id uint) error {
+	return r.execOne(ctx, "DELETE FROM tasks WHERE workspace_id = ? AND id = ? AND deleted_at IS NOT NULL", workspace.FromContext(ctx), id)
+}
+
+// PurgeDeletedTasks is permanently removing the tasks trashed before the given time.
+func (r *TaskRepository) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time) ([]uint, error) {
+	ws := workspace.FromContext(ctx)
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		r.dialect.Rebind("SELECT id FROM tasks WHERE workspace_id = ? AND deleted_at IS NOT NULL AND deleted_at < ? ORDER BY id"),
+		ws, deletedBefore.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select purgeable tasks error: %w", err)
+	}
+	defer rows.Close()
+
+	purged := make([]uint, 0)
+
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("rows.Scan error: %w", err)
+		}
+
+		purged = append(purged, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err error: %w", err)
+	}
+
+	if len(purged) == 0 {
+		return purged, nil
+	}
+
+	// delete by the selected ids, so a task restored in the meantime is kept
+	args := make([]any, 0, len(purged)+1)
+	args = append(args, ws)
+
+	for _, id := range purged {
+		args = append(args, id)
+	}
+
+	_, err = r.db.ExecContext(
+		ctx,
+		r.dialect.Rebind("DELETE FROM tasks WHERE workspace_id = ? AND deleted_at IS NOT NULL AND id IN ("+placeholders(len(purged))+")"),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("purge tasks error: %w", err)
+	}
+
+	return purged, nil
+}
+
+// Workspaces is listing the workspaces holding tasks, live or trashed, in order.
+func (r *TaskRepository) Workspaces(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT DISTINCT workspace_id FROM tasks ORDER BY workspace_id")
+	if err != nil {
+		return nil, fmt.Errorf("select workspaces error: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("rows.Scan error: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err error: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *TaskRepository) execOne(ctx context.Context, query string, args ...any) error {
+	return execOne(ctx, r.db, r.dialect, query, args...)
+}
+
+// execOne runs a statement that must affect exactly one row, reporting ErrDataNotFound otherwise.
+func execOne(ctx context.Context, db querier, dialect Dialect, query string, args ...any) error {
+	result, err := db.ExecContext(ctx, dialect.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("exec %q error: %w", query, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected error: %w", err)
+	}
+
+	if affected == 0 {
+		return repository.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// insertReturningID runs an INSERT and returns the generated id.
+func insertReturningID(ctx context.Context, db querier, dialect Dialect, query string, args ...any) (int64, error) {
+	var id int64
+
+	if dialect == DialectPostgres {
+		if err := db.QueryRowContext(ctx, dialect.Rebind(query+" RETURNING id"), args...).Scan(&id); err != nil {
+			return 0, err //nolint:wrapcheck
+		}
+
+		return id, nil
+	}
+
+	result, err := db.ExecContext(ctx, dialect.Rebind(query), args...)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	if id, err = result.LastInsertId(); err != nil {
+		return 0, fmt.Errorf("result.LastInsertId error: %w", err)
+	}
+
+	return id, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row scanner) (*entities.Task, error) {
+	var (
+		task      entities.Task
+		deletedAt dbsql.NullTime
+		dueAt     dbsql.NullTime
+		dueOffset int
+		tags      string
+		parentID  dbsql.NullInt64
+		blockedBy string
+		rule      string
+		timeZone  string
+	)
+
+	err := row.Scan(
+		&task.ID, &task.Name, &task.Status, &task.Version, &task.CreatedAt, &task.UpdatedAt, &deletedAt,
+		&task.Description, &task.Priority, &dueAt, &dueOffset, &tags, &parentID, &blockedBy, &rule, &timeZone,
+		&task.ListID, &task.CreatedBy,
+	)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if deletedAt.Valid {
+		task.DeletedAt = &deletedAt.Time
+	}
+
+	if dueAt.Valid {
+		due := dueAt.Time.In(dueZone(dueOffset))
+		task.DueAt = &due
+	}
+
+	task.Tags = parseTags(tags)
+
+	task.BlockedBy = parseIDs(blockedBy)
+
+	if parentID.Valid {
+		parent := uint(parentID.Int64)
+		task.ParentID = &parent
+	}
+
+	if rule != "" {
+		task.Recurrence = &entities.Recurrence{Rule: rule, TimeZone: timeZone}
+	}
+
+	return &task, nil
+}
+
+// dueColumns splits a due date into the UTC instant stored in due_at and the offset of its time zone,
+// in seconds east of UTC, stored in due_offset; the database keeps instants only.
+func dueColumns(dueAt *time.Time) (any, int) {
+	if dueAt == nil {
+		return nil, 0
+	}
+
+	_, offset := dueAt.Zone()
+
+	return dueAt.UTC(), offset
+}
+
+func dueZone(offset int) *time.Location {
+	if offset == 0 {
+		return time.UTC
+	}
+
+	return time.FixedZone("", offset)
+}
+
+func parentColumn(parentID *uint) any {
+	if parentID == nil {
+		return nil
+	}
+
+	return *parentID
+}
+
+// listColumn puts a task without a list in the default list.
+func listColumn(listID uint) uint {
+	if listID == 0 {
+		return entities.DefaultListID
+	}
+
+	return listID
+}
+
+func recurrenceColumns(recurrence *entities.Recurrence) (string, string) {
+	if recurrence == nil {
+		return "", ""
+	}
+
+	return recurrence.Rule, recurrence.TimeZone
+}
+
+// formatTags stores tags as `,a,b,`, so a tag filter is a LIKE on `%,tag,%`; tags never hold a comma.
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	return "," + strings.Join(tags, ",") + ","
+}
+
+func parseTags(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.Trim(s, ","), ",")
+}
+
+// formatIDs stores task ids as `,1,2,`, the way formatTags stores tags.
+func formatIDs(ids []uint) string {
+	if len(ids) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatUint(uint64(id), 10))
+	}
+
+	return "," + strings.Join(parts, ",") + ","
+}
+
+// parseIDs reads the ids written by formatIDs; it returns nil for none, as an unset field.
+func parseIDs(s string) []uint {
+	if s == "" {
+		return nil
+	}
+
+	parts := strings.Split(strings.Trim(s, ","), ",")
+	ids := make([]uint, 0, len(parts))
+
+	for _, part := range parts {
+		// the column is only written by formatIDs
+		id, _ := strconv.ParseUint(part, 10, 64)
+		ids = append(ids, uint(id))
+	}
+
+	return ids
+}
@@ -0,0 +1,54 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/repository"
+)
+
+// beginner is implemented by *sql.DB, but not by *sql.Tx: a repository already in a transaction runs
+// a nested one as part of it.
+type beginner interface {
+	BeginTx(ctx context.Context, opts *dbsql.TxOptions) (*dbsql.Tx, error)
+}
+
+// WithinTransaction runs fn in a database transaction, committed when fn returns nil and rolled back otherwise.
+func (r *TaskRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context, tx repository.Repository) error) (err error) {
+	db, ok := r.db.(beginner)
+	if !ok {
+		return fn(ctx, r)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("db.BeginTx error: %w", err)
+	}
+
+	committed := false
+
+	// also rolls back when fn panics
+	defer func() {
+		if committed {
+			return
+		}
+
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			err = errors.Join(err, fmt.Errorf("tx.Rollback error: %w", rollbackErr))
+		}
+	}()
+
+	if err := fn(ctx, &TaskRepository{db: tx, dialect: r.dialect}); err != nil {
+		return err
+	}
+
+	// a failed commit leaves nothing to roll back
+	committed = true
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tx.Commit error: %w", err)
+	}
+
+	return nil
+}
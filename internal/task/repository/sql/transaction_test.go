@@ -0,0 +1,84 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"ggltask/internal/task/domain/repository"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskRepository_WithinTransaction(t *testing.T) {
+	t.Parallel()
+
+	errRollback := errors.New("rollback")
+
+	tests := []struct {
+		name    string
+		setup   func(mock sqlmock.Sqlmock)
+		fnErr   error
+		wantErr error
+	}{
+		{
+			name: "commit",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE tasks SET deleted_at").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE tasks SET deleted_at").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "rollback",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE tasks SET deleted_at").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE tasks SET deleted_at").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			fnErr:   errRollback,
+			wantErr: errRollback,
+		},
+		{
+			name: "begin error",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(errRollback)
+			},
+			wantErr: errRollback,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, mock := newMockRepository(t, DialectPostgres)
+			tt.setup(mock)
+
+			err := r.WithinTransaction(context.Background(), func(ctx context.Context, tx repository.Repository) error {
+				if err := tx.DeleteTask(ctx, 1); err != nil {
+					return err
+				}
+
+				// a nested transaction is part of the outer one
+				if err := tx.WithinTransaction(ctx, func(ctx context.Context, nested repository.Repository) error {
+					return nested.DeleteTask(ctx, 2)
+				}); err != nil {
+					return err
+				}
+
+				return tt.fnErr
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
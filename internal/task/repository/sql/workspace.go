@@ -0,0 +1,48 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"ggltask/pkg/workspace"
+)
+
+// nextID allocates the next id of a table in the workspace of ctx. The counters live in the
+// workspace_sequences table, one row per workspace and table, created by the first allocation.
+// Inside a transaction the row stays locked until the end, so ids are handed out in commit order.
+func nextID(ctx context.Context, db querier, dialect Dialect, table string) (uint, error) {
+	ws := workspace.FromContext(ctx)
+
+	if dialect == DialectPostgres {
+		var id uint
+
+		err := db.QueryRowContext(
+			ctx,
+			dialect.Rebind("INSERT INTO workspace_sequences (workspace_id, name, last_id) VALUES (?, ?, 1) "+
+				"ON CONFLICT (workspace_id, name) DO UPDATE SET last_id = workspace_sequences.last_id + 1 RETURNING last_id"),
+			ws, table,
+		).Scan(&id)
+		if err != nil {
+			return 0, fmt.Errorf("allocate %s id error: %w", table, err)
+		}
+
+		return id, nil
+	}
+
+	// LAST_INSERT_ID(expr) hands the new counter value back as the insert id of the statement
+	result, err := db.ExecContext(
+		ctx,
+		"INSERT INTO workspace_sequences (workspace_id, name, last_id) VALUES (?, ?, LAST_INSERT_ID(1)) "+
+			"ON DUPLICATE KEY UPDATE last_id = LAST_INSERT_ID(last_id + 1)",
+		ws, table,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("allocate %s id error: %w", table, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("result.LastInsertId error: %w", err)
+	}
+
+	return uint(id), nil
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+)
+
+// errBatchAborted rolls back an atomic batch at its first failed operation.
+var errBatchAborted = errors.New("batch aborted")
+
+// BatchTasks is responsible for applying a batch of create, update and delete operations.
+// An atomic batch runs in a repository transaction and its history is only recorded once it commits.
+func (a *TaskUseCaseImpl) BatchTasks(ctx context.Context, param usecase.BatchTasksParams) (*usecase.BatchTasksResult, error) {
+	results := make([]usecase.BatchOperationResult, len(param.Operations))
+
+	if !param.Atomic {
+		for i, op := range param.Operations {
+			results[i] = a.applyBatchOperation(ctx, op)
+		}
+
+		return &usecase.BatchTasksResult{Results: results}, nil
+	}
+
+	failed := -1
+
+	err := a.withinTransaction(ctx, func(ctx context.Context, txUseCase *TaskUseCaseImpl) error {
+		for i, op := range param.Operations {
+			results[i] = txUseCase.applyBatchOperation(ctx, op)
+			if results[i].Err != nil {
+				failed = i
+
+				return errBatchAborted
+			}
+		}
+
+		return nil
+	})
+	if failed >= 0 {
+		for i := range results {
+			if i != failed {
+				results[i] = usecase.BatchOperationResult{Err: usecase.AbortedError{Index: failed}}
+			}
+		}
+
+		return &usecase.BatchTasksResult{Results: results}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repo.WithinTransaction error: %w", err)
+	}
+
+	return &usecase.BatchTasksResult{Results: results}, nil
+}
+
+func (a *TaskUseCaseImpl) applyBatchOperation(ctx context.Context, op usecase.BatchOperation) usecase.BatchOperationResult {
+	switch op.Type {
+	case usecase.BatchOperationCreate:
+		created, err := a.CreateTask(ctx, usecase.CreateTaskParams{
+			Name:        op.Name,
+			Description: op.Description,
+			Priority:    op.Priority,
+			DueAt:       op.DueAt,
+			Tags:        op.Tags,
+			ParentID:    op.ParentID,
+			Recurrence:  op.Recurrence,
+			ListID:      op.ListID,
+		})
+
+		return usecase.BatchOperationResult{Task: created, Err: err}
+	case usecase.BatchOperationUpdate:
+		var listID *uint
+		if op.ListID != 0 {
+			listID = &op.ListID
+		}
+
+		updated, err := a.UpdateTask(ctx, usecase.UpdateTaskParams{
+			ID:              op.ID,
+			Name:            &op.Name,
+			Description:     &op.Description,
+			Status:          &op.Status,
+			Priority:        &op.Priority,
+			DueAt:           &op.DueAt,
+			Tags:            &op.Tags,
+			ParentID:        &op.ParentID,
+			Recurrence:      &op.Recurrence,
+			ListID:          listID,
+			ExpectedVersion: op.ExpectedVersion,
+		})
+
+		return usecase.BatchOperationResult{Task: updated, Err: err}
+	case usecase.BatchOperationDelete:
+		return usecase.BatchOperationResult{Err: a.DeleteTask(ctx, op.ID)}
+	default:
+		return usecase.BatchOperationResult{Err: usecase.InvalidArgumentError{
+			Argument: "operation",
+			Reason:   fmt.Sprintf("unknown operation type %q", op.Type),
+		}}
+	}
+}
+
+// bufferedHistory holds back the history of a transaction until it commits.
+type bufferedHistory struct {
+	entries []*entities.TaskHistory
+}
+
+func (h *bufferedHistory) AppendHistory(_ context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error) {
+	h.entries = append(h.entries, entry)
+
+	return entry, nil
+}
+
+// ListHistoryByTaskID is not supported, the operations of a batch do not read history.
+func (h *bufferedHistory) ListHistoryByTaskID(context.Context, uint, int, int) ([]*entities.TaskHistory, int, error) {
+	return nil, 0, repository.ErrInvalidData
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskUseCaseImpl_BatchTasks(t *testing.T) {
+	t.Parallel()
+
+	ops := []usecase.BatchOperation{
+		{Type: usecase.BatchOperationCreate, Name: "new"},
+		{Type: usecase.BatchOperationDelete, ID: 9},
+		{Type: usecase.BatchOperationDelete, ID: 1},
+	}
+
+	// inTransaction makes WithinTransaction run its function against the repository itself
+	inTransaction := func(mockRepo *repositorymock.MockRepository) {
+		mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+				return fn(ctx, mockRepo)
+			})
+	}
+
+	tests := []struct {
+		name        string
+		atomic      bool
+		setup       func(mockRepo *repositorymock.MockRepository)
+		wantHistory int
+		wantErrs    []error
+	}{
+		{
+			name: "best effort applies the operations that succeed",
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(&entities.Task{ID: 2, Name: "new"}, nil)
+				mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(9)).Return(repository.ErrDataNotFound)
+				mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil)
+			},
+			wantHistory: 2,
+			wantErrs:    []error{nil, usecase.NotFoundError{Resource: "task", ID: uint(9)}, nil},
+		},
+		{
+			name:   "atomic aborts at the first failure",
+			atomic: true,
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				inTransaction(mockRepo)
+				mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(&entities.Task{ID: 2, Name: "new"}, nil)
+				mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(9)).Return(repository.ErrDataNotFound)
+			},
+			wantErrs: []error{
+				usecase.AbortedError{Index: 1},
+				usecase.NotFoundError{Resource: "task", ID: uint(9)},
+				usecase.AbortedError{Index: 1},
+			},
+		},
+		{
+			name:   "atomic records the history once committed",
+			atomic: true,
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				inTransaction(mockRepo)
+				mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(&entities.Task{ID: 2, Name: "new"}, nil)
+				mockRepo.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			wantHistory: 3,
+			wantErrs:    []error{nil, nil, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+			noSubtasks(mockRepo)
+			tt.setup(mockRepo)
+
+			mockHistory := repositorymock.NewMockHistoryRepository(ctrl)
+			mockHistory.EXPECT().AppendHistory(gomock.Any(), gomock.Any()).Return(nil, nil).Times(tt.wantHistory)
+
+			got, err := NewTaskUseCaseImpl(mockRepo, mockHistory, noLists(ctrl)).BatchTasks(context.Background(), usecase.BatchTasksParams{
+				Operations: ops,
+				Atomic:     tt.atomic,
+			})
+			require.NoError(t, err)
+			require.Len(t, got.Results, len(ops))
+
+			for i, result := range got.Results {
+				assert.Equal(t, tt.wantErrs[i], result.Err, "operation %d", i)
+			}
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_BatchTasks_TransactionError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	errCommit := errors.New("commit failed")
+
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	noSubtasks(mockRepo)
+	mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+			if err := fn(ctx, mockRepo); err != nil {
+				return err
+			}
+
+			return errCommit
+		})
+	mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil)
+
+	// nothing was committed, so no history is recorded
+	uc := NewTaskUseCaseImpl(mockRepo, repositorymock.NewMockHistoryRepository(ctrl), noLists(ctrl))
+
+	_, err := uc.BatchTasks(context.Background(), usecase.BatchTasksParams{
+		Operations: []usecase.BatchOperation{{Type: usecase.BatchOperationDelete, ID: 1}},
+		Atomic:     true,
+	})
+	assert.ErrorIs(t, err, errCommit)
+}
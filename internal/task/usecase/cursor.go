@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"strings"
+	"time"
+)
+
+// cursorToken is the content of an opaque cursor: the position of a task in one sort order.
+// Only the values of the sorted fields are kept.
+type cursorToken struct {
+	Sort      string           `json:"s,omitempty"`
+	Backward  bool             `json:"b,omitempty"`
+	ID        uint             `json:"i"`
+	Name      *string          `json:"n,omitempty"`
+	Status    *task.TaskStatus `json:"st,omitempty"`
+	Priority  *task.Priority   `json:"p,omitempty"`
+	CreatedAt *time.Time       `json:"c,omitempty"`
+	UpdatedAt *time.Time       `json:"u,omitempty"`
+}
+
+// encodeCursor returns the cursor listing the tasks after t in the sort order or, with backward, before it.
+func encodeCursor(t *entities.Task, sort []repository.SortKey, backward bool) string {
+	token := cursorToken{Sort: formatSort(sort), Backward: backward, ID: t.ID}
+
+	for _, key := range sort {
+		switch key.Field {
+		case repository.SortFieldName:
+			token.Name = &t.Name
+		case repository.SortFieldStatus:
+			token.Status = &t.Status
+		case repository.SortFieldPriority:
+			token.Priority = &t.Priority
+		case repository.SortFieldCreatedAt:
+			token.CreatedAt = &t.CreatedAt
+		case repository.SortFieldUpdatedAt:
+			token.UpdatedAt = &t.UpdatedAt
+		case repository.SortFieldID:
+		}
+	}
+
+	// marshaling a struct of plain fields cannot fail
+	payload, _ := json.Marshal(token)
+
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// decodeCursor returns the task position held by a cursor and its direction.
+// A cursor only makes sense in the sort order it was issued for.
+func decodeCursor(s string, sort []repository.SortKey) (*entities.Task, bool, error) {
+	invalid := usecase.InvalidArgumentError{Argument: "cursor", Reason: "malformed cursor"}
+
+	payload, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, false, invalid
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return nil, false, invalid
+	}
+
+	if token.Sort != formatSort(sort) {
+		return nil, false, usecase.InvalidArgumentError{Argument: "cursor", Reason: "cursor was issued for another sort order"}
+	}
+
+	cursor := &entities.Task{ID: token.ID}
+
+	for _, key := range sort {
+		var ok bool
+
+		switch key.Field {
+		case repository.SortFieldName:
+			ok = token.Name != nil
+			if ok {
+				cursor.Name = *token.Name
+			}
+		case repository.SortFieldStatus:
+			ok = token.Status != nil
+			if ok {
+				cursor.Status = *token.Status
+			}
+		case repository.SortFieldPriority:
+			ok = token.Priority != nil
+			if ok {
+				cursor.Priority = *token.Priority
+			}
+		case repository.SortFieldCreatedAt:
+			ok = token.CreatedAt != nil
+			if ok {
+				cursor.CreatedAt = *token.CreatedAt
+			}
+		case repository.SortFieldUpdatedAt:
+			ok = token.UpdatedAt != nil
+			if ok {
+				cursor.UpdatedAt = *token.UpdatedAt
+			}
+		case repository.SortFieldID:
+			ok = true
+		}
+
+		if !ok {
+			return nil, false, invalid
+		}
+	}
+
+	return cursor, token.Backward, nil
+}
+
+// formatSort renders sort keys the way the `sort` query parameter spells them.
+func formatSort(sort []repository.SortKey) string {
+	parts := make([]string, 0, len(sort))
+
+	for _, key := range sort {
+		if key.Desc {
+			parts = append(parts, "-"+string(key.Field))
+		} else {
+			parts = append(parts, string(key.Field))
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
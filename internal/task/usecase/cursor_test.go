@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+	sort := []repository.SortKey{{Field: repository.SortFieldStatus}, {Field: repository.SortFieldCreatedAt, Desc: true}}
+	original := &entities.Task{ID: 4, Name: "secret", Status: task.TaskStatusCompleted, CreatedAt: createdAt}
+
+	cursor, backward, err := decodeCursor(encodeCursor(original, sort, true), sort)
+	assert.NoError(t, err)
+	assert.True(t, backward)
+	assert.Equal(t, &entities.Task{ID: 4, Status: task.TaskStatusCompleted, CreatedAt: createdAt}, cursor)
+
+	_, _, err = decodeCursor(encodeCursor(original, sort, false), nil)
+	assert.ErrorAs(t, err, &usecase.InvalidArgumentError{})
+
+	_, _, err = decodeCursor("not a cursor!", sort)
+	assert.ErrorAs(t, err, &usecase.InvalidArgumentError{})
+}
+
+func TestTaskUseCaseImpl_ListTasks_Cursors(t *testing.T) {
+	t.Parallel()
+
+	page := []*entities.Task{{ID: 3}, {ID: 4}}
+
+	tests := []struct {
+		name     string
+		param    usecase.ListTasksParams
+		mockRepo func(ctrl *gomock.Controller) repository.Repository
+		wantNext *entities.Task
+		wantPrev *entities.Task
+	}{
+		{
+			name:  "middle offset page",
+			param: usecase.ListTasksParams{PageIndex: 2, PageSize: 2},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().ListTasksByPage(gomock.Any(), gomock.Any()).Return(page, 5, nil)
+
+				return mockRepo
+			},
+			wantNext: page[1],
+			wantPrev: page[0],
+		},
+		{
+			name:  "last offset page",
+			param: usecase.ListTasksParams{PageIndex: 2, PageSize: 2},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().ListTasksByPage(gomock.Any(), gomock.Any()).Return(page, 4, nil)
+
+				return mockRepo
+			},
+			wantPrev: page[0],
+		},
+		{
+			name:  "forward cursor at the end",
+			param: usecase.ListTasksParams{PageSize: 2, Cursor: encodeCursor(&entities.Task{ID: 2}, nil, false)},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().ListTasksByCursor(gomock.Any(), repository.TaskCursorQuery{
+					Cursor: &entities.Task{ID: 2},
+					Limit:  2,
+				}).Return(page, 4, false, nil)
+
+				return mockRepo
+			},
+			wantPrev: page[0],
+		},
+		{
+			name:  "backward cursor with more before",
+			param: usecase.ListTasksParams{PageSize: 2, Cursor: encodeCursor(&entities.Task{ID: 5}, nil, true)},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().ListTasksByCursor(gomock.Any(), repository.TaskCursorQuery{
+					Cursor:   &entities.Task{ID: 5},
+					Backward: true,
+					Limit:    2,
+				}).Return(page, 6, true, nil)
+
+				return mockRepo
+			},
+			wantNext: page[1],
+			wantPrev: page[0],
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc := NewTaskUseCaseImpl(tt.mockRepo(gomock.NewController(t)), nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
+
+			got, err := uc.ListTasks(context.Background(), tt.param)
+			assert.NoError(t, err)
+
+			wantNext, wantPrev := "", ""
+			if tt.wantNext != nil {
+				wantNext = encodeCursor(tt.wantNext, nil, false)
+			}
+
+			if tt.wantPrev != nil {
+				wantPrev = encodeCursor(tt.wantPrev, nil, true)
+			}
+
+			assert.Equal(t, wantNext, got.NextCursor)
+			assert.Equal(t, wantPrev, got.PrevCursor)
+		})
+	}
+}
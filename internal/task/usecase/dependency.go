@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/workspace"
+	"hash/fnv"
+	"slices"
+	"sync"
+)
+
+// maxBlockers bounds the tasks a task can depend on.
+const maxBlockers = 20
+
+// dependencyLocks serializes the changes that add dependencies in a workspace. The cycle check reads the graph
+// without locking it, which a transaction at the default isolation level does not prevent from changing, so two
+// additions checked at once could close a cycle together. Workspaces share a fixed set of locks, held by the
+// process, so the instances of a service sharing one database must not add dependencies concurrently.
+type dependencyLocks [listLockCount]sync.Mutex
+
+func (l *dependencyLocks) of(ctx context.Context) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(workspace.FromContext(ctx)))
+
+	return &l[h.Sum32()%listLockCount]
+}
+
+// AddDependency is responsible for making a task depend on another one. Adding an existing dependency
+// changes nothing, and a dependency that would close a cycle is rejected.
+// The additions of a workspace are checked and made one at a time, so two of them cannot close a cycle together.
+func (a *TaskUseCaseImpl) AddDependency(ctx context.Context, param usecase.DependencyParams) (*entities.Task, error) {
+	lock := a.dependencyLocks.of(ctx)
+	lock.Lock()
+	defer lock.Unlock()
+
+	var updated *entities.Task
+
+	err := a.withinTransaction(ctx, func(ctx context.Context, txUseCase *TaskUseCaseImpl) error {
+		var err error
+		updated, err = txUseCase.addDependency(ctx, param)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (a *TaskUseCaseImpl) addDependency(ctx context.Context, param usecase.DependencyParams) (*entities.Task, error) {
+	current, err := a.getTask(ctx, param.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(current.BlockedBy, param.BlockerID) {
+		return current, nil
+	}
+
+	if len(current.BlockedBy) >= maxBlockers {
+		return nil, usecase.InvalidArgumentError{
+			Argument: "blocker_id",
+			Reason:   fmt.Sprintf("a task can depend on at most %d tasks", maxBlockers),
+		}
+	}
+
+	if _, err := a.taskRepo.GetTaskByID(ctx, param.BlockerID); err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return nil, usecase.InvalidArgumentError{Argument: "blocker_id", Reason: fmt.Sprintf("task %d not found", param.BlockerID)}
+		}
+
+		return nil, fmt.Errorf("repo.GetTaskByID error: %w", err)
+	}
+
+	if err := a.checkAcyclic(ctx, param); err != nil {
+		return nil, err
+	}
+
+	blockedBy := append(slices.Clone(current.BlockedBy), param.BlockerID)
+	slices.Sort(blockedBy)
+
+	return a.UpdateTask(ctx, usecase.UpdateTaskParams{
+		ID:              param.TaskID,
+		BlockedBy:       &blockedBy,
+		ExpectedVersion: current.Version,
+	})
+}
+
+// RemoveDependency is responsible for removing a dependency of a task.
+func (a *TaskUseCaseImpl) RemoveDependency(ctx context.Context, param usecase.DependencyParams) (*entities.Task, error) {
+	current, err := a.getTask(ctx, param.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(current.BlockedBy, param.BlockerID) {
+		return nil, usecase.NotFoundError{
+			Resource: fmt.Sprintf("dependency of task %d on task", param.TaskID),
+			ID:       param.BlockerID,
+		}
+	}
+
+	blockedBy := slices.DeleteFunc(slices.Clone(current.BlockedBy), func(id uint) bool {
+		return id == param.BlockerID
+	})
+
+	return a.UpdateTask(ctx, usecase.UpdateTaskParams{
+		ID:              param.TaskID,
+		BlockedBy:       &blockedBy,
+		ExpectedVersion: current.Version,
+	})
+}
+
+// checkAcyclic rejects a dependency whose blocker already depends on the task, directly or not.
+// Trashed tasks block nothing, so the walk does not follow them.
+func (a *TaskUseCaseImpl) checkAcyclic(ctx context.Context, param usecase.DependencyParams) error {
+	visited := make(map[uint]bool)
+	pending := []uint{param.BlockerID}
+
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if id == param.TaskID {
+			return usecase.ConflictError{
+				Resource: "task",
+				ID:       param.TaskID,
+				Reason:   fmt.Sprintf("depending on task %d would create a dependency cycle", param.BlockerID),
+			}
+		}
+
+		if visited[id] {
+			continue
+		}
+
+		visited[id] = true
+
+		blocker, err := a.taskRepo.GetTaskByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrDataNotFound) {
+				continue
+			}
+
+			return fmt.Errorf("repo.GetTaskByID error: %w", err)
+		}
+
+		pending = append(pending, blocker.BlockedBy...)
+	}
+
+	return nil
+}
+
+// acyclicBlockers returns the blockers of a restored task that do not close a dependency cycle. The cycle check
+// does not follow trashed tasks, so one the task depends on may have come to depend on it while it was trashed.
+func (a *TaskUseCaseImpl) acyclicBlockers(ctx context.Context, restored *entities.Task) ([]uint, error) {
+	blockedBy := make([]uint, 0, len(restored.BlockedBy))
+
+	for _, blockerID := range restored.BlockedBy {
+		err := a.checkAcyclic(ctx, usecase.DependencyParams{TaskID: restored.ID, BlockerID: blockerID})
+
+		var conflict usecase.ConflictError
+
+		switch {
+		case errors.As(err, &conflict):
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		blockedBy = append(blockedBy, blockerID)
+	}
+
+	return blockedBy, nil
+}
+
+// checkDependencies refuses to complete a task while some of the tasks it depends on are live and not closed.
+func (a *TaskUseCaseImpl) checkDependencies(ctx context.Context, before, after *entities.Task) error {
+	if after.Status != task.TaskStatusCompleted || before.Status == task.TaskStatusCompleted {
+		return nil
+	}
+
+	open := make([]uint, 0)
+
+	for _, id := range after.BlockedBy {
+		blocker, err := a.taskRepo.GetTaskByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrDataNotFound) {
+				continue
+			}
+
+			return fmt.Errorf("repo.GetTaskByID error: %w", err)
+		}
+
+		if !blocker.Status.Closed() {
+			open = append(open, id)
+		}
+	}
+
+	if len(open) > 0 {
+		return usecase.BlockedByDependenciesError{ID: after.ID, Blockers: open}
+	}
+
+	return nil
+}
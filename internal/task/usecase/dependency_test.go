@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withTasks makes GetTaskByID look the tasks up in a fixed set, with copies so that tests cannot change them.
+func withTasks(mockRepo *repositorymock.MockRepository, tasks ...*entities.Task) {
+	byID := make(map[uint]*entities.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	mockRepo.EXPECT().GetTaskByID(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id uint) (*entities.Task, error) {
+			t, ok := byID[id]
+			if !ok {
+				return nil, repository.ErrDataNotFound
+			}
+
+			clone := *t
+
+			return &clone, nil
+		}).AnyTimes()
+}
+
+func TestTaskUseCaseImpl_AddDependency(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		param     usecase.DependencyParams
+		tasks     []*entities.Task
+		wantSaved []uint
+		wantErr   error
+	}{
+		{
+			name:  "adds the blocker in order",
+			param: usecase.DependencyParams{TaskID: 1, BlockerID: 2},
+			tasks: []*entities.Task{
+				{ID: 1, BlockedBy: []uint{3}, Version: 4},
+				{ID: 2},
+				{ID: 3},
+			},
+			wantSaved: []uint{2, 3},
+		},
+		{
+			name:  "existing dependency changes nothing",
+			param: usecase.DependencyParams{TaskID: 1, BlockerID: 2},
+			tasks: []*entities.Task{{ID: 1, BlockedBy: []uint{2}}, {ID: 2}},
+		},
+		{
+			name:    "unknown task",
+			param:   usecase.DependencyParams{TaskID: 9, BlockerID: 2},
+			tasks:   []*entities.Task{{ID: 2}},
+			wantErr: usecase.NotFoundError{Resource: "task", ID: uint(9)},
+		},
+		{
+			name:    "unknown blocker",
+			param:   usecase.DependencyParams{TaskID: 1, BlockerID: 9},
+			tasks:   []*entities.Task{{ID: 1}},
+			wantErr: usecase.InvalidArgumentError{Argument: "blocker_id", Reason: "task 9 not found"},
+		},
+		{
+			name:  "direct cycle",
+			param: usecase.DependencyParams{TaskID: 1, BlockerID: 2},
+			tasks: []*entities.Task{{ID: 1}, {ID: 2, BlockedBy: []uint{1}}},
+			wantErr: usecase.ConflictError{
+				Resource: "task", ID: uint(1), Reason: "depending on task 2 would create a dependency cycle",
+			},
+		},
+		{
+			name:  "indirect cycle",
+			param: usecase.DependencyParams{TaskID: 1, BlockerID: 2},
+			tasks: []*entities.Task{{ID: 1}, {ID: 2, BlockedBy: []uint{3, 4}}, {ID: 3, BlockedBy: []uint{4}}, {ID: 4, BlockedBy: []uint{1}}},
+			wantErr: usecase.ConflictError{
+				Resource: "task", ID: uint(1), Reason: "depending on task 2 would create a dependency cycle",
+			},
+		},
+		{
+			name:    "self dependency",
+			param:   usecase.DependencyParams{TaskID: 1, BlockerID: 1},
+			tasks:   []*entities.Task{{ID: 1}},
+			wantErr: usecase.ConflictError{Resource: "task", ID: uint(1), Reason: "depending on task 1 would create a dependency cycle"},
+		},
+		{
+			name:  "too many blockers",
+			param: usecase.DependencyParams{TaskID: 1, BlockerID: 2},
+			tasks: []*entities.Task{{ID: 1, BlockedBy: make([]uint, maxBlockers)}, {ID: 2}},
+			wantErr: usecase.InvalidArgumentError{
+				Argument: "blocker_id", Reason: "a task can depend on at most 20 tasks",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+			noSubtasks(mockRepo)
+			withTasks(mockRepo, tt.tasks...)
+			mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+					return fn(ctx, mockRepo)
+				})
+
+			if tt.wantSaved != nil {
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+						assert.Equal(t, tt.wantSaved, saved.BlockedBy)
+						assert.Equal(t, tt.tasks[0].Version, saved.Version)
+
+						return saved, nil
+					})
+			}
+
+			got, err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl)).AddDependency(context.Background(), tt.param)
+			assert.Equal(t, tt.wantErr, err)
+
+			if tt.wantErr == nil {
+				assert.Contains(t, got.BlockedBy, tt.param.BlockerID)
+			}
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_RemoveDependency(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	noSubtasks(mockRepo)
+	withTasks(mockRepo, &entities.Task{ID: 1, BlockedBy: []uint{2, 3}, Version: 2})
+	mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+			return saved, nil
+		})
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+	got, err := uc.RemoveDependency(context.Background(), usecase.DependencyParams{TaskID: 1, BlockerID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{3}, got.BlockedBy)
+
+	_, err = uc.RemoveDependency(context.Background(), usecase.DependencyParams{TaskID: 1, BlockerID: 4})
+	assert.Equal(t, usecase.NotFoundError{Resource: "dependency of task 1 on task", ID: uint(4)}, err)
+}
+
+func TestTaskUseCaseImpl_UpdateTask_BlockedByDependencies(t *testing.T) {
+	t.Parallel()
+
+	completed := task.TaskStatusCompleted.Ref()
+
+	ctrl := gomock.NewController(t)
+
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	noSubtasks(mockRepo)
+	// task 4 was purged, and a trashed task is not found either, so neither blocks
+	withTasks(mockRepo,
+		&entities.Task{ID: 1, BlockedBy: []uint{2, 3, 4}},
+		&entities.Task{ID: 2, Status: task.TaskStatusCompleted},
+		&entities.Task{ID: 3, Status: task.TaskStatusIncomplete},
+		&entities.Task{ID: 5, BlockedBy: []uint{2, 4}},
+	)
+	mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+			return saved, nil
+		})
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+	_, err := uc.UpdateTask(context.Background(), usecase.UpdateTaskParams{ID: 1, Status: &completed})
+	assert.Equal(t, usecase.BlockedByDependenciesError{ID: uint(1), Blockers: []uint{3}}, err)
+
+	got, err := uc.UpdateTask(context.Background(), usecase.UpdateTaskParams{ID: 5, Status: &completed})
+	assert.NoError(t, err)
+	assert.Equal(t, task.TaskStatusCompleted, got.Status)
+}
+
+func TestTaskUseCaseImpl_RestoreTask_DropsCyclicDependencies(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+
+	// task 2 came to depend on task 1 while task 1 was trashed
+	restored := &entities.Task{ID: 1, Name: "task", BlockedBy: []uint{2, 3}, Version: 2}
+	mockRepo.EXPECT().RestoreTask(gomock.Any(), uint(1)).Return(restored, nil)
+	withTasks(mockRepo,
+		restored,
+		&entities.Task{ID: 2, BlockedBy: []uint{4}},
+		&entities.Task{ID: 3},
+		&entities.Task{ID: 4, BlockedBy: []uint{1}},
+	)
+	mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{ID: 1, Name: "task", BlockedBy: []uint{3}, Version: 2}).
+		DoAndReturn(func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+			return saved, nil
+		})
+
+	got, err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl)).RestoreTask(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3}, got.BlockedBy)
+}
+
+func TestTaskUseCaseImpl_AddDependency_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var (
+		mu    sync.Mutex
+		once  sync.Once
+		graph = map[uint][]uint{1: nil, 2: nil}
+	)
+
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	noSubtasks(mockRepo)
+	mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+			return fn(ctx, mockRepo)
+		}).Times(2)
+	mockRepo.EXPECT().GetTaskByID(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id uint) (*entities.Task, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return &entities.Task{ID: id, BlockedBy: slices.Clone(graph[id])}, nil
+		}).AnyTimes()
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+	reverse := make(chan error, 1)
+
+	// the reverse dependency is added while the first one is checked but not saved yet
+	mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+			once.Do(func() {
+				go func() {
+					_, err := uc.AddDependency(context.Background(), usecase.DependencyParams{TaskID: 2, BlockerID: 1})
+					reverse <- err
+				}()
+				time.Sleep(20 * time.Millisecond)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			graph[saved.ID] = saved.BlockedBy
+
+			return saved, nil
+		}).AnyTimes()
+
+	_, err := uc.AddDependency(context.Background(), usecase.DependencyParams{TaskID: 1, BlockerID: 2})
+	require.NoError(t, err)
+	assert.Equal(t, usecase.ConflictError{
+		Resource: "task", ID: uint(2), Reason: "depending on task 1 would create a dependency cycle",
+	}, <-reverse)
+}
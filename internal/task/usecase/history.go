@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/actor"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// untrackedFields are the task fields maintained by the repository rather than changed by users.
+var untrackedFields = map[string]bool{
+	"id":         true,
+	"version":    true,
+	"created_at": true,
+	"created_by": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+// ListTaskHistory is responsible for listing the change history of a task by page, newest first.
+func (a *TaskUseCaseImpl) ListTaskHistory(ctx context.Context, param usecase.ListTaskHistoryParams) (*usecase.ListTaskHistoryResult, error) {
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	history, total, err := a.historyRepo.ListHistoryByTaskID(ctx, param.TaskID, param.PageIndex, param.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("repo.ListHistoryByTaskID error: %w", err)
+	}
+
+	return &usecase.ListTaskHistoryResult{
+		History: history,
+		Total:   total,
+	}, nil
+}
+
+// recordHistory appends a history entry for a change that has already been applied.
+// A failure is logged rather than returned, so the caller does not retry a change that succeeded.
+func (a *TaskUseCaseImpl) recordHistory(ctx context.Context, taskID uint, action entities.HistoryAction, changes []entities.FieldChange) {
+	entry := &entities.TaskHistory{
+		TaskID:    taskID,
+		Action:    action,
+		Actor:     actor.FromContext(ctx),
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+
+	a.appendHistory(ctx, entry)
+}
+
+func (a *TaskUseCaseImpl) appendHistory(ctx context.Context, entry *entities.TaskHistory) {
+	if _, err := a.historyRepo.AppendHistory(ctx, entry); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Uint("task_id", entry.TaskID).Str("action", string(entry.Action)).Msg("record task history error")
+	}
+}
+
+// diffTask returns the user-visible fields that differ between two states of a task.
+// A nil before lists every field, as for a newly created task.
+func diffTask(before, after *entities.Task) []entities.FieldChange {
+	changes := make([]entities.FieldChange, 0)
+
+	afterValue := reflect.ValueOf(after).Elem()
+	taskType := afterValue.Type()
+
+	for i := 0; i < taskType.NumField(); i++ {
+		name, _, _ := strings.Cut(taskType.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" || untrackedFields[name] {
+			continue
+		}
+
+		change := entities.FieldChange{Field: name, After: afterValue.Field(i).Interface()}
+
+		if before != nil {
+			change.Before = reflect.ValueOf(before).Elem().Field(i).Interface()
+			if reflect.DeepEqual(change.Before, change.After) {
+				continue
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
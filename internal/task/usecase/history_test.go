@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"ggltask/pkg/actor"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTask(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	before := &entities.Task{ID: 1, Name: "task", Status: task.TaskStatusIncomplete, Version: 1, CreatedAt: now}
+	after := &entities.Task{ID: 1, Name: "task", Status: task.TaskStatusCompleted, Version: 2, CreatedAt: now, UpdatedAt: now}
+
+	assert.Equal(t, []entities.FieldChange{
+		{Field: "status", Before: task.TaskStatusIncomplete, After: task.TaskStatusCompleted},
+	}, diffTask(before, after))
+
+	assert.Equal(t, []entities.FieldChange{
+		{Field: "name", Before: nil, After: "task"},
+		{Field: "list_id", Before: nil, After: uint(0)},
+		{Field: "description", Before: nil, After: ""},
+		{Field: "status", Before: nil, After: task.TaskStatusCompleted},
+		{Field: "priority", Before: nil, After: task.PriorityNone},
+		{Field: "due_at", Before: nil, After: (*time.Time)(nil)},
+		{Field: "tags", Before: nil, After: []string(nil)},
+		{Field: "parent_id", Before: nil, After: (*uint)(nil)},
+		{Field: "blocked_by", Before: nil, After: []uint(nil)},
+		{Field: "recurrence", Before: nil, After: (*entities.Recurrence)(nil)},
+	}, diffTask(nil, after))
+
+	due := now.Add(time.Hour)
+	tagged := &entities.Task{
+		ID: 1, Name: "task", Status: task.TaskStatusCompleted, DueAt: &due, Tags: []string{"ops"},
+		Version: 3, CreatedAt: now, UpdatedAt: now,
+	}
+	assert.Equal(t, []entities.FieldChange{
+		{Field: "due_at", Before: (*time.Time)(nil), After: &due},
+		{Field: "tags", Before: []string(nil), After: []string{"ops"}},
+	}, diffTask(after, tagged))
+
+	assert.Empty(t, diffTask(after, after))
+}
+
+func TestTaskUseCaseImpl_RecordsHistory(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	ctx := actor.WithActor(context.Background(), "alice")
+
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	noSubtasks(mockRepo)
+	mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Name: "old", Version: 1}, nil)
+	mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(&entities.Task{ID: 1, Name: "new", Version: 2}, nil)
+	mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil)
+
+	mockHistory := repositorymock.NewMockHistoryRepository(ctrl)
+	gomock.InOrder(
+		mockHistory.EXPECT().AppendHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error) {
+				assert.Equal(t, uint(1), entry.TaskID)
+				assert.Equal(t, entities.HistoryActionUpdate, entry.Action)
+				assert.Equal(t, "alice", entry.Actor)
+				assert.Equal(t, []entities.FieldChange{{Field: "name", Before: "old", After: "new"}}, entry.Changes)
+				assert.False(t, entry.CreatedAt.IsZero())
+
+				return entry, nil
+			}),
+		// a failing history store does not fail the change itself
+		mockHistory.EXPECT().AppendHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error) {
+				assert.Equal(t, entities.HistoryActionDelete, entry.Action)
+
+				return nil, repository.ErrInvalidData
+			}),
+	)
+
+	uc := NewTaskUseCaseImpl(mockRepo, mockHistory, noLists(ctrl))
+
+	_, err := uc.UpdateTask(ctx, usecase.UpdateTaskParams{ID: 1, Name: ptr("new")})
+	assert.NoError(t, err)
+	assert.NoError(t, uc.DeleteTask(ctx, 1))
+}
+
+func TestTaskUseCaseImpl_ListTaskHistory(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockHistory := repositorymock.NewMockHistoryRepository(ctrl)
+	mockHistory.EXPECT().ListHistoryByTaskID(gomock.Any(), uint(1), 1, 10).
+		Return([]*entities.TaskHistory{{ID: 2, TaskID: 1}, {ID: 1, TaskID: 1}}, 2, nil)
+	mockHistory.EXPECT().ListHistoryByTaskID(gomock.Any(), uint(1), 0, 10).Return(nil, 0, repository.ErrInvalidData)
+
+	uc := NewTaskUseCaseImpl(repositorymock.NewMockRepository(ctrl), mockHistory, noLists(ctrl))
+
+	got, err := uc.ListTaskHistory(context.Background(), usecase.ListTaskHistoryParams{TaskID: 1, PageIndex: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got.Total)
+	assert.Len(t, got.History, 2)
+
+	_, err = uc.ListTaskHistory(context.Background(), usecase.ListTaskHistoryParams{TaskID: 1, PageIndex: 0, PageSize: 10})
+	assert.ErrorIs(t, err, repository.ErrInvalidData)
+}
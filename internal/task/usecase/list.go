@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/workspace"
+	"hash/fnv"
+	"strconv"
+	"sync"
+)
+
+// listLockCount is the number of locks the lists share.
+const listLockCount = 64
+
+// listLocks keeps a list from being deleted while a task is put in it: putting a task in a list holds the
+// lock of the list for reading, and deleting the list holds it for writing from before its tasks are moved
+// out until it is gone. Lists share a fixed set of locks, by workspace and id.
+type listLocks [listLockCount]sync.RWMutex
+
+func (l *listLocks) of(ctx context.Context, listID uint) *sync.RWMutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(workspace.FromContext(ctx) + "/" + strconv.FormatUint(uint64(listID), 10)))
+
+	return &l[h.Sum32()%listLockCount]
+}
+
+// CreateList is responsible for creating a new list. The policy takes any change to lists for an update of tasks.
+func (a *TaskUseCaseImpl) CreateList(ctx context.Context, param usecase.CreateListParams) (*entities.List, error) {
+	if err := a.authorize(ctx, PolicyActionUpdate, nil); err != nil {
+		return nil, err
+	}
+
+	if err := validateList(param.Name, param.Description); err != nil {
+		return nil, err
+	}
+
+	list, err := a.listRepo.CreateList(ctx, &entities.List{Name: param.Name, Description: param.Description})
+	if err != nil {
+		return nil, fmt.Errorf("repo.CreateList error: %w", err)
+	}
+
+	return list, nil
+}
+
+// GetList is responsible for getting a list by id.
+func (a *TaskUseCaseImpl) GetList(ctx context.Context, id uint) (*entities.List, error) {
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	list, err := a.listRepo.GetListByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return nil, usecase.NotFoundError{
+				Resource: "list",
+				ID:       id,
+			}
+		}
+
+		return nil, fmt.Errorf("repo.GetListByID error: %w", err)
+	}
+
+	return list, nil
+}
+
+// ListLists is responsible for listing the lists by page, ordered by id.
+func (a *TaskUseCaseImpl) ListLists(ctx context.Context, param usecase.ListListsParams) (*usecase.ListListsResult, error) {
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	lists, total, err := a.listRepo.ListListsByPage(ctx, param.PageIndex, param.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("repo.ListListsByPage error: %w", err)
+	}
+
+	return &usecase.ListListsResult{
+		Lists: lists,
+		Total: total,
+	}, nil
+}
+
+// UpdateList is responsible for replacing the name and description of a list.
+func (a *TaskUseCaseImpl) UpdateList(ctx context.Context, param usecase.UpdateListParams) (*entities.List, error) {
+	if err := a.authorize(ctx, PolicyActionUpdate, nil); err != nil {
+		return nil, err
+	}
+
+	if err := validateList(param.Name, param.Description); err != nil {
+		return nil, err
+	}
+
+	list, err := a.listRepo.UpdateList(ctx, &entities.List{ID: param.ID, Name: param.Name, Description: param.Description})
+	if err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return nil, usecase.NotFoundError{
+				Resource: "list",
+				ID:       param.ID,
+			}
+		}
+
+		return nil, fmt.Errorf("repo.UpdateList error: %w", err)
+	}
+
+	return list, nil
+}
+
+// DeleteList is responsible for deleting a list. Its live tasks are archived into the default list
+// or moved to the trash, by the policy, in one transaction; the list is deleted once they are gone.
+// Its trashed tasks are left alone, and restoring one puts it in the default list. Each task archived or
+// trashed must be one the principal may update or delete.
+func (a *TaskUseCaseImpl) DeleteList(ctx context.Context, param usecase.DeleteListParams) error {
+	if !param.Tasks.Valid() {
+		return usecase.InvalidArgumentError{Argument: "tasks", Reason: "must be archive or cascade"}
+	}
+
+	if err := a.authorize(ctx, PolicyActionUpdate, nil); err != nil {
+		return err
+	}
+
+	if param.ID == entities.DefaultListID {
+		return usecase.ConflictError{
+			Resource: "list",
+			ID:       param.ID,
+			Reason:   "the default list cannot be deleted",
+		}
+	}
+
+	// no task can be put in the list from now on, so none is left in it once it is cleared
+	lock := a.listLocks.of(ctx, param.ID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := a.GetList(ctx, param.ID); err != nil {
+		return err
+	}
+
+	err := a.withinTransaction(ctx, func(ctx context.Context, txUseCase *TaskUseCaseImpl) error {
+		return txUseCase.clearList(ctx, param.ID, param.Tasks)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := a.listRepo.DeleteList(ctx, param.ID); err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return usecase.NotFoundError{
+				Resource: "list",
+				ID:       param.ID,
+			}
+		}
+
+		return fmt.Errorf("repo.DeleteList error: %w", err)
+	}
+
+	return nil
+}
+
+// listPageSize is the number of tasks clearList reads at a time.
+const listPageSize = 100
+
+// clearList archives or trashes the live tasks of a list, until none is left.
+func (a *TaskUseCaseImpl) clearList(ctx context.Context, listID uint, policy usecase.ListTasksPolicy) error {
+	archived := task.TaskStatusArchived.Ref()
+	defaultList := entities.DefaultListID
+
+	for {
+		// every task handled leaves the list, so the first page is always the next one
+		tasks, _, err := a.taskRepo.ListTasksByPage(ctx, repository.TaskQuery{
+			Filter:    repository.TaskFilter{ListID: listID},
+			PageIndex: 1,
+			PageSize:  listPageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("repo.ListTasksByPage error: %w", err)
+		}
+
+		if len(tasks) == 0 {
+			return nil
+		}
+
+		for _, t := range tasks {
+			if policy == usecase.ListTasksPolicyArchive {
+				_, err = a.UpdateTask(ctx, usecase.UpdateTaskParams{ID: t.ID, Status: &archived, ListID: &defaultList})
+			} else {
+				err = a.deleteTaskTree(ctx, t.ID)
+			}
+
+			// a task trashed along with an earlier one of the page is already gone
+			if err != nil && !errors.As(err, &usecase.NotFoundError{}) {
+				return err
+			}
+		}
+	}
+}
+
+// holdList keeps the list from being deleted until the returned func is called, so that a task put in it
+// after checkList is not left behind in a deleted list. The default list is never deleted, and a transaction
+// is already kept apart from the deletion of a list, which clears the list in a transaction of its own.
+func (a *TaskUseCaseImpl) holdList(ctx context.Context, listID uint) func() {
+	if isDefaultList(listID) || a.inTransaction {
+		return func() {}
+	}
+
+	lock := a.listLocks.of(ctx, listID)
+	lock.RLock()
+
+	return lock.RUnlock
+}
+
+// checkList checks that a task can be put in the list.
+func (a *TaskUseCaseImpl) checkList(ctx context.Context, listID uint) error {
+	if isDefaultList(listID) {
+		return nil
+	}
+
+	if _, err := a.listRepo.GetListByID(ctx, listID); err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return usecase.InvalidArgumentError{Argument: "list_id", Reason: fmt.Sprintf("list %d not found", listID)}
+		}
+
+		return fmt.Errorf("repo.GetListByID error: %w", err)
+	}
+
+	return nil
+}
+
+// isDefaultList reports whether the id is the default list; a task without a list is in it.
+func isDefaultList(listID uint) bool {
+	return listID == 0 || listID == entities.DefaultListID
+}
+
+func validateList(name, description string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	return validateDescription(description)
+}
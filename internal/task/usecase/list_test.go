@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskUseCaseImpl_Lists(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockLists := repositorymock.NewMockListRepository(ctrl)
+	mockLists.EXPECT().CreateList(gomock.Any(), &entities.List{Name: "work", Description: "office"}).
+		Return(&entities.List{ID: 2, Name: "work", Description: "office"}, nil)
+	mockLists.EXPECT().GetListByID(gomock.Any(), uint(9)).Return(nil, repository.ErrDataNotFound)
+	mockLists.EXPECT().UpdateList(gomock.Any(), &entities.List{ID: 9, Name: "home"}).Return(nil, repository.ErrDataNotFound)
+	mockLists.EXPECT().ListListsByPage(gomock.Any(), 1, 10).Return([]*entities.List{{ID: 1}, {ID: 2}}, 2, nil)
+
+	uc := NewTaskUseCaseImpl(repositorymock.NewMockRepository(ctrl), nopHistory(ctrl), mockLists)
+	ctx := context.Background()
+
+	created, err := uc.CreateList(ctx, usecase.CreateListParams{Name: "work", Description: "office"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), created.ID)
+
+	_, err = uc.CreateList(ctx, usecase.CreateListParams{})
+	assert.Equal(t, usecase.InvalidArgumentError{Argument: "name", Reason: "must be 1 to 50 characters"}, err)
+
+	_, err = uc.GetList(ctx, 9)
+	assert.Equal(t, usecase.NotFoundError{Resource: "list", ID: uint(9)}, err)
+
+	_, err = uc.UpdateList(ctx, usecase.UpdateListParams{ID: 9, Name: "home"})
+	assert.Equal(t, usecase.NotFoundError{Resource: "list", ID: uint(9)}, err)
+
+	result, err := uc.ListLists(ctx, usecase.ListListsParams{PageIndex: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+}
+
+func TestTaskUseCaseImpl_CreateTask_List(t *testing.T) {
+	t.Parallel()
+
+	parentID := uint(1)
+
+	tests := []struct {
+		name       string
+		params     usecase.CreateTaskParams
+		setup      func(mockRepo *repositorymock.MockRepository, mockLists *repositorymock.MockListRepository)
+		wantListID uint
+		wantErr    error
+	}{
+		{
+			name:       "default list",
+			params:     usecase.CreateTaskParams{Name: "task"},
+			setup:      func(*repositorymock.MockRepository, *repositorymock.MockListRepository) {},
+			wantListID: entities.DefaultListID,
+		},
+		{
+			name:   "given list",
+			params: usecase.CreateTaskParams{Name: "task", ListID: 2},
+			setup: func(_ *repositorymock.MockRepository, mockLists *repositorymock.MockListRepository) {
+				mockLists.EXPECT().GetListByID(gomock.Any(), uint(2)).Return(&entities.List{ID: 2}, nil)
+			},
+			wantListID: 2,
+		},
+		{
+			name:   "subtask inherits the list of its parent",
+			params: usecase.CreateTaskParams{Name: "task", ParentID: &parentID},
+			setup: func(mockRepo *repositorymock.MockRepository, mockLists *repositorymock.MockListRepository) {
+				withTasks(mockRepo, &entities.Task{ID: 1, ListID: 3})
+				mockLists.EXPECT().GetListByID(gomock.Any(), uint(3)).Return(&entities.List{ID: 3}, nil)
+			},
+			wantListID: 3,
+		},
+		{
+			name:   "unknown list",
+			params: usecase.CreateTaskParams{Name: "task", ListID: 9},
+			setup: func(_ *repositorymock.MockRepository, mockLists *repositorymock.MockListRepository) {
+				mockLists.EXPECT().GetListByID(gomock.Any(), uint(9)).Return(nil, repository.ErrDataNotFound)
+			},
+			wantErr: usecase.InvalidArgumentError{Argument: "list_id", Reason: "list 9 not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+			mockLists := repositorymock.NewMockListRepository(ctrl)
+			tt.setup(mockRepo, mockLists)
+			mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+					saved.ID = 2
+
+					return saved, nil
+				}).AnyTimes()
+
+			got, err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), mockLists).CreateTask(context.Background(), tt.params)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantListID, got.ListID)
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_ListTasks_UnknownList(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockLists := repositorymock.NewMockListRepository(ctrl)
+	mockLists.EXPECT().GetListByID(gomock.Any(), uint(9)).Return(nil, repository.ErrDataNotFound)
+
+	uc := NewTaskUseCaseImpl(repositorymock.NewMockRepository(ctrl), nopHistory(ctrl), mockLists)
+
+	_, err := uc.ListTasks(context.Background(), usecase.ListTasksParams{
+		PageIndex: 1,
+		PageSize:  10,
+		Filter:    repository.TaskFilter{ListID: 9},
+	})
+	assert.Equal(t, usecase.NotFoundError{Resource: "list", ID: uint(9)}, err)
+}
+
+func TestTaskUseCaseImpl_DeleteList(t *testing.T) {
+	t.Parallel()
+
+	listTasks := func(mockRepo *repositorymock.MockRepository, tasks ...*entities.Task) {
+		query := repository.TaskQuery{Filter: repository.TaskFilter{ListID: 2}, PageIndex: 1, PageSize: listPageSize}
+
+		gomock.InOrder(
+			mockRepo.EXPECT().ListTasksByPage(gomock.Any(), query).Return(tasks, len(tasks), nil),
+			mockRepo.EXPECT().ListTasksByPage(gomock.Any(), query).Return([]*entities.Task{}, 0, nil),
+		)
+	}
+
+	tests := []struct {
+		name    string
+		params  usecase.DeleteListParams
+		setup   func(mockRepo *repositorymock.MockRepository, mockLists *repositorymock.MockListRepository)
+		wantErr error
+	}{
+		{
+			name:   "archive moves the tasks to the default list",
+			params: usecase.DeleteListParams{ID: 2, Tasks: usecase.ListTasksPolicyArchive},
+			setup: func(mockRepo *repositorymock.MockRepository, mockLists *repositorymock.MockListRepository) {
+				mockLists.EXPECT().GetListByID(gomock.Any(), uint(2)).Return(&entities.List{ID: 2}, nil)
+				listTasks(mockRepo, &entities.Task{ID: 3, Name: "task", ListID: 2})
+				withTasks(mockRepo, &entities.Task{ID: 3, Name: "task", ListID: 2, Version: 1})
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{
+					ID: 3, Name: "task", ListID: entities.DefaultListID, Status: task.TaskStatusArchived, Version: 1,
+				}).DoAndReturn(func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+					return saved, nil
+				})
+				mockLists.EXPECT().DeleteList(gomock.Any(), uint(2)).Return(nil)
+			},
+		},
+		{
+			name:   "cascade trashes the tasks and their subtasks",
+			params: usecase.DeleteListParams{ID: 2, Tasks: usecase.ListTasksPolicyCascade},
+			setup: func(mockRepo *repositorymock.MockRepository, mockLists *repositorymock.MockListRepository) {
+				parentID := uint(3)
+
+				mockLists.EXPECT().GetListByID(gomock.Any(), uint(2)).Return(&entities.List{ID: 2}, nil)
+				listTasks(mockRepo, &entities.Task{ID: 3, ListID: 2}, &entities.Task{ID: 4, ListID: 2, ParentID: &parentID})
+				// task 4 is trashed along with task 3, so it is gone by its turn in the page
+				gomock.InOrder(
+					mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(3)).Return(&entities.Task{ID: 3, ListID: 2}, nil),
+					mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(4)).Return(&entities.Task{ID: 4, ListID: 2, ParentID: &parentID}, nil),
+					mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(4)).Return(nil, repository.ErrDataNotFound),
+				)
+				mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(3)).Return([]*entities.Task{{ID: 4, ParentID: &parentID}}, nil)
+				mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(4)).Return([]*entities.Task{}, nil)
+				gomock.InOrder(
+					mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(4)).Return(nil),
+					mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(3)).Return(nil),
+				)
+				mockLists.EXPECT().DeleteList(gomock.Any(), uint(2)).Return(nil)
+			},
+		},
+		{
+			name:    "unknown policy",
+			params:  usecase.DeleteListParams{ID: 2, Tasks: "keep"},
+			setup:   func(*repositorymock.MockRepository, *repositorymock.MockListRepository) {},
+			wantErr: usecase.InvalidArgumentError{Argument: "tasks", Reason: "must be archive or cascade"},
+		},
+		{
+			name:    "default list",
+			params:  usecase.DeleteListParams{ID: entities.DefaultListID, Tasks: usecase.ListTasksPolicyArchive},
+			setup:   func(*repositorymock.MockRepository, *repositorymock.MockListRepository) {},
+			wantErr: usecase.ConflictError{Resource: "list", ID: entities.DefaultListID, Reason: "the default list cannot be deleted"},
+		},
+		{
+			name:   "unknown list",
+			params: usecase.DeleteListParams{ID: 9, Tasks: usecase.ListTasksPolicyCascade},
+			setup: func(_ *repositorymock.MockRepository, mockLists *repositorymock.MockListRepository) {
+				mockLists.EXPECT().GetListByID(gomock.Any(), uint(9)).Return(nil, repository.ErrDataNotFound)
+			},
+			wantErr: usecase.NotFoundError{Resource: "list", ID: uint(9)},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+			mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+					return fn(ctx, mockRepo)
+				}).AnyTimes()
+			mockLists := repositorymock.NewMockListRepository(ctrl)
+			tt.setup(mockRepo, mockLists)
+
+			err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), mockLists).DeleteList(context.Background(), tt.params)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_DeleteList_ConcurrentCreate(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	var deleted atomic.Bool
+
+	mockLists := repositorymock.NewMockListRepository(ctrl)
+	mockLists.EXPECT().GetListByID(gomock.Any(), uint(2)).DoAndReturn(
+		func(context.Context, uint) (*entities.List, error) {
+			if deleted.Load() {
+				return nil, repository.ErrDataNotFound
+			}
+
+			return &entities.List{ID: 2}, nil
+		}).AnyTimes()
+	mockLists.EXPECT().DeleteList(gomock.Any(), uint(2)).DoAndReturn(func(context.Context, uint) error {
+		deleted.Store(true)
+
+		return nil
+	})
+
+	// the task is created while the list is cleared, so it must find the list gone
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+			return saved, nil
+		}).AnyTimes()
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), mockLists)
+	created := make(chan error, 1)
+
+	mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+			return fn(ctx, mockRepo)
+		})
+	mockRepo.EXPECT().ListTasksByPage(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, repository.TaskQuery) ([]*entities.Task, int, error) {
+			go func() {
+				_, err := uc.CreateTask(context.Background(), usecase.CreateTaskParams{Name: "task", ListID: 2})
+				created <- err
+			}()
+			time.Sleep(20 * time.Millisecond)
+
+			return []*entities.Task{}, 0, nil
+		})
+
+	err := uc.DeleteList(context.Background(), usecase.DeleteListParams{ID: 2, Tasks: usecase.ListTasksPolicyArchive})
+	assert.NoError(t, err)
+	assert.Equal(t, usecase.InvalidArgumentError{Argument: "list_id", Reason: "list 2 not found"}, <-created)
+}
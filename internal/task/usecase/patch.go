@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/jsonpatch"
+	"time"
+)
+
+// PatchTask is responsible for applying a JSON Merge Patch or a JSON Patch to a task.
+// The patch applies to the JSON representation of the current task, so a JSON Patch test operation
+// sees the same version the change is made to; a patch that does not change anything is not persisted.
+func (a *TaskUseCaseImpl) PatchTask(ctx context.Context, param usecase.PatchTaskParams) (*entities.Task, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := a.getTask(ctx, param.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if param.ExpectedVersion != 0 && param.ExpectedVersion != current.Version {
+			return nil, usecase.PreconditionFailedError{
+				Resource: "task",
+				ID:       param.ID,
+			}
+		}
+
+		update, err := patchTask(current, param.Format, param.Patch)
+		if err != nil {
+			return nil, err
+		}
+
+		if update == (usecase.UpdateTaskParams{ID: param.ID}) {
+			return current, nil
+		}
+
+		update.ExpectedVersion = current.Version
+
+		updated, err := a.UpdateTask(ctx, update)
+		if errors.As(err, &usecase.PreconditionFailedError{}) && param.ExpectedVersion == 0 && attempt < maxUpdateAttempts {
+			continue
+		}
+
+		return updated, err
+	}
+}
+
+// patchTask applies a patch to the JSON representation of a task and returns the fields it changed as an update.
+func patchTask(current *entities.Task, format usecase.PatchFormat, patch []byte) (usecase.UpdateTaskParams, error) {
+	update := usecase.UpdateTaskParams{ID: current.ID}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return update, fmt.Errorf("json.Marshal error: %w", err)
+	}
+
+	var patched []byte
+
+	switch format {
+	case usecase.PatchFormatMergePatch:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case usecase.PatchFormatJSONPatch:
+		patched, err = jsonpatch.Apply(doc, patch)
+	default:
+		return update, usecase.InvalidArgumentError{Argument: "patch", Reason: fmt.Sprintf("unsupported format %q", format)}
+	}
+
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return update, usecase.ConflictError{Resource: "task", ID: current.ID, Reason: err.Error()}
+	}
+
+	if err != nil {
+		return update, usecase.InvalidArgumentError{Argument: "patch", Reason: err.Error()}
+	}
+
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &before); err != nil {
+		return update, fmt.Errorf("json.Unmarshal error: %w", err)
+	}
+
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return update, usecase.InvalidArgumentError{Argument: "patch", Reason: "the patched task is not an object"}
+	}
+
+	for field := range after {
+		if _, ok := before[field]; !ok && !optionalFields[field] {
+			return update, usecase.InvalidArgumentError{Argument: "patch", Reason: fmt.Sprintf("field %q cannot be added", field)}
+		}
+	}
+
+	for field := range before {
+		if _, ok := after[field]; !ok && !optionalFields[field] {
+			return update, usecase.InvalidArgumentError{Argument: "patch", Reason: fmt.Sprintf("field %q cannot be removed", field)}
+		}
+	}
+
+	for field, value := range after {
+		if bytes.Equal(value, before[field]) {
+			continue
+		}
+
+		if err := setPatchedField(&update, field, value); err != nil {
+			return update, err
+		}
+	}
+
+	if _, ok := after["due_at"]; !ok && before["due_at"] != nil {
+		var cleared *time.Time
+		update.DueAt = &cleared
+	}
+
+	if _, ok := after["parent_id"]; !ok && before["parent_id"] != nil {
+		var detached *uint
+		update.ParentID = &detached
+	}
+
+	if _, ok := after["recurrence"]; !ok && before["recurrence"] != nil {
+		var stopped *entities.Recurrence
+		update.Recurrence = &stopped
+	}
+
+	return update, nil
+}
+
+// optionalFields are left out of the JSON of a task when unset, so adding one sets it and removing it clears it.
+var optionalFields = map[string]bool{
+	"due_at":     true,
+	"parent_id":  true,
+	"recurrence": true,
+}
+
+// setPatchedField sets the update field matching a changed field of the JSON representation of a task.
+func setPatchedField(update *usecase.UpdateTaskParams, field string, value json.RawMessage) error {
+	var err error
+
+	switch field {
+	case "name":
+		update.Name = new(string)
+		err = json.Unmarshal(value, update.Name)
+	case "description":
+		update.Description = new(string)
+		err = json.Unmarshal(value, update.Description)
+	case "status":
+		update.Status = new(task.StatusRef)
+		err = json.Unmarshal(value, update.Status)
+	case "priority":
+		update.Priority = new(task.Priority)
+		err = json.Unmarshal(value, update.Priority)
+	case "due_at":
+		update.DueAt = new(*time.Time)
+		err = json.Unmarshal(value, update.DueAt)
+	case "tags":
+		update.Tags = new([]string)
+		err = json.Unmarshal(value, update.Tags)
+	case "parent_id":
+		update.ParentID = new(*uint)
+		err = json.Unmarshal(value, update.ParentID)
+	case "recurrence":
+		update.Recurrence = new(*entities.Recurrence)
+		err = json.Unmarshal(value, update.Recurrence)
+	case "list_id":
+		update.ListID = new(uint)
+		err = json.Unmarshal(value, update.ListID)
+	default:
+		return usecase.InvalidArgumentError{Argument: "patch", Reason: fmt.Sprintf("field %q is read-only", field)}
+	}
+
+	if err != nil {
+		return usecase.InvalidArgumentError{Argument: field, Reason: "is not valid"}
+	}
+
+	return nil
+}
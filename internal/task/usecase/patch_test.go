@@ -0,0 +1,385 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskUseCaseImpl_PatchTask(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := time.Date(2030, 1, 2, 9, 0, 0, 0, time.FixedZone("", 2*60*60))
+	current := func() *entities.Task {
+		return &entities.Task{ID: 1, Name: "task", Status: task.TaskStatusIncomplete, Version: 3, CreatedAt: now, UpdatedAt: now}
+	}
+
+	// expectUpdate expects the task to be written back with the given name and status at version 3.
+	expectUpdate := func(mockRepo *repositorymock.MockRepository, name string, status task.TaskStatus) {
+		mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{ID: 1, Name: name, Status: status, Version: 3}).
+			Return(&entities.Task{ID: 1, Name: name, Status: status, Version: 4}, nil)
+	}
+
+	tests := []struct {
+		name     string
+		param    usecase.PatchTaskParams
+		mockRepo func(ctrl *gomock.Controller) repository.Repository
+		want     *entities.Task
+		wantErr  error
+	}{
+		{
+			name: "merge patch changes only the given field",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"status": 1}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil).Times(2)
+				expectUpdate(mockRepo, "task", task.TaskStatusCompleted)
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "task", Status: task.TaskStatusCompleted, Version: 4},
+		},
+		{
+			name: "merge patch sets the status by name",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"status": "in_progress"}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil).Times(2)
+				expectUpdate(mockRepo, "task", task.TaskStatusInProgress)
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "task", Status: task.TaskStatusInProgress, Version: 4},
+		},
+		{
+			name: "json patch with a passing test",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatJSONPatch,
+				Patch:  []byte(`[{"op": "test", "path": "/name", "value": "task"}, {"op": "replace", "path": "/name", "value": "renamed"}]`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil).Times(2)
+				expectUpdate(mockRepo, "renamed", task.TaskStatusIncomplete)
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "renamed", Status: task.TaskStatusIncomplete, Version: 4},
+		},
+		{
+			name: "merge patch sets the details",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"priority": "high", "due_at": "2030-01-02T09:00:00+02:00", "tags": ["Ops", "db"], "description": "more"}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil).Times(2)
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{
+					ID:          1,
+					Name:        "task",
+					Description: "more",
+					Priority:    task.PriorityHigh,
+					DueAt:       &due,
+					Tags:        []string{"db", "ops"},
+					Version:     3,
+				}).DoAndReturn(func(_ context.Context, t *entities.Task) (*entities.Task, error) {
+					t.Version = 4
+
+					return t, nil
+				})
+
+				return mockRepo
+			},
+			want: &entities.Task{
+				ID: 1, Name: "task", Description: "more", Priority: task.PriorityHigh, DueAt: &due, Tags: []string{"db", "ops"}, Version: 4,
+			},
+		},
+		{
+			name: "json patch removes the due date",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatJSONPatch,
+				Patch:  []byte(`[{"op": "remove", "path": "/due_at"}]`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				withDue := current()
+				withDue.DueAt = &due
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(withDue, nil).Times(2)
+				expectUpdate(mockRepo, "task", task.TaskStatusIncomplete)
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "task", Status: task.TaskStatusIncomplete, Version: 4},
+		},
+		{
+			name: "merge patch moves the task to the top level",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"parent_id": null}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				parentID := uint(7)
+				withParent := current()
+				withParent.ParentID = &parentID
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(withParent, nil).Times(2)
+				expectUpdate(mockRepo, "task", task.TaskStatusIncomplete)
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "task", Status: task.TaskStatusIncomplete, Version: 4},
+		},
+		{
+			name: "json patch stops the recurrence",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatJSONPatch,
+				Patch:  []byte(`[{"op": "remove", "path": "/recurrence"}]`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				recurring := current()
+				recurring.DueAt = &due
+				recurring.Recurrence = &entities.Recurrence{Rule: "FREQ=DAILY", TimeZone: "UTC"}
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(recurring, nil).Times(2)
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{ID: 1, Name: "task", DueAt: &due, Version: 3}).
+					Return(&entities.Task{ID: 1, Name: "task", DueAt: &due, Version: 4}, nil)
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "task", DueAt: &due, Version: 4},
+		},
+		{
+			name: "invalid priority",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"priority": "asap"}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+				return mockRepo
+			},
+			wantErr: usecase.InvalidArgumentError{},
+		},
+		{
+			name: "patch without changes is not persisted",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"name": "task"}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+				return mockRepo
+			},
+			want: current(),
+		},
+		{
+			name: "failed test operation",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatJSONPatch,
+				Patch:  []byte(`[{"op": "test", "path": "/status", "value": 1}, {"op": "replace", "path": "/name", "value": "renamed"}]`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+				return mockRepo
+			},
+			wantErr: usecase.ConflictError{},
+		},
+		{
+			name: "read-only field",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"version": 9}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+				return mockRepo
+			},
+			wantErr: usecase.InvalidArgumentError{},
+		},
+		{
+			name: "unknown field",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatJSONPatch,
+				Patch:  []byte(`[{"op": "add", "path": "/owner", "value": "me"}]`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+				return mockRepo
+			},
+			wantErr: usecase.InvalidArgumentError{},
+		},
+		{
+			name: "removing the name",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"name": null}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+				return mockRepo
+			},
+			wantErr: usecase.InvalidArgumentError{},
+		},
+		{
+			name: "invalid status",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"status": 7}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil).Times(2)
+
+				return mockRepo
+			},
+			wantErr: usecase.InvalidArgumentError{},
+		},
+		{
+			name: "malformed patch",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatJSONPatch,
+				Patch:  []byte(`{"op": "replace"}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+				return mockRepo
+			},
+			wantErr: usecase.InvalidArgumentError{},
+		},
+		{
+			name: "stale if-match",
+			param: usecase.PatchTaskParams{
+				ID:              1,
+				Format:          usecase.PatchFormatMergePatch,
+				Patch:           []byte(`{"status": 1}`),
+				ExpectedVersion: 2,
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil)
+
+				return mockRepo
+			},
+			wantErr: usecase.PreconditionFailedError{},
+		},
+		{
+			name: "not found",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"status": 1}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(nil, repository.ErrDataNotFound)
+
+				return mockRepo
+			},
+			wantErr: usecase.NotFoundError{},
+		},
+		{
+			name: "concurrent write re-applies the patch",
+			param: usecase.PatchTaskParams{
+				ID:     1,
+				Format: usecase.PatchFormatMergePatch,
+				Patch:  []byte(`{"status": 1}`),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				renamed := &entities.Task{ID: 1, Name: "renamed", Version: 4, CreatedAt: now, UpdatedAt: now}
+				gomock.InOrder(
+					mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(current(), nil).Times(2),
+					mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(nil, repository.ErrVersionConflict),
+					mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(renamed, nil).Times(2),
+					mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{ID: 1, Name: "renamed", Status: task.TaskStatusCompleted, Version: 4}).
+						Return(&entities.Task{ID: 1, Name: "renamed", Status: task.TaskStatusCompleted, Version: 5}, nil),
+				)
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "renamed", Status: task.TaskStatusCompleted, Version: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			uc := NewTaskUseCaseImpl(tt.mockRepo(ctrl), nopHistory(ctrl), noLists(ctrl))
+
+			got, err := uc.PatchTask(context.Background(), tt.param)
+			if tt.wantErr != nil {
+				assert.IsType(t, tt.wantErr, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
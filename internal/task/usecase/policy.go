@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/auth"
+	"slices"
+)
+
+// PolicyAction is an action on tasks that the policy decides on.
+type PolicyAction string
+
+const (
+	PolicyActionCreate PolicyAction = "create"
+	PolicyActionUpdate PolicyAction = "update"
+	PolicyActionDelete PolicyAction = "delete"
+	PolicyActionList   PolicyAction = "list"
+)
+
+func (a PolicyAction) Valid() bool {
+	return a == PolicyActionCreate || a == PolicyActionUpdate || a == PolicyActionDelete || a == PolicyActionList
+}
+
+// The roles of the default policy. RoleAdmin is the one role that ownership rules never restrict.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// PolicyDefinition is a policy by names: the actions each role may take, the actions a principal may only take
+// on the tasks it created, and the role of principals that carry none.
+type PolicyDefinition struct {
+	Roles       map[string][]string
+	OwnerOnly   []string
+	DefaultRole string
+}
+
+// DefaultPolicyDefinition is the policy used unless another is configured. Viewers only list tasks, editors
+// also change them but only delete their own, and admins do everything. Principals without roles, such as
+// API keys issued without any or tokens lacking the roles claim, are viewers: the policy fails closed.
+var DefaultPolicyDefinition = PolicyDefinition{
+	Roles: map[string][]string{
+		RoleViewer: {"list"},
+		RoleEditor: {"list", "create", "update", "delete"},
+		RoleAdmin:  {"list", "create", "update", "delete"},
+	},
+	OwnerOnly:   []string{"delete"},
+	DefaultRole: RoleViewer,
+}
+
+// Policy decides which principals may take which actions on tasks.
+type Policy struct {
+	roles       map[string][]PolicyAction
+	ownerOnly   []PolicyAction
+	defaultRole string
+}
+
+// NewPolicy checks a policy definition and builds the policy. Every action named must be a known one, only
+// actions on an existing task can be owner only, and the default role, if any, must be a role of the policy.
+func NewPolicy(def PolicyDefinition) (*Policy, error) {
+	parse := func(name string) (PolicyAction, error) {
+		action := PolicyAction(name)
+		if !action.Valid() {
+			return "", fmt.Errorf("unknown action %q", name)
+		}
+
+		return action, nil
+	}
+
+	policy := &Policy{roles: make(map[string][]PolicyAction, len(def.Roles)), defaultRole: def.DefaultRole}
+
+	for role, names := range def.Roles {
+		actions := make([]PolicyAction, 0, len(names))
+
+		for _, name := range names {
+			action, err := parse(name)
+			if err != nil {
+				return nil, fmt.Errorf("role %q: %w", role, err)
+			}
+
+			actions = append(actions, action)
+		}
+
+		policy.roles[role] = actions
+	}
+
+	for _, name := range def.OwnerOnly {
+		action, err := parse(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if action == PolicyActionCreate || action == PolicyActionList {
+			return nil, fmt.Errorf("action %q is not on an existing task and cannot be owner only", name)
+		}
+
+		policy.ownerOnly = append(policy.ownerOnly, action)
+	}
+
+	if _, ok := policy.roles[def.DefaultRole]; def.DefaultRole != "" && !ok {
+		return nil, fmt.Errorf("default role %q is not a role of the policy", def.DefaultRole)
+	}
+
+	return policy, nil
+}
+
+// Allows reports whether one of the roles of principal grants action.
+func (p *Policy) Allows(principal auth.Principal, action PolicyAction) bool {
+	for _, role := range p.rolesOf(principal) {
+		if slices.Contains(p.roles[role], action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OwnerOnly reports whether principal may only take action on the tasks it created, which is never the case
+// of an admin.
+func (p *Policy) OwnerOnly(principal auth.Principal, action PolicyAction) bool {
+	return slices.Contains(p.ownerOnly, action) && !slices.Contains(p.rolesOf(principal), RoleAdmin)
+}
+
+func (p *Policy) rolesOf(principal auth.Principal) []string {
+	if len(principal.Roles) == 0 && p.defaultRole != "" {
+		return []string{p.defaultRole}
+	}
+
+	return principal.Roles
+}
+
+// authorize checks that the principal of ctx may take action. For an action on an existing task, load reads
+// the task, which only happens when its creator matters. Requests without a principal, such as the jobs of
+// the service, are not restricted.
+func (a *TaskUseCaseImpl) authorize(ctx context.Context, action PolicyAction, load func() (*entities.Task, error)) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if !a.policy.Allows(principal, action) {
+		return usecase.ForbiddenError{Action: string(action), Resource: "task", Reason: "no role of the principal grants it"}
+	}
+
+	if load == nil || !a.policy.OwnerOnly(principal, action) {
+		return nil
+	}
+
+	task, err := load()
+	if err != nil {
+		return err
+	}
+
+	// a task whose creator is unknown belongs to no one but the admins
+	if task.CreatedBy == "" || task.CreatedBy != principal.Subject {
+		return usecase.ForbiddenError{
+			Action:   string(action),
+			Resource: "task",
+			ID:       task.ID,
+			Reason:   fmt.Sprintf("only its creator or an admin can %s it", action),
+		}
+	}
+
+	return nil
+}
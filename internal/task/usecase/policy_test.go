@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"ggltask/pkg/actor"
+	"ggltask/pkg/auth"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		def     PolicyDefinition
+		wantErr bool
+	}{
+		{
+			name: "default",
+			def:  DefaultPolicyDefinition,
+		},
+		{
+			name: "no default role",
+			def:  PolicyDefinition{Roles: map[string][]string{"reader": {"list"}}},
+		},
+		{
+			name:    "unknown action",
+			def:     PolicyDefinition{Roles: map[string][]string{"reader": {"list", "purge"}}},
+			wantErr: true,
+		},
+		{
+			name:    "owner only action on no task",
+			def:     PolicyDefinition{Roles: map[string][]string{"writer": {"create"}}, OwnerOnly: []string{"create"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown owner only action",
+			def:     PolicyDefinition{Roles: map[string][]string{"writer": {"create"}}, OwnerOnly: []string{"archive"}},
+			wantErr: true,
+		},
+		{
+			name:    "default role outside the policy",
+			def:     PolicyDefinition{Roles: map[string][]string{"reader": {"list"}}, DefaultRole: "admin"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewPolicy(tt.def)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPolicy_Allows(t *testing.T) {
+	t.Parallel()
+
+	policy, err := NewPolicy(DefaultPolicyDefinition)
+	assert.NoError(t, err)
+
+	viewer := auth.Principal{Subject: "bob", Roles: []string{RoleViewer}}
+	editor := auth.Principal{Subject: "alice", Roles: []string{RoleEditor}}
+	admin := auth.Principal{Subject: "carol", Roles: []string{RoleViewer, RoleAdmin}}
+	apiKey := auth.Principal{Subject: "ci"}
+
+	assert.True(t, policy.Allows(viewer, PolicyActionList))
+	assert.False(t, policy.Allows(viewer, PolicyActionCreate))
+	assert.False(t, policy.Allows(viewer, PolicyActionDelete))
+	assert.True(t, policy.Allows(editor, PolicyActionDelete))
+	assert.True(t, policy.OwnerOnly(editor, PolicyActionDelete))
+	assert.False(t, policy.OwnerOnly(editor, PolicyActionUpdate))
+	assert.False(t, policy.OwnerOnly(admin, PolicyActionDelete))
+	// principals without roles take the default one, the lowest
+	assert.True(t, policy.Allows(apiKey, PolicyActionList))
+	assert.False(t, policy.Allows(apiKey, PolicyActionUpdate))
+	assert.False(t, policy.Allows(apiKey, PolicyActionDelete))
+	assert.False(t, policy.Allows(auth.Principal{Roles: []string{"auditor"}}, PolicyActionList))
+
+	strict, err := NewPolicy(PolicyDefinition{Roles: map[string][]string{RoleViewer: {"list"}}})
+	assert.NoError(t, err)
+	assert.False(t, strict.Allows(apiKey, PolicyActionList))
+}
+
+func TestTaskUseCaseImpl_Policy(t *testing.T) {
+	t.Parallel()
+
+	as := func(subject string, roles ...string) context.Context {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject, Roles: roles})
+
+		return actor.WithActor(ctx, subject)
+	}
+
+	owned := &entities.Task{ID: 1, Name: "alice's task", CreatedBy: "alice"}
+	unowned := &entities.Task{ID: 2, Name: "legacy task"}
+
+	t.Run("viewer cannot create", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		uc := NewTaskUseCaseImpl(repositorymock.NewMockRepository(ctrl), nopHistory(ctrl), noLists(ctrl))
+
+		_, err := uc.CreateTask(as("bob", RoleViewer), usecase.CreateTaskParams{Name: "task"})
+		assert.Equal(t, usecase.ForbiddenError{Action: "create", Resource: "task", Reason: "no role of the principal grants it"}, err)
+		assert.Equal(t, http.StatusForbidden, err.(usecase.UseCaseError).HTTPStatusCode())
+		assert.Equal(t, "not allowed to create task: no role of the principal grants it", err.Error())
+	})
+
+	t.Run("viewer cannot update", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		withTasks(mockRepo, owned)
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+		_, err := uc.UpdateTask(as("bob", RoleViewer), usecase.UpdateTaskParams{ID: 1, Name: ptr("renamed")})
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+	})
+
+	t.Run("unknown role cannot read", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		uc := NewTaskUseCaseImpl(repositorymock.NewMockRepository(ctrl), nopHistory(ctrl), noLists(ctrl))
+		ctx := as("eve", "auditor")
+		page := usecase.ListTasksParams{PageIndex: 1, PageSize: 10}
+
+		_, err := uc.ListTasks(ctx, page)
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+		_, err = uc.GetTask(ctx, 1)
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+		_, err = uc.ListTrash(ctx, page)
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+		_, err = uc.SearchTasks(ctx, usecase.SearchTasksParams{Query: "milk", PageIndex: 1, PageSize: 10})
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+		_, err = uc.ListTaskHistory(ctx, usecase.ListTaskHistoryParams{TaskID: 1, PageIndex: 1, PageSize: 10})
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+		_, err = uc.ListSubtasks(ctx, 1)
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+		_, err = uc.ListOccurrences(ctx, usecase.ListOccurrencesParams{ID: 1, Count: 1})
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+		_, err = uc.ListLists(ctx, usecase.ListListsParams{PageIndex: 1, PageSize: 10})
+		assert.ErrorAs(t, err, &usecase.ForbiddenError{})
+	})
+
+	t.Run("viewer cannot purge or restore", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		uc := NewTaskUseCaseImpl(repositorymock.NewMockRepository(ctrl), nopHistory(ctrl), noLists(ctrl))
+
+		err := uc.PurgeTask(as("bob", RoleViewer), 1)
+		assert.Equal(t, usecase.ForbiddenError{Action: "delete", Resource: "task", Reason: "no role of the principal grants it"}, err)
+
+		_, err = uc.RestoreTask(as("bob", RoleViewer), 1)
+		assert.Equal(t, usecase.ForbiddenError{Action: "update", Resource: "task", Reason: "no role of the principal grants it"}, err)
+	})
+
+	t.Run("editor purges only its own trashed tasks", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		mockRepo.EXPECT().GetDeletedTaskByID(gomock.Any(), uint(1)).Return(owned, nil).Times(2)
+		mockRepo.EXPECT().GetDeletedTaskByID(gomock.Any(), uint(9)).Return(nil, repository.ErrDataNotFound)
+		mockRepo.EXPECT().PurgeTask(gomock.Any(), uint(1)).Return(nil)
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+		err := uc.PurgeTask(as("dave", RoleEditor), 1)
+		assert.Equal(t, usecase.ForbiddenError{
+			Action: "delete", Resource: "task", ID: uint(1), Reason: "only its creator or an admin can delete it",
+		}, err)
+
+		assert.Equal(t, usecase.NotFoundError{Resource: "trashed task", ID: uint(9)}, uc.PurgeTask(as("dave", RoleEditor), 9))
+		assert.NoError(t, uc.PurgeTask(as("alice", RoleEditor), 1))
+	})
+
+	t.Run("editor creates tasks of its own", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+				saved.ID = 3
+
+				return saved, nil
+			})
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+		got, err := uc.CreateTask(as("alice", RoleEditor), usecase.CreateTaskParams{Name: "task"})
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", got.CreatedBy)
+	})
+
+	t.Run("editor deletes its own task", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		withTasks(mockRepo, owned)
+		noSubtasks(mockRepo)
+		mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil)
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+		assert.NoError(t, uc.DeleteTask(as("alice", RoleEditor), 1))
+	})
+
+	t.Run("editor cannot delete the task of another", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		withTasks(mockRepo, owned, unowned)
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+		for _, id := range []uint{1, 2} {
+			err := uc.DeleteTask(as("dave", RoleEditor), id)
+			assert.Equal(t, usecase.ForbiddenError{
+				Action: "delete", Resource: "task", ID: id, Reason: "only its creator or an admin can delete it",
+			}, err)
+		}
+
+		err := uc.DeleteTask(as("dave", RoleEditor), 9)
+		assert.Equal(t, usecase.NotFoundError{Resource: "task", ID: uint(9)}, err)
+	})
+
+	t.Run("editor cannot delete the subtasks of another", func(t *testing.T) {
+		t.Parallel()
+
+		parentID := owned.ID
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		withTasks(mockRepo, owned, &entities.Task{ID: 3, Name: "bob's subtask", ParentID: &parentID, CreatedBy: "bob"})
+		mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+				return fn(ctx, mockRepo)
+			})
+		mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(1)).Return([]*entities.Task{{ID: 3, ParentID: &parentID, CreatedBy: "bob"}}, nil).Times(2)
+		mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(3)).Return([]*entities.Task{}, nil).AnyTimes()
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+		err := uc.DeleteTask(as("alice", RoleEditor), 1)
+		assert.Equal(t, usecase.ForbiddenError{
+			Action: "delete", Resource: "task", ID: uint(3), Reason: "only its creator or an admin can delete it",
+		}, err)
+	})
+
+	t.Run("editor cannot cascade the tasks of another out of a list", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		withTasks(mockRepo, &entities.Task{ID: 3, Name: "bob's task", ListID: 2, CreatedBy: "bob"})
+		mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+				return fn(ctx, mockRepo)
+			})
+		mockRepo.EXPECT().ListTasksByPage(gomock.Any(), gomock.Any()).Return([]*entities.Task{{ID: 3, ListID: 2, CreatedBy: "bob"}}, 1, nil)
+		mockLists := repositorymock.NewMockListRepository(ctrl)
+		mockLists.EXPECT().GetListByID(gomock.Any(), uint(2)).Return(&entities.List{ID: 2}, nil)
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), mockLists)
+
+		err := uc.DeleteList(as("alice", RoleEditor), usecase.DeleteListParams{ID: 2, Tasks: usecase.ListTasksPolicyCascade})
+		assert.Equal(t, usecase.ForbiddenError{
+			Action: "delete", Resource: "task", ID: uint(3), Reason: "only its creator or an admin can delete it",
+		}, err)
+	})
+
+	t.Run("editor can update the task of another", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		withTasks(mockRepo, owned)
+		noSubtasks(mockRepo)
+		mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+				return saved, nil
+			})
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+		got, err := uc.UpdateTask(as("dave", RoleEditor), usecase.UpdateTaskParams{ID: 1, Name: ptr("renamed")})
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", got.CreatedBy)
+	})
+
+	t.Run("admin deletes any task without reading it", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		noSubtasks(mockRepo)
+		mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(2)).Return(nil)
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+		assert.NoError(t, uc.DeleteTask(as("carol", RoleAdmin), 2))
+	})
+
+	t.Run("configured owner only update", func(t *testing.T) {
+		t.Parallel()
+
+		policy, err := NewPolicy(PolicyDefinition{
+			Roles:     map[string][]string{RoleEditor: {"update"}},
+			OwnerOnly: []string{"update"},
+		})
+		assert.NoError(t, err)
+
+		ctrl := gomock.NewController(t)
+		mockRepo := repositorymock.NewMockRepository(ctrl)
+		withTasks(mockRepo, owned)
+		uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl), WithPolicy(policy))
+
+		_, err = uc.UpdateTask(as("dave", RoleEditor), usecase.UpdateTaskParams{ID: 1, Name: ptr("renamed")})
+		assert.Equal(t, usecase.ForbiddenError{
+			Action: "update", Resource: "task", ID: uint(1), Reason: "only its creator or an admin can update it",
+		}, err)
+	})
+}
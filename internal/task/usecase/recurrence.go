@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/rrule"
+	"time"
+)
+
+// maxOccurrences bounds the occurrences listed at once.
+const maxOccurrences = 100
+
+// ListOccurrences is responsible for previewing the occurrences of a recurring task that follow its due date.
+// A task that does not recur has none.
+func (a *TaskUseCaseImpl) ListOccurrences(ctx context.Context, param usecase.ListOccurrencesParams) ([]time.Time, error) {
+	if param.Count < 1 || param.Count > maxOccurrences {
+		return nil, usecase.InvalidArgumentError{Argument: "count", Reason: fmt.Sprintf("must be 1 to %d", maxOccurrences)}
+	}
+
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	current, err := a.getTask(ctx, param.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Recurrence == nil || current.DueAt == nil {
+		return []time.Time{}, nil
+	}
+
+	rule, loc, err := parseRecurrence(current.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule.After(current.DueAt.In(loc), *current.DueAt, param.Count), nil
+}
+
+// normalizeRecurrence checks a recurrence and returns it with its rule in canonical form,
+// its time zone defaulting to UTC.
+func normalizeRecurrence(recurrence *entities.Recurrence) (*entities.Recurrence, error) {
+	if recurrence == nil {
+		return nil, nil
+	}
+
+	rule, err := rrule.Parse(recurrence.Rule)
+	if err != nil {
+		return nil, usecase.InvalidArgumentError{Argument: "recurrence.rule", Reason: err.Error()}
+	}
+
+	timeZone := recurrence.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+
+	// Local is the zone of the server, which is no zone a client can rely on
+	if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "Local" {
+		return nil, usecase.InvalidArgumentError{Argument: "recurrence.time_zone", Reason: fmt.Sprintf("unknown time zone %q", timeZone)}
+	}
+
+	return &entities.Recurrence{Rule: rule.String(), TimeZone: timeZone}, nil
+}
+
+// parseRecurrence parses a stored recurrence, which was normalized when it was set.
+func parseRecurrence(recurrence *entities.Recurrence) (*rrule.Rule, *time.Location, error) {
+	rule, err := rrule.Parse(recurrence.Rule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rrule.Parse error: %w", err)
+	}
+
+	loc, err := time.LoadLocation(recurrence.TimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("time.LoadLocation error: %w", err)
+	}
+
+	return rule, loc, nil
+}
+
+// checkRecurrence requires a recurring task to have a due date, the occurrence its series continues from.
+func checkRecurrence(t *entities.Task) error {
+	if t.Recurrence != nil && t.DueAt == nil {
+		return usecase.InvalidArgumentError{Argument: "due_at", Reason: "is required for a recurring task"}
+	}
+
+	return nil
+}
+
+// nextOccurrence returns the next occurrence of a recurring task being completed, to create along with
+// the completion, or nil. The recurrence passes from the completed task to the next occurrence, so that
+// completing the task again does not repeat it twice; the last occurrence of a series just stops recurring.
+func nextOccurrence(before, after *entities.Task) (*entities.Task, error) {
+	if after.Recurrence == nil || after.Status != task.TaskStatusCompleted || before.Status == task.TaskStatusCompleted {
+		return nil, nil
+	}
+
+	rule, loc, err := parseRecurrence(after.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	timeZone := after.Recurrence.TimeZone
+	after.Recurrence = nil
+
+	due := after.DueAt.In(loc)
+
+	occurrences := rule.After(due, due, 1)
+	if len(occurrences) == 0 {
+		return nil, nil
+	}
+
+	// the series of the next occurrence starts with it, one occurrence shorter
+	if rule.Count > 0 {
+		rule.Count--
+	}
+
+	return &entities.Task{
+		Name:        after.Name,
+		Description: after.Description,
+		Priority:    after.Priority,
+		DueAt:       &occurrences[0],
+		Tags:        after.Tags,
+		ParentID:    after.ParentID,
+		ListID:      after.ListID,
+		Recurrence:  &entities.Recurrence{Rule: rule.String(), TimeZone: timeZone},
+		CreatedBy:   after.CreatedBy,
+	}, nil
+}
+
+// createOccurrence stores the next occurrence of a completed recurring task, in the initial status of the workflow.
+func (a *TaskUseCaseImpl) createOccurrence(ctx context.Context, next *entities.Task) error {
+	next.Status = a.workflow.Initial.Code
+
+	created, err := a.taskRepo.CreateTask(ctx, next)
+	if err != nil {
+		return fmt.Errorf("repo.CreateTask error: %w", err)
+	}
+
+	a.recordHistory(ctx, created.ID, entities.HistoryActionCreate, diffTask(nil, created))
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskUseCaseImpl_CreateTask_Recurrence(t *testing.T) {
+	t.Parallel()
+
+	due := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		param   usecase.CreateTaskParams
+		want    *entities.Recurrence
+		wantErr error
+	}{
+		{
+			name: "normalized",
+			param: usecase.CreateTaskParams{
+				Name: "standup", DueAt: &due, Recurrence: &entities.Recurrence{Rule: "freq=weekly;byday=we,mo"},
+			},
+			want: &entities.Recurrence{Rule: "FREQ=WEEKLY;BYDAY=MO,WE", TimeZone: "UTC"},
+		},
+		{
+			name: "invalid rule",
+			param: usecase.CreateTaskParams{
+				Name: "standup", DueAt: &due, Recurrence: &entities.Recurrence{Rule: "FREQ=HOURLY"},
+			},
+			wantErr: usecase.InvalidArgumentError{Argument: "recurrence.rule", Reason: "unsupported FREQ HOURLY: invalid recurrence rule"},
+		},
+		{
+			name: "unknown time zone",
+			param: usecase.CreateTaskParams{
+				Name: "standup", DueAt: &due, Recurrence: &entities.Recurrence{Rule: "FREQ=DAILY", TimeZone: "Mars/Olympus"},
+			},
+			wantErr: usecase.InvalidArgumentError{Argument: "recurrence.time_zone", Reason: `unknown time zone "Mars/Olympus"`},
+		},
+		{
+			name:    "without a due date",
+			param:   usecase.CreateTaskParams{Name: "standup", Recurrence: &entities.Recurrence{Rule: "FREQ=DAILY"}},
+			wantErr: usecase.InvalidArgumentError{Argument: "due_at", Reason: "is required for a recurring task"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+			if tt.wantErr == nil {
+				mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, created *entities.Task) (*entities.Task, error) {
+						assert.Equal(t, tt.want, created.Recurrence)
+
+						return created, nil
+					})
+			}
+
+			_, err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl)).CreateTask(context.Background(), tt.param)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_UpdateTask_CompletesOccurrence(t *testing.T) {
+	t.Parallel()
+
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+
+	completed := task.TaskStatusCompleted.Ref()
+	// Monday
+	due := time.Date(2024, 1, 15, 9, 0, 0, 0, taipei)
+
+	tests := []struct {
+		name     string
+		rule     string
+		listID   uint
+		wantRule string
+		wantDue  time.Time
+	}{
+		{
+			name:     "creates the next occurrence",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3",
+			wantRule: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=2",
+			wantDue:  time.Date(2024, 1, 17, 9, 0, 0, 0, taipei),
+		},
+		{
+			name:     "creates the next occurrence in the list of the task",
+			rule:     "FREQ=DAILY",
+			listID:   2,
+			wantRule: "FREQ=DAILY",
+			wantDue:  time.Date(2024, 1, 16, 9, 0, 0, 0, taipei),
+		},
+		{
+			name: "last occurrence",
+			rule: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+			noSubtasks(mockRepo)
+			withTasks(mockRepo, &entities.Task{
+				ID: 1, Name: "standup", Priority: task.PriorityHigh, DueAt: &due, Tags: []string{"ops"},
+				Recurrence: &entities.Recurrence{Rule: tt.rule, TimeZone: "Asia/Taipei"}, ListID: tt.listID, Version: 2,
+			})
+			mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+					assert.Equal(t, task.TaskStatusCompleted, saved.Status)
+					assert.Nil(t, saved.Recurrence)
+
+					return saved, nil
+				})
+
+			if tt.wantRule != "" {
+				mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+						return fn(ctx, mockRepo)
+					})
+				mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, next *entities.Task) (*entities.Task, error) {
+						assert.Equal(t, "standup", next.Name)
+						assert.Equal(t, task.TaskStatusIncomplete, next.Status)
+						assert.Equal(t, task.PriorityHigh, next.Priority)
+						assert.Equal(t, []string{"ops"}, next.Tags)
+						assert.True(t, tt.wantDue.Equal(*next.DueAt), "next occurrence due %v", next.DueAt)
+						assert.Equal(t, tt.listID, next.ListID)
+						assert.Equal(t, &entities.Recurrence{Rule: tt.wantRule, TimeZone: "Asia/Taipei"}, next.Recurrence)
+
+						next.ID = 2
+
+						return next, nil
+					})
+			}
+
+			got, err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl)).UpdateTask(context.Background(), usecase.UpdateTaskParams{
+				ID:     1,
+				Status: &completed,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, uint(1), got.ID)
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_ListOccurrences(t *testing.T) {
+	t.Parallel()
+
+	due := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	withTasks(mockRepo,
+		&entities.Task{ID: 1, DueAt: &due, Recurrence: &entities.Recurrence{Rule: "FREQ=MONTHLY;BYMONTHDAY=-1", TimeZone: "UTC"}},
+		&entities.Task{ID: 2, DueAt: &due},
+	)
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+	got, err := uc.ListOccurrences(context.Background(), usecase.ListOccurrencesParams{ID: 1, Count: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+	}, got)
+
+	got, err = uc.ListOccurrences(context.Background(), usecase.ListOccurrencesParams{ID: 2, Count: 2})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = uc.ListOccurrences(context.Background(), usecase.ListOccurrencesParams{ID: 9, Count: 2})
+	assert.Equal(t, usecase.NotFoundError{Resource: "task", ID: uint(9)}, err)
+
+	_, err = uc.ListOccurrences(context.Background(), usecase.ListOccurrencesParams{ID: 1, Count: 0})
+	assert.Equal(t, usecase.InvalidArgumentError{Argument: "count", Reason: "must be 1 to 100"}, err)
+}
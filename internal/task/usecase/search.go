@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/fulltext"
+)
+
+// SearchTasks is responsible for searching the tasks by name, most relevant first,
+// with the matched words of each name highlighted.
+func (a *TaskUseCaseImpl) SearchTasks(ctx context.Context, param usecase.SearchTasksParams) (*usecase.SearchTasksResult, error) {
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	query := fulltext.ParseQuery(param.Query)
+	if len(query.Terms) == 0 {
+		return nil, usecase.InvalidArgumentError{Argument: "q", Reason: "query has no word to search for"}
+	}
+
+	hits, total, err := a.taskRepo.SearchTasks(ctx, repository.TaskSearchQuery{
+		Text:      param.Query,
+		PageIndex: param.PageIndex,
+		PageSize:  param.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.SearchTasks error: %w", err)
+	}
+
+	for _, hit := range hits {
+		hit.Highlight = fulltext.Highlight(hit.Task.Name, query)
+	}
+
+	return &usecase.SearchTasksResult{
+		Hits:  hits,
+		Total: total,
+	}, nil
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskUseCaseImpl_SearchTasks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	mockRepo.EXPECT().SearchTasks(gomock.Any(), repository.TaskSearchQuery{Text: "Buy mi", PageIndex: 1, PageSize: 10}).
+		Return([]*entities.TaskSearchHit{{Task: &entities.Task{ID: 1, Name: "Buy <milk> & milkshake"}, Score: 1}}, 1, nil)
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+	got, err := uc.SearchTasks(context.Background(), usecase.SearchTasksParams{Query: "Buy mi", PageIndex: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got.Total)
+	assert.Equal(t, "<mark>Buy</mark> &lt;<mark>milk</mark>&gt; &amp; <mark>milkshake</mark>", got.Hits[0].Highlight)
+
+	_, err = uc.SearchTasks(context.Background(), usecase.SearchTasksParams{Query: " -- ", PageIndex: 1, PageSize: 10})
+	assert.ErrorAs(t, err, &usecase.InvalidArgumentError{})
+}
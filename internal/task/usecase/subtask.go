@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+)
+
+// ListSubtasks is responsible for listing the direct subtasks of a task, with the progress of all its subtasks.
+func (a *TaskUseCaseImpl) ListSubtasks(ctx context.Context, id uint) (*usecase.ListSubtasksResult, error) {
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	if _, err := a.getTask(ctx, id); err != nil {
+		return nil, err
+	}
+
+	children, err := a.taskRepo.ListChildTasks(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repo.ListChildTasks error: %w", err)
+	}
+
+	progress, err := a.progress(ctx, children)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usecase.ListSubtasksResult{
+		Subtasks: children,
+		Progress: progress,
+	}, nil
+}
+
+// progress counts the given subtasks and, at every depth, their own subtasks. A closed subtask counts as
+// completed: an archived one needs no more work either.
+func (a *TaskUseCaseImpl) progress(ctx context.Context, children []*entities.Task) (entities.TaskProgress, error) {
+	var progress entities.TaskProgress
+
+	// the hierarchy is a tree, but a visited set keeps a corrupted one from looping forever
+	visited := make(map[uint]bool)
+
+	for len(children) > 0 {
+		child := children[0]
+		children = children[1:]
+
+		if visited[child.ID] {
+			continue
+		}
+
+		visited[child.ID] = true
+
+		progress.Total++
+		if child.Status.Closed() {
+			progress.Completed++
+		}
+
+		grandchildren, err := a.taskRepo.ListChildTasks(ctx, child.ID)
+		if err != nil {
+			return entities.TaskProgress{}, fmt.Errorf("repo.ListChildTasks error: %w", err)
+		}
+
+		children = append(children, grandchildren...)
+	}
+
+	return progress, nil
+}
+
+// checkSubtaskRules checks an update against the hierarchy: a task moved under another one must not
+// end up under itself, and a task is only completed once all of its subtasks are closed, unless allowed.
+func (a *TaskUseCaseImpl) checkSubtaskRules(ctx context.Context, before, after *entities.Task) error {
+	if after.ParentID != nil && (before.ParentID == nil || *before.ParentID != *after.ParentID) {
+		if _, err := a.checkParent(ctx, after.ID, *after.ParentID); err != nil {
+			return err
+		}
+	}
+
+	if a.allowIncompleteSubtasks || after.Status != task.TaskStatusCompleted || before.Status == task.TaskStatusCompleted {
+		return nil
+	}
+
+	children, err := a.taskRepo.ListChildTasks(ctx, after.ID)
+	if err != nil {
+		return fmt.Errorf("repo.ListChildTasks error: %w", err)
+	}
+
+	progress, err := a.progress(ctx, children)
+	if err != nil {
+		return err
+	}
+
+	if progress.Completed < progress.Total {
+		return usecase.ConflictError{
+			Resource: "task",
+			ID:       after.ID,
+			Reason:   fmt.Sprintf("%d of %d subtasks are incomplete", progress.Total-progress.Completed, progress.Total),
+		}
+	}
+
+	return nil
+}
+
+// checkParent checks that the task id can be a subtask of parentID, and returns the parent: it must be live,
+// and must be neither the task itself nor one of its subtasks. A new task, not stored yet, has id zero.
+func (a *TaskUseCaseImpl) checkParent(ctx context.Context, id, parentID uint) (*entities.Task, error) {
+	var parent *entities.Task
+
+	visited := make(map[uint]bool)
+
+	for ancestorID := &parentID; ancestorID != nil; {
+		if *ancestorID == id {
+			return nil, usecase.ConflictError{
+				Resource: "task",
+				ID:       id,
+				Reason:   "cannot be a subtask of itself or of one of its subtasks",
+			}
+		}
+
+		if visited[*ancestorID] {
+			return parent, nil
+		}
+
+		visited[*ancestorID] = true
+
+		ancestor, err := a.taskRepo.GetTaskByID(ctx, *ancestorID)
+		if err != nil {
+			if !errors.Is(err, repository.ErrDataNotFound) {
+				return nil, fmt.Errorf("repo.GetTaskByID error: %w", err)
+			}
+
+			if *ancestorID == parentID {
+				return nil, usecase.InvalidArgumentError{Argument: "parent_id", Reason: fmt.Sprintf("task %d not found", parentID)}
+			}
+
+			// a trashed ancestor ends the live part of the hierarchy
+			return parent, nil
+		}
+
+		if parent == nil {
+			parent = ancestor
+		}
+
+		ancestorID = ancestor.ParentID
+	}
+
+	return parent, nil
+}
+
+// deleteTaskTree moves a task to the trash, and its subtasks along with it or over to its parent, by the policy.
+// Every task trashed must be one the principal may delete, so a subtask of another fails the whole tree.
+func (a *TaskUseCaseImpl) deleteTaskTree(ctx context.Context, id uint) error {
+	deleted, err := a.getTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := a.authorize(ctx, PolicyActionDelete, func() (*entities.Task, error) { return deleted, nil }); err != nil {
+		return err
+	}
+
+	children, err := a.taskRepo.ListChildTasks(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repo.ListChildTasks error: %w", err)
+	}
+
+	for _, child := range children {
+		if a.subtaskDeletePolicy == SubtaskDeletePolicyReparent {
+			newParentID := deleted.ParentID
+			if _, err := a.UpdateTask(ctx, usecase.UpdateTaskParams{ID: child.ID, ParentID: &newParentID}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := a.deleteTaskTree(ctx, child.ID); err != nil {
+			return err
+		}
+	}
+
+	return a.deleteTask(ctx, id)
+}
+
+// withinTransaction runs fn with a use case working in a repository transaction. The history of the
+// transaction is only recorded once it commits.
+func (a *TaskUseCaseImpl) withinTransaction(ctx context.Context, fn func(ctx context.Context, txUseCase *TaskUseCaseImpl) error) error {
+	history := &bufferedHistory{}
+
+	err := a.taskRepo.WithinTransaction(ctx, func(ctx context.Context, tx repository.Repository) error {
+		txUseCase := a.withRepositories(tx, history)
+		txUseCase.inTransaction = true
+
+		return fn(ctx, txUseCase)
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for _, entry := range history.entries {
+		a.appendHistory(ctx, entry)
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/repository"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskUseCaseImpl_ListSubtasks(t *testing.T) {
+	t.Parallel()
+
+	parentID, childID := uint(1), uint(3)
+	children := []*entities.Task{
+		{ID: 2, Name: "done", Status: task.TaskStatusCompleted, ParentID: &parentID},
+		{ID: 3, Name: "pending", Status: task.TaskStatusIncomplete, ParentID: &parentID},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1}, nil)
+	mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(1)).Return(children, nil)
+	mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(2)).Return([]*entities.Task{
+		{ID: 5, Name: "archived", Status: task.TaskStatusArchived, ParentID: &children[0].ID},
+	}, nil)
+	mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(5)).Return([]*entities.Task{}, nil)
+	mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(3)).Return([]*entities.Task{
+		{ID: 4, Name: "nested", Status: task.TaskStatusCompleted, ParentID: &childID},
+	}, nil)
+	mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(4)).Return([]*entities.Task{}, nil)
+	mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(9)).Return(nil, repository.ErrDataNotFound)
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl))
+
+	got, err := uc.ListSubtasks(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, children, got.Subtasks)
+	assert.Equal(t, entities.TaskProgress{Completed: 3, Total: 4}, got.Progress)
+
+	_, err = uc.ListSubtasks(context.Background(), 9)
+	assert.Equal(t, usecase.NotFoundError{Resource: "task", ID: uint(9)}, err)
+}
+
+func TestTaskUseCaseImpl_SubtaskRules(t *testing.T) {
+	t.Parallel()
+
+	completed := task.TaskStatusCompleted.Ref()
+	one, two, three, nine := uint(1), uint(2), uint(3), uint(9)
+	moveUnder := func(id uint) **uint {
+		p := &id
+
+		return &p
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		// parentID is the parent of the updated task before the update
+		parentID *uint
+		param    usecase.UpdateTaskParams
+		setup    func(mockRepo *repositorymock.MockRepository)
+		wantErr  error
+	}{
+		{
+			name:  "completing a task with incomplete subtasks",
+			param: usecase.UpdateTaskParams{ID: 1, Status: &completed},
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(1)).Return([]*entities.Task{
+					{ID: 2, Status: task.TaskStatusCompleted, ParentID: &one},
+					{ID: 3, Status: task.TaskStatusIncomplete, ParentID: &one},
+				}, nil)
+				mockRepo.EXPECT().ListChildTasks(gomock.Any(), gomock.Any()).Return([]*entities.Task{}, nil).Times(2)
+			},
+			wantErr: usecase.ConflictError{Resource: "task", ID: uint(1), Reason: "1 of 2 subtasks are incomplete"},
+		},
+		{
+			name:  "completing a task with archived subtasks",
+			param: usecase.UpdateTaskParams{ID: 1, Status: &completed},
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(1)).Return([]*entities.Task{
+					{ID: 2, Status: task.TaskStatusCompleted, ParentID: &one},
+					{ID: 3, Status: task.TaskStatusArchived, ParentID: &one},
+				}, nil)
+				mockRepo.EXPECT().ListChildTasks(gomock.Any(), gomock.Any()).Return([]*entities.Task{}, nil).Times(2)
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(&entities.Task{ID: 1, Status: task.TaskStatusCompleted}, nil)
+			},
+		},
+		{
+			name:  "completing a task with incomplete subtasks when allowed",
+			opts:  []Option{WithIncompleteSubtasksAllowed(true)},
+			param: usecase.UpdateTaskParams{ID: 1, Status: &completed},
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(&entities.Task{ID: 1, Status: task.TaskStatusCompleted}, nil)
+			},
+		},
+		{
+			name:  "moving a task under one of its subtasks",
+			param: usecase.UpdateTaskParams{ID: 1, ParentID: moveUnder(3)},
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(3)).Return(&entities.Task{ID: 3, ParentID: &two}, nil)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(2)).Return(&entities.Task{ID: 2, ParentID: &one}, nil)
+			},
+			wantErr: usecase.ConflictError{Resource: "task", ID: uint(1), Reason: "cannot be a subtask of itself or of one of its subtasks"},
+		},
+		{
+			name:    "moving a task under itself",
+			param:   usecase.UpdateTaskParams{ID: 1, ParentID: moveUnder(1)},
+			setup:   func(*repositorymock.MockRepository) {},
+			wantErr: usecase.ConflictError{Resource: "task", ID: uint(1), Reason: "cannot be a subtask of itself or of one of its subtasks"},
+		},
+		{
+			name:  "moving a task under a missing task",
+			param: usecase.UpdateTaskParams{ID: 1, ParentID: moveUnder(9)},
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(9)).Return(nil, repository.ErrDataNotFound)
+			},
+			wantErr: usecase.InvalidArgumentError{Argument: "parent_id", Reason: "task 9 not found"},
+		},
+		{
+			name:  "moving a task under another top-level task",
+			param: usecase.UpdateTaskParams{ID: 1, ParentID: moveUnder(nine)},
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(9)).Return(&entities.Task{ID: 9}, nil)
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{ID: 1, Name: "task", ParentID: &nine, Version: 1}).
+					Return(&entities.Task{ID: 1, Name: "task", ParentID: &nine, Version: 2}, nil)
+			},
+		},
+		{
+			name:     "keeping the parent does not check it again",
+			parentID: &three,
+			param:    usecase.UpdateTaskParams{ID: 1, ParentID: moveUnder(three)},
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(&entities.Task{ID: 1, ParentID: &three}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+
+			mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Name: "task", ParentID: tt.parentID, Version: 1}, nil)
+			tt.setup(mockRepo)
+
+			_, err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl), tt.opts...).UpdateTask(context.Background(), tt.param)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_CreateTask_MissingParent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(9)).Return(nil, repository.ErrDataNotFound)
+
+	parentID := uint(9)
+	_, err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl)).CreateTask(context.Background(), usecase.CreateTaskParams{
+		Name:     "subtask",
+		ParentID: &parentID,
+	})
+	assert.Equal(t, usecase.InvalidArgumentError{Argument: "parent_id", Reason: "task 9 not found"}, err)
+}
+
+func TestTaskUseCaseImpl_DeleteTask_Subtasks(t *testing.T) {
+	t.Parallel()
+
+	grandparentID, parentID, childID := uint(5), uint(1), uint(2)
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		setup func(mockRepo *repositorymock.MockRepository)
+	}{
+		{
+			name: "cascade trashes the subtasks at every depth",
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(2)).Return(&entities.Task{ID: 2, ParentID: &parentID}, nil)
+				mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(2)).Return([]*entities.Task{
+					{ID: 3, ParentID: &childID},
+				}, nil)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(3)).Return(&entities.Task{ID: 3}, nil)
+				mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(3)).Return([]*entities.Task{}, nil)
+
+				gomock.InOrder(
+					mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(3)).Return(nil),
+					mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(2)).Return(nil),
+					mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil),
+				)
+			},
+		},
+		{
+			name: "reparent hands the subtasks over to the grandparent",
+			opts: []Option{WithSubtaskDeletePolicy(SubtaskDeletePolicyReparent)},
+			setup: func(mockRepo *repositorymock.MockRepository) {
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(2)).Return(&entities.Task{ID: 2, Name: "child", ParentID: &parentID, Version: 1}, nil)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(5)).Return(&entities.Task{ID: 5}, nil)
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{ID: 2, Name: "child", ParentID: &grandparentID, Version: 1}).
+					Return(&entities.Task{ID: 2, Name: "child", ParentID: &grandparentID, Version: 2}, nil)
+				mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+
+			children := []*entities.Task{{ID: 2, ParentID: &parentID}}
+			mockRepo.EXPECT().ListChildTasks(gomock.Any(), uint(1)).Return(children, nil).Times(2)
+			mockRepo.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(context.Context, repository.Repository) error) error {
+					return fn(ctx, mockRepo)
+				})
+			mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, ParentID: &grandparentID}, nil)
+			tt.setup(mockRepo)
+
+			err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl), tt.opts...).DeleteTask(context.Background(), 1)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_RestoreTask_DetachesFromTrashedParent(t *testing.T) {
+	t.Parallel()
+
+	parentID := uint(1)
+
+	ctrl := gomock.NewController(t)
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	mockRepo.EXPECT().RestoreTask(gomock.Any(), uint(2)).Return(&entities.Task{ID: 2, Name: "child", ParentID: &parentID, Version: 2}, nil)
+	mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(nil, repository.ErrDataNotFound)
+	mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(2)).Return(&entities.Task{ID: 2, Name: "child", ParentID: &parentID, Version: 2}, nil)
+	mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{ID: 2, Name: "child", Version: 2}).
+		Return(&entities.Task{ID: 2, Name: "child", Version: 3}, nil)
+
+	restored, err := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl)).RestoreTask(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Nil(t, restored.ParentID)
+}
@@ -4,27 +4,192 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"ggltask/internal/task"
 	"ggltask/internal/task/domain/entities"
 	"ggltask/internal/task/domain/repository"
 	"ggltask/internal/task/domain/usecase"
+	"ggltask/pkg/actor"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 var _ usecase.TaskUseCase = (*TaskUseCaseImpl)(nil)
 
+// maxUpdateAttempts bounds the retries of an unconditional update that lost a race with another writer.
+const maxUpdateAttempts = 3
+
+// maxNameLength is the longest task name, in characters.
+const maxNameLength = 50
+
+// maxDescriptionLength is the longest task description, in characters.
+const maxDescriptionLength = 10000
+
+// maxTags bounds the tags of a task, and maxTagLength the characters of each tag.
+const (
+	maxTags      = 20
+	maxTagLength = 32
+)
+
+// SubtaskDeletePolicy decides what becomes of the subtasks of a deleted task.
+type SubtaskDeletePolicy string
+
+const (
+	// SubtaskDeletePolicyCascade moves the subtasks to the trash along with their parent, at every depth.
+	SubtaskDeletePolicyCascade SubtaskDeletePolicy = "cascade"
+	// SubtaskDeletePolicyReparent hands the subtasks over to the parent of the deleted task.
+	SubtaskDeletePolicyReparent SubtaskDeletePolicy = "reparent"
+)
+
+func (p SubtaskDeletePolicy) Valid() bool {
+	return p == SubtaskDeletePolicyCascade || p == SubtaskDeletePolicyReparent
+}
+
 type TaskUseCaseImpl struct {
-	taskRepo repository.Repository
+	taskRepo    repository.Repository
+	historyRepo repository.HistoryRepository
+	listRepo    repository.ListRepository
+
+	allowIncompleteSubtasks bool
+	subtaskDeletePolicy     SubtaskDeletePolicy
+	workflow                *entities.Workflow
+	policy                  *Policy
+	listLocks               *listLocks
+	dependencyLocks         *dependencyLocks
+
+	// inTransaction is set on the copy of the use case running a transaction
+	inTransaction bool
+}
+
+type Option func(*TaskUseCaseImpl)
+
+// WithIncompleteSubtasksAllowed lets a task be completed while some of its subtasks are not.
+func WithIncompleteSubtasksAllowed(allowed bool) Option {
+	return func(a *TaskUseCaseImpl) {
+		a.allowIncompleteSubtasks = allowed
+	}
+}
+
+// WithSubtaskDeletePolicy sets what becomes of the subtasks of a deleted task, cascade by default.
+func WithSubtaskDeletePolicy(policy SubtaskDeletePolicy) Option {
+	return func(a *TaskUseCaseImpl) {
+		if policy.Valid() {
+			a.subtaskDeletePolicy = policy
+		}
+	}
 }
 
-func NewTaskUseCaseImpl(taskRepo repository.Repository) *TaskUseCaseImpl {
-	return &TaskUseCaseImpl{taskRepo: taskRepo}
+// WithWorkflow sets the workflow of the task statuses, DefaultWorkflowDefinition by default.
+func WithWorkflow(workflow *entities.Workflow) Option {
+	return func(a *TaskUseCaseImpl) {
+		if workflow != nil {
+			a.workflow = workflow
+		}
+	}
+}
+
+// WithPolicy sets the policy deciding who may create, update, delete and list tasks, DefaultPolicyDefinition by default.
+func WithPolicy(policy *Policy) Option {
+	return func(a *TaskUseCaseImpl) {
+		if policy != nil {
+			a.policy = policy
+		}
+	}
+}
+
+func NewTaskUseCaseImpl(
+	taskRepo repository.Repository,
+	historyRepo repository.HistoryRepository,
+	listRepo repository.ListRepository,
+	opts ...Option,
+) *TaskUseCaseImpl {
+	// the default definitions are known to be valid
+	workflow, _ := NewWorkflow(DefaultWorkflowDefinition)
+	policy, _ := NewPolicy(DefaultPolicyDefinition)
+
+	a := &TaskUseCaseImpl{
+		taskRepo:            taskRepo,
+		historyRepo:         historyRepo,
+		listRepo:            listRepo,
+		subtaskDeletePolicy: SubtaskDeletePolicyCascade,
+		workflow:            workflow,
+		policy:              policy,
+		listLocks:           &listLocks{},
+		dependencyLocks:     &dependencyLocks{},
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// withRepositories returns a copy of the use case, with the same options, working on other repositories.
+// Lists are not part of task transactions, so the copy keeps the list repository.
+func (a *TaskUseCaseImpl) withRepositories(taskRepo repository.Repository, historyRepo repository.HistoryRepository) *TaskUseCaseImpl {
+	c := *a
+	c.taskRepo = taskRepo
+	c.historyRepo = historyRepo
+
+	return &c
 }
 
-// CreateTask is responsible for creating a new task.
+// CreateTask is responsible for creating a new task, in the initial status of the workflow, on behalf of the actor.
+// A subtask goes to the list of its parent unless a list is given.
 func (a *TaskUseCaseImpl) CreateTask(ctx context.Context, param usecase.CreateTaskParams) (*entities.Task, error) {
+	if err := a.authorize(ctx, PolicyActionCreate, nil); err != nil {
+		return nil, err
+	}
+
+	tags, err := validateCreate(param)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrence, err := normalizeRecurrence(param.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	listID := param.ListID
+	if param.ParentID != nil {
+		parent, err := a.checkParent(ctx, 0, *param.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if listID == 0 {
+			listID = parent.ListID
+		}
+	}
+
+	if listID == 0 {
+		listID = entities.DefaultListID
+	}
+
+	defer a.holdList(ctx, listID)()
+
+	if err := a.checkList(ctx, listID); err != nil {
+		return nil, err
+	}
+
 	entityTask := &entities.Task{
-		Name:   param.Name,
-		Status: task.TaskStatusIncomplete,
+		Name:        param.Name,
+		ListID:      listID,
+		Description: param.Description,
+		Status:      a.workflow.Initial.Code,
+		Priority:    param.Priority,
+		DueAt:       param.DueAt,
+		Tags:        tags,
+		ParentID:    param.ParentID,
+		Recurrence:  recurrence,
+		CreatedBy:   actor.FromContext(ctx),
+	}
+
+	if err := checkRecurrence(entityTask); err != nil {
+		return nil, err
 	}
 
 	newTask, err := a.taskRepo.CreateTask(ctx, entityTask)
@@ -32,47 +197,447 @@ func (a *TaskUseCaseImpl) CreateTask(ctx context.Context, param usecase.CreateTa
 		return nil, fmt.Errorf("repo.CreateTask error: %w", err)
 	}
 
+	a.recordHistory(ctx, newTask.ID, entities.HistoryActionCreate, diffTask(nil, newTask))
+
 	return newTask, nil
 }
 
-// ListTasks is responsible for listing tasks by page.
+// GetTask is responsible for getting a task by id.
+func (a *TaskUseCaseImpl) GetTask(ctx context.Context, id uint) (*entities.Task, error) {
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	return a.getTask(ctx, id)
+}
+
+// getTask gets a live task by id, whatever the principal of ctx may read.
+func (a *TaskUseCaseImpl) getTask(ctx context.Context, id uint) (*entities.Task, error) {
+	taskEntity, err := a.taskRepo.GetTaskByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return nil, usecase.NotFoundError{
+				Resource: "task",
+				ID:       id,
+			}
+		}
+
+		return nil, fmt.Errorf("repo.GetTaskByID error: %w", err)
+	}
+
+	return taskEntity, nil
+}
+
+// ListTasks is responsible for listing the tasks matching the filter, by page or from a cursor.
+// Both modes return cursors for the adjacent pages, so a client can switch to cursors after the first page.
 func (a *TaskUseCaseImpl) ListTasks(ctx context.Context, param usecase.ListTasksParams) (*usecase.ListTasksResult, error) {
-	tasks, total, err := a.taskRepo.ListTasksByPage(ctx, param.PageIndex, param.PageSize)
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	if param.Status != nil {
+		status, ok := a.workflow.Resolve(*param.Status)
+		if !ok {
+			return nil, usecase.InvalidArgumentError{
+				Argument: "status",
+				Reason:   fmt.Sprintf("%s is not a status of the workflow", *param.Status),
+			}
+		}
+
+		param.Filter.Status = &status.Code
+	}
+
+	// an unknown list is not found rather than empty
+	if id := param.Filter.ListID; !isDefaultList(id) {
+		if _, err := a.GetList(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if param.Cursor != "" {
+		return a.listTasksByCursor(ctx, param)
+	}
+
+	tasks, total, err := a.taskRepo.ListTasksByPage(ctx, repository.TaskQuery{
+		Filter:    param.Filter,
+		Sort:      param.Sort,
+		PageIndex: param.PageIndex,
+		PageSize:  param.PageSize,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("repo.ListTasksByPage error: %w", err)
 	}
 
-	return &usecase.ListTasksResult{
+	result := &usecase.ListTasksResult{
 		Tasks: tasks,
 		Total: total,
-	}, nil
+	}
+
+	if len(tasks) > 0 {
+		if (param.PageIndex-1)*param.PageSize+len(tasks) < total {
+			result.NextCursor = encodeCursor(tasks[len(tasks)-1], param.Sort, false)
+		}
+
+		if param.PageIndex > 1 {
+			result.PrevCursor = encodeCursor(tasks[0], param.Sort, true)
+		}
+	}
+
+	return result, nil
+}
+
+func (a *TaskUseCaseImpl) listTasksByCursor(ctx context.Context, param usecase.ListTasksParams) (*usecase.ListTasksResult, error) {
+	cursor, backward, err := decodeCursor(param.Cursor, param.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, total, hasMore, err := a.taskRepo.ListTasksByCursor(ctx, repository.TaskCursorQuery{
+		Filter:   param.Filter,
+		Sort:     param.Sort,
+		Cursor:   cursor,
+		Backward: backward,
+		Limit:    param.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.ListTasksByCursor error: %w", err)
+	}
+
+	result := &usecase.ListTasksResult{
+		Tasks: tasks,
+		Total: total,
+	}
+
+	if len(tasks) == 0 {
+		return result, nil
+	}
+
+	// the cursor itself proves a page exists on the side the client came from
+	if hasMore || backward {
+		result.NextCursor = encodeCursor(tasks[len(tasks)-1], param.Sort, false)
+	}
+
+	if hasMore || !backward {
+		result.PrevCursor = encodeCursor(tasks[0], param.Sort, true)
+	}
+
+	return result, nil
 }
 
-// UpdateTask is responsible for updating a task.
+// UpdateTask is responsible for updating the fields of a task set in the params.
+// The stored task is read first so the change can be recorded; the update is conditional on the
+// version read, and an unconditional update that loses a race is retried against the new state.
+// Completing a recurring task creates its next occurrence in the same transaction.
 func (a *TaskUseCaseImpl) UpdateTask(ctx context.Context, param usecase.UpdateTaskParams) (*entities.Task, error) {
-	entityTask := &entities.Task{
-		ID:     param.ID,
-		Name:   param.Name,
-		Status: param.Status,
+	if err := validateUpdate(&param); err != nil {
+		return nil, err
+	}
+
+	if param.ListID != nil {
+		defer a.holdList(ctx, *param.ListID)()
+	}
+
+	for attempt := 1; ; attempt++ {
+		current, err := a.taskRepo.GetTaskByID(ctx, param.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDataNotFound) {
+				return nil, usecase.NotFoundError{
+					Resource: "task",
+					ID:       param.ID,
+				}
+			}
+
+			return nil, fmt.Errorf("repo.GetTaskByID error: %w", err)
+		}
+
+		// copy, as a repository may hand out the stored task itself
+		before := *current
+
+		if err := a.authorize(ctx, PolicyActionUpdate, func() (*entities.Task, error) { return &before, nil }); err != nil {
+			return nil, err
+		}
+
+		entityTask := &entities.Task{
+			ID:          param.ID,
+			Name:        before.Name,
+			ListID:      before.ListID,
+			Description: before.Description,
+			Status:      before.Status,
+			Priority:    before.Priority,
+			DueAt:       before.DueAt,
+			Tags:        before.Tags,
+			ParentID:    before.ParentID,
+			BlockedBy:   before.BlockedBy,
+			Recurrence:  before.Recurrence,
+			CreatedBy:   before.CreatedBy,
+			Version:     param.ExpectedVersion,
+		}
+		if param.Name != nil {
+			entityTask.Name = *param.Name
+		}
+
+		if param.Description != nil {
+			entityTask.Description = *param.Description
+		}
+
+		if param.Status != nil {
+			status, err := a.resolveStatus(*param.Status, before.Status)
+			if err != nil {
+				return nil, err
+			}
+
+			entityTask.Status = status
+		}
+
+		if param.Priority != nil {
+			entityTask.Priority = *param.Priority
+		}
+
+		if param.DueAt != nil {
+			entityTask.DueAt = *param.DueAt
+		}
+
+		if param.Tags != nil {
+			entityTask.Tags = *param.Tags
+		}
+
+		if param.ParentID != nil {
+			entityTask.ParentID = *param.ParentID
+		}
+
+		if param.BlockedBy != nil {
+			entityTask.BlockedBy = *param.BlockedBy
+		}
+
+		if param.Recurrence != nil {
+			entityTask.Recurrence = *param.Recurrence
+		}
+
+		if param.ListID != nil && *param.ListID != before.ListID {
+			entityTask.ListID = *param.ListID
+			if err := a.checkList(ctx, entityTask.ListID); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := checkRecurrence(entityTask); err != nil {
+			return nil, err
+		}
+
+		if err := a.checkTransition(&before, entityTask); err != nil {
+			return nil, err
+		}
+
+		if err := a.checkSubtaskRules(ctx, &before, entityTask); err != nil {
+			return nil, err
+		}
+
+		if err := a.checkDependencies(ctx, &before, entityTask); err != nil {
+			return nil, err
+		}
+
+		next, err := nextOccurrence(&before, entityTask)
+		if err != nil {
+			return nil, err
+		}
+
+		// the next occurrence is created in the transaction completing the current one
+		if next != nil && !a.inTransaction {
+			return a.updateTaskWithinTransaction(ctx, param)
+		}
+
+		if entityTask.Version == 0 {
+			entityTask.Version = before.Version
+		}
+
+		updatedTask, err := a.taskRepo.UpdateTask(ctx, entityTask)
+		if err != nil {
+			if errors.Is(err, repository.ErrDataNotFound) {
+				return nil, usecase.NotFoundError{
+					Resource: "task",
+					ID:       param.ID,
+				}
+			}
+
+			if errors.Is(err, repository.ErrVersionConflict) {
+				if param.ExpectedVersion == 0 && attempt < maxUpdateAttempts {
+					continue
+				}
+
+				return nil, usecase.PreconditionFailedError{
+					Resource: "task",
+					ID:       param.ID,
+				}
+			}
+
+			return nil, fmt.Errorf("repo.UpdateTask error: %w", err)
+		}
+
+		a.recordHistory(ctx, updatedTask.ID, entities.HistoryActionUpdate, diffTask(&before, updatedTask))
+
+		if next != nil {
+			if err := a.createOccurrence(ctx, next); err != nil {
+				return nil, err
+			}
+		}
+
+		return updatedTask, nil
 	}
+}
 
-	updatedTask, err := a.taskRepo.UpdateTask(ctx, entityTask)
+func (a *TaskUseCaseImpl) updateTaskWithinTransaction(ctx context.Context, param usecase.UpdateTaskParams) (*entities.Task, error) {
+	var updated *entities.Task
+
+	err := a.withinTransaction(ctx, func(ctx context.Context, txUseCase *TaskUseCaseImpl) error {
+		var err error
+		updated, err = txUseCase.UpdateTask(ctx, param)
+
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, repository.ErrDataNotFound) {
-			return nil, usecase.NotFoundError{
-				Resource: "task",
-				ID:       param.ID,
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// validateCreate checks the fields of a new task and returns its normalized tags.
+func validateCreate(param usecase.CreateTaskParams) ([]string, error) {
+	if err := validateName(param.Name); err != nil {
+		return nil, err
+	}
+
+	if err := validateDescription(param.Description); err != nil {
+		return nil, err
+	}
+
+	if !param.Priority.Valid() {
+		return nil, errInvalidPriority
+	}
+
+	return normalizeTags(param.Tags)
+}
+
+// validateUpdate checks the fields an update sets and normalizes its tags.
+func validateUpdate(param *usecase.UpdateTaskParams) error {
+	if param.Name != nil {
+		if err := validateName(*param.Name); err != nil {
+			return err
+		}
+	}
+
+	if param.Description != nil {
+		if err := validateDescription(*param.Description); err != nil {
+			return err
+		}
+	}
+
+	if param.ListID != nil && *param.ListID == 0 {
+		return usecase.InvalidArgumentError{Argument: "list_id", Reason: "must be a list id"}
+	}
+
+	if param.Priority != nil && !param.Priority.Valid() {
+		return errInvalidPriority
+	}
+
+	if param.Tags != nil {
+		tags, err := normalizeTags(*param.Tags)
+		if err != nil {
+			return err
+		}
+
+		param.Tags = &tags
+	}
+
+	if param.Recurrence != nil {
+		recurrence, err := normalizeRecurrence(*param.Recurrence)
+		if err != nil {
+			return err
+		}
+
+		param.Recurrence = &recurrence
+	}
+
+	return nil
+}
+
+var errInvalidPriority = usecase.InvalidArgumentError{Argument: "priority", Reason: "must be none, low, medium, high or urgent"}
+
+func validateName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return usecase.InvalidArgumentError{Argument: "name", Reason: fmt.Sprintf("must be 1 to %d characters", maxNameLength)}
+	}
+
+	return nil
+}
+
+func validateDescription(description string) error {
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return usecase.InvalidArgumentError{Argument: "description", Reason: fmt.Sprintf("must be at most %d characters", maxDescriptionLength)}
+	}
+
+	return nil
+}
+
+// normalizeTags lowercases and trims the tags, then sorts them and drops duplicates, so tags are a set.
+// A tag is made of letters, digits, `-` and `_`, and starts with a letter or a digit.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if utf8.RuneCountInString(tag) > maxTagLength || !validTag(tag) {
+			return nil, usecase.InvalidArgumentError{
+				Argument: "tags",
+				Reason:   fmt.Sprintf("%q is not a tag of 1 to %d letters, digits, - or _", tag, maxTagLength),
 			}
 		}
 
-		return nil, fmt.Errorf("repo.UpdateTask error: %w", err)
+		normalized = append(normalized, tag)
+	}
+
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+
+	if len(normalized) > maxTags {
+		return nil, usecase.InvalidArgumentError{Argument: "tags", Reason: fmt.Sprintf("must be at most %d tags", maxTags)}
+	}
+
+	return normalized, nil
+}
+
+func validTag(tag string) bool {
+	for i, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && (i == 0 || (r != '-' && r != '_')) {
+			return false
+		}
 	}
 
-	return updatedTask, nil
+	return tag != ""
 }
 
-// DeleteTask is responsible for deleting a task.
+// DeleteTask is responsible for moving a task to the trash.
+// The subtasks of the task are trashed along with it or handed over to its parent, in one transaction.
 func (a *TaskUseCaseImpl) DeleteTask(ctx context.Context, id uint) error {
+	if err := a.authorize(ctx, PolicyActionDelete, func() (*entities.Task, error) { return a.getTask(ctx, id) }); err != nil {
+		return err
+	}
+
+	children, err := a.taskRepo.ListChildTasks(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repo.ListChildTasks error: %w", err)
+	}
+
+	if len(children) == 0 {
+		return a.deleteTask(ctx, id)
+	}
+
+	return a.withinTransaction(ctx, func(ctx context.Context, txUseCase *TaskUseCaseImpl) error {
+		return txUseCase.deleteTaskTree(ctx, id)
+	})
+}
+
+func (a *TaskUseCaseImpl) deleteTask(ctx context.Context, id uint) error {
 	if err := a.taskRepo.DeleteTask(ctx, id); err != nil {
 		if errors.Is(err, repository.ErrDataNotFound) {
 			return usecase.NotFoundError{
@@ -84,5 +649,150 @@ func (a *TaskUseCaseImpl) DeleteTask(ctx context.Context, id uint) error {
 		return fmt.Errorf("repo.DeleteTask error: %w", err)
 	}
 
+	a.recordHistory(ctx, id, entities.HistoryActionDelete, []entities.FieldChange{
+		{Field: "deleted", Before: false, After: true},
+	})
+
 	return nil
 }
+
+// ListTrash is responsible for listing trashed tasks by page.
+func (a *TaskUseCaseImpl) ListTrash(ctx context.Context, param usecase.ListTasksParams) (*usecase.ListTasksResult, error) {
+	if err := a.authorize(ctx, PolicyActionList, nil); err != nil {
+		return nil, err
+	}
+
+	tasks, total, err := a.taskRepo.ListDeletedTasksByPage(ctx, param.PageIndex, param.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("repo.ListDeletedTasksByPage error: %w", err)
+	}
+
+	return &usecase.ListTasksResult{
+		Tasks: tasks,
+		Total: total,
+	}, nil
+}
+
+// RestoreTask is responsible for moving a task out of the trash, which the policy takes for an update.
+// A subtask whose parent is no longer live is restored as a top-level task, and a task whose list
+// was deleted meanwhile is restored to the default list.
+func (a *TaskUseCaseImpl) RestoreTask(ctx context.Context, id uint) (*entities.Task, error) {
+	if err := a.authorize(ctx, PolicyActionUpdate, func() (*entities.Task, error) { return a.getDeletedTask(ctx, id) }); err != nil {
+		return nil, err
+	}
+
+	// a restored task brings its dependencies back, so it is kept apart from the additions of dependencies
+	lock := a.dependencyLocks.of(ctx)
+	lock.Lock()
+	defer lock.Unlock()
+
+	restoredTask, err := a.taskRepo.RestoreTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return nil, usecase.NotFoundError{
+				Resource: "trashed task",
+				ID:       id,
+			}
+		}
+
+		return nil, fmt.Errorf("repo.RestoreTask error: %w", err)
+	}
+
+	a.recordHistory(ctx, id, entities.HistoryActionRestore, []entities.FieldChange{
+		{Field: "deleted", Before: true, After: false},
+	})
+
+	var update usecase.UpdateTaskParams
+
+	if restoredTask.ParentID != nil {
+		if _, err := a.taskRepo.GetTaskByID(ctx, *restoredTask.ParentID); errors.Is(err, repository.ErrDataNotFound) {
+			var noParent *uint
+			update.ParentID = &noParent
+		}
+	}
+
+	// the dependencies that would close a cycle are dropped, as AddDependency would have rejected them
+	blockedBy, err := a.acyclicBlockers(ctx, restoredTask)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(blockedBy) < len(restoredTask.BlockedBy) {
+		update.BlockedBy = &blockedBy
+	}
+
+	if !isDefaultList(restoredTask.ListID) {
+		// a list being deleted is gone by the time the lock is held, even if it was cleared before the restore
+		defer a.holdList(ctx, restoredTask.ListID)()
+
+		if _, err := a.listRepo.GetListByID(ctx, restoredTask.ListID); errors.Is(err, repository.ErrDataNotFound) {
+			defaultList := entities.DefaultListID
+			update.ListID = &defaultList
+		}
+	}
+
+	if update != (usecase.UpdateTaskParams{}) {
+		update.ID = id
+
+		return a.UpdateTask(ctx, update)
+	}
+
+	return restoredTask, nil
+}
+
+// PurgeTask is responsible for permanently removing a trashed task, which the policy takes for a delete.
+func (a *TaskUseCaseImpl) PurgeTask(ctx context.Context, id uint) error {
+	if err := a.authorize(ctx, PolicyActionDelete, func() (*entities.Task, error) { return a.getDeletedTask(ctx, id) }); err != nil {
+		return err
+	}
+
+	if err := a.taskRepo.PurgeTask(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return usecase.NotFoundError{
+				Resource: "trashed task",
+				ID:       id,
+			}
+		}
+
+		return fmt.Errorf("repo.PurgeTask error: %w", err)
+	}
+
+	return nil
+}
+
+// getDeletedTask gets a trashed task by id.
+func (a *TaskUseCaseImpl) getDeletedTask(ctx context.Context, id uint) (*entities.Task, error) {
+	taskEntity, err := a.taskRepo.GetDeletedTaskByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDataNotFound) {
+			return nil, usecase.NotFoundError{
+				Resource: "trashed task",
+				ID:       id,
+			}
+		}
+
+		return nil, fmt.Errorf("repo.GetDeletedTaskByID error: %w", err)
+	}
+
+	return taskEntity, nil
+}
+
+// PurgeTrash is responsible for permanently removing the tasks trashed before the given time.
+func (a *TaskUseCaseImpl) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]uint, error) {
+	purged, err := a.taskRepo.PurgeDeletedTasks(ctx, deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("repo.PurgeDeletedTasks error: %w", err)
+	}
+
+	return purged, nil
+}
+
+// ListWorkspaces is responsible for listing the workspaces holding tasks, live or trashed.
+func (a *TaskUseCaseImpl) ListWorkspaces(ctx context.Context) ([]string, error) {
+	workspaces, err := a.taskRepo.Workspaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repo.Workspaces error: %w", err)
+	}
+
+	return workspaces, nil
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"context"
@@ -13,6 +14,7 @@ import (
 	"ggltask/internal/task/domain/repository"
 	"ggltask/internal/task/domain/usecase"
 	"ggltask/internal/task/mock/repositorymock"
+	"ggltask/pkg/actor"
 
 	"time"
 
@@ -34,6 +36,31 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+// noSubtasks makes the repository report that no task has subtasks.
+func noSubtasks(mockRepo *repositorymock.MockRepository) {
+	mockRepo.EXPECT().ListChildTasks(gomock.Any(), gomock.Any()).Return([]*entities.Task{}, nil).AnyTimes()
+}
+
+// nopHistory returns a history repository accepting any entry.
+func nopHistory(ctrl *gomock.Controller) repository.HistoryRepository {
+	mockHistory := repositorymock.NewMockHistoryRepository(ctrl)
+	mockHistory.EXPECT().AppendHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, entry *entities.TaskHistory) (*entities.TaskHistory, error) {
+			return entry, nil
+		}).AnyTimes()
+
+	return mockHistory
+}
+
+// noLists returns a list repository expecting no call, for tasks that stay in the default list.
+func noLists(ctrl *gomock.Controller) repository.ListRepository {
+	return repositorymock.NewMockListRepository(ctrl)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func TestNewTaskUseCaseImpl(t *testing.T) {
 	t.Parallel()
 
@@ -41,7 +68,7 @@ func TestNewTaskUseCaseImpl(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := repositorymock.NewMockRepository(ctrl)
-	uc := NewTaskUseCaseImpl(mockRepo)
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
 
 	if reflect.TypeOf(uc) != reflect.TypeOf(&TaskUseCaseImpl{}) {
 		t.Errorf("NewTaskUseCaseImpl() = %v, want %v", uc, &TaskUseCaseImpl{})
@@ -66,8 +93,11 @@ func TestTaskUseCaseImpl_CreateTask(t *testing.T) {
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
 				mockRepo.EXPECT().CreateTask(gomock.Any(), &entities.Task{
-					Name:   "test task",
-					Status: task.TaskStatusIncomplete,
+					Name:      "test task",
+					ListID:    entities.DefaultListID,
+					Status:    task.TaskStatusIncomplete,
+					Tags:      []string{},
+					CreatedBy: actor.Anonymous,
 				}).Return(&entities.Task{
 					ID:        1,
 					Name:      "test task",
@@ -87,6 +117,69 @@ func TestTaskUseCaseImpl_CreateTask(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "details",
+			param: usecase.CreateTaskParams{
+				Name:        "test task",
+				Description: "long form",
+				Priority:    task.PriorityHigh,
+				DueAt:       ptr(time.Date(2030, 1, 2, 9, 0, 0, 0, time.FixedZone("", 2*60*60))),
+				Tags:        []string{" Ops", "db", "ops"},
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().CreateTask(gomock.Any(), &entities.Task{
+					Name:        "test task",
+					ListID:      entities.DefaultListID,
+					Description: "long form",
+					Status:      task.TaskStatusIncomplete,
+					Priority:    task.PriorityHigh,
+					DueAt:       ptr(time.Date(2030, 1, 2, 9, 0, 0, 0, time.FixedZone("", 2*60*60))),
+					Tags:        []string{"db", "ops"},
+					CreatedBy:   actor.Anonymous,
+				}).DoAndReturn(func(_ context.Context, t *entities.Task) (*entities.Task, error) {
+					t.ID = 1
+
+					return t, nil
+				})
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "test task"},
+		},
+		{
+			name: "invalid tag",
+			param: usecase.CreateTaskParams{
+				Name: "test task",
+				Tags: []string{"two words"},
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				return repositorymock.NewMockRepository(ctrl)
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid priority",
+			param: usecase.CreateTaskParams{
+				Name:     "test task",
+				Priority: task.Priority(9),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				return repositorymock.NewMockRepository(ctrl)
+			},
+			wantErr: true,
+		},
+		{
+			name: "description too long",
+			param: usecase.CreateTaskParams{
+				Name:        "test task",
+				Description: strings.Repeat("é", 10001),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				return repositorymock.NewMockRepository(ctrl)
+			},
+			wantErr: true,
+		},
 		{
 			name: "repository error",
 			param: usecase.CreateTaskParams{
@@ -108,7 +201,7 @@ func TestTaskUseCaseImpl_CreateTask(t *testing.T) {
 			t.Parallel()
 
 			mockRepo := tt.mockRepo(gomock.NewController(t))
-			uc := NewTaskUseCaseImpl(mockRepo)
+			uc := NewTaskUseCaseImpl(mockRepo, nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
 
 			got, err := uc.CreateTask(context.Background(), tt.param)
 			if tt.wantErr {
@@ -124,6 +217,56 @@ func TestTaskUseCaseImpl_CreateTask(t *testing.T) {
 	}
 }
 
+func TestTaskUseCaseImpl_GetTask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mockRepo func(ctrl *gomock.Controller) repository.Repository
+		want     *entities.Task
+		wantErr  error
+	}{
+		{
+			name: "success",
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Name: "task"}, nil)
+
+				return mockRepo
+			},
+			want: &entities.Task{ID: 1, Name: "task"},
+		},
+		{
+			name: "not found",
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(nil, repository.ErrDataNotFound)
+
+				return mockRepo
+			},
+			wantErr: usecase.NotFoundError{Resource: "task", ID: uint(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc := NewTaskUseCaseImpl(tt.mockRepo(gomock.NewController(t)), nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
+
+			got, err := uc.GetTask(context.Background(), 1)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestTaskUseCaseImpl_ListTasks(t *testing.T) {
 	t.Parallel()
 
@@ -142,7 +285,7 @@ func TestTaskUseCaseImpl_ListTasks(t *testing.T) {
 			},
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
-				mockRepo.EXPECT().ListTasksByPage(gomock.Any(), 1, 10).Return([]*entities.Task{
+				mockRepo.EXPECT().ListTasksByPage(gomock.Any(), repository.TaskQuery{PageIndex: 1, PageSize: 10}).Return([]*entities.Task{
 					{
 						ID:        1,
 						Name:      "test task",
@@ -176,7 +319,7 @@ func TestTaskUseCaseImpl_ListTasks(t *testing.T) {
 			},
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
-				mockRepo.EXPECT().ListTasksByPage(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, errors.New("repository error"))
+				mockRepo.EXPECT().ListTasksByPage(gomock.Any(), gomock.Any()).Return(nil, 0, errors.New("repository error"))
 
 				return mockRepo
 			},
@@ -190,7 +333,7 @@ func TestTaskUseCaseImpl_ListTasks(t *testing.T) {
 			t.Parallel()
 
 			mockRepo := tt.mockRepo(gomock.NewController(t))
-			uc := NewTaskUseCaseImpl(mockRepo)
+			uc := NewTaskUseCaseImpl(mockRepo, nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
 
 			got, err := uc.ListTasks(context.Background(), tt.param)
 			if tt.wantErr {
@@ -224,15 +367,18 @@ func TestTaskUseCaseImpl_UpdateTask(t *testing.T) {
 			name: "success",
 			param: usecase.UpdateTaskParams{
 				ID:     1,
-				Name:   "updated task",
-				Status: task.TaskStatusCompleted,
+				Name:   ptr("updated task"),
+				Status: ptr(task.TaskStatusCompleted.Ref()),
 			},
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Name: "task", Version: 1}, nil)
 				mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{
-					ID:     1,
-					Name:   "updated task",
-					Status: task.TaskStatusCompleted,
+					ID:      1,
+					Name:    "updated task",
+					Status:  task.TaskStatusCompleted,
+					Version: 1,
 				}).Return(&entities.Task{
 					ID:        1,
 					Name:      "updated task",
@@ -255,27 +401,119 @@ func TestTaskUseCaseImpl_UpdateTask(t *testing.T) {
 		{
 			name: "not found",
 			param: usecase.UpdateTaskParams{
+				ID:     1,
+				Name:   ptr("updated task"),
+				Status: ptr(task.TaskStatusCompleted.Ref()),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(nil, repository.ErrDataNotFound)
+
+				return mockRepo
+			},
+			wantErr: true,
+		},
+		{
+			name: "version conflict",
+			param: usecase.UpdateTaskParams{
+				ID:              1,
+				Name:            ptr("updated task"),
+				Status:          ptr(task.TaskStatusCompleted.Ref()),
+				ExpectedVersion: 2,
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 3}, nil)
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{
+					ID:      1,
+					Name:    "updated task",
+					Status:  task.TaskStatusCompleted,
+					Version: 2,
+				}).Return(nil, repository.ErrVersionConflict)
+
+				return mockRepo
+			},
+			wantErr: true,
+		},
+		{
+			name: "unconditional update retried after a concurrent write",
+			param: usecase.UpdateTaskParams{
+				ID:     1,
+				Name:   ptr("updated task"),
+				Status: ptr(task.TaskStatusCompleted.Ref()),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				gomock.InOrder(
+					mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 1}, nil),
+					mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(nil, repository.ErrVersionConflict),
+					mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 2}, nil),
+					mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{
+						ID:      1,
+						Name:    "updated task",
+						Status:  task.TaskStatusCompleted,
+						Version: 2,
+					}).Return(&entities.Task{ID: 1, Name: "updated task", Status: task.TaskStatusCompleted, Version: 3}, nil),
+				)
+
+				return mockRepo
+			},
+			want: &entities.Task{
 				ID:     1,
 				Name:   "updated task",
 				Status: task.TaskStatusCompleted,
 			},
+		},
+		{
+			name: "partial update keeps the other fields",
+			param: usecase.UpdateTaskParams{
+				ID:     1,
+				Status: ptr(task.TaskStatusCompleted.Ref()),
+			},
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
-				mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(nil, repository.ErrDataNotFound)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Name: "task", Version: 1}, nil)
+				mockRepo.EXPECT().UpdateTask(gomock.Any(), &entities.Task{
+					ID:      1,
+					Name:    "task",
+					Status:  task.TaskStatusCompleted,
+					Version: 1,
+				}).Return(&entities.Task{ID: 1, Name: "task", Status: task.TaskStatusCompleted, Version: 2}, nil)
 
 				return mockRepo
 			},
+			want: &entities.Task{
+				ID:     1,
+				Name:   "task",
+				Status: task.TaskStatusCompleted,
+			},
+		},
+		{
+			name: "name too long",
+			param: usecase.UpdateTaskParams{
+				ID:   1,
+				Name: ptr(strings.Repeat("a", 51)),
+			},
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				return repositorymock.NewMockRepository(ctrl)
+			},
 			wantErr: true,
 		},
 		{
 			name: "repository error",
 			param: usecase.UpdateTaskParams{
 				ID:     1,
-				Name:   "updated task",
-				Status: task.TaskStatusCompleted,
+				Name:   ptr("updated task"),
+				Status: ptr(task.TaskStatusCompleted.Ref()),
 			},
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
+				mockRepo.EXPECT().GetTaskByID(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 1}, nil)
 				mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(nil, errors.New("repository error"))
 
 				return mockRepo
@@ -290,7 +528,7 @@ func TestTaskUseCaseImpl_UpdateTask(t *testing.T) {
 			t.Parallel()
 
 			mockRepo := tt.mockRepo(gomock.NewController(t))
-			uc := NewTaskUseCaseImpl(mockRepo)
+			uc := NewTaskUseCaseImpl(mockRepo, nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
 
 			got, err := uc.UpdateTask(context.Background(), tt.param)
 			if tt.wantErr {
@@ -320,6 +558,7 @@ func TestTaskUseCaseImpl_DeleteTask(t *testing.T) {
 			id:   1,
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
 				mockRepo.EXPECT().DeleteTask(gomock.Any(), uint(1)).Return(nil)
 
 				return mockRepo
@@ -331,6 +570,7 @@ func TestTaskUseCaseImpl_DeleteTask(t *testing.T) {
 			id:   1,
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
 				mockRepo.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).Return(repository.ErrDataNotFound)
 
 				return mockRepo
@@ -342,6 +582,7 @@ func TestTaskUseCaseImpl_DeleteTask(t *testing.T) {
 			id:   1,
 			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
 				mockRepo := repositorymock.NewMockRepository(ctrl)
+				noSubtasks(mockRepo)
 				mockRepo.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).Return(errors.New("repository error"))
 
 				return mockRepo
@@ -356,7 +597,7 @@ func TestTaskUseCaseImpl_DeleteTask(t *testing.T) {
 			t.Parallel()
 
 			mockRepo := tt.mockRepo(gomock.NewController(t))
-			uc := NewTaskUseCaseImpl(mockRepo)
+			uc := NewTaskUseCaseImpl(mockRepo, nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
 
 			err := uc.DeleteTask(context.Background(), tt.id)
 			if tt.wantErr {
@@ -368,3 +609,134 @@ func TestTaskUseCaseImpl_DeleteTask(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskUseCaseImpl_RestoreTask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mockRepo func(ctrl *gomock.Controller) repository.Repository
+		wantErr  error
+	}{
+		{
+			name: "success",
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().RestoreTask(gomock.Any(), uint(1)).Return(&entities.Task{ID: 1, Version: 2}, nil)
+
+				return mockRepo
+			},
+		},
+		{
+			name: "not in trash",
+			mockRepo: func(ctrl *gomock.Controller) repository.Repository {
+				mockRepo := repositorymock.NewMockRepository(ctrl)
+				mockRepo.EXPECT().RestoreTask(gomock.Any(), uint(1)).Return(nil, repository.ErrDataNotFound)
+
+				return mockRepo
+			},
+			wantErr: usecase.NotFoundError{Resource: "trashed task", ID: uint(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc := NewTaskUseCaseImpl(tt.mockRepo(gomock.NewController(t)), nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
+
+			got, err := uc.RestoreTask(context.Background(), 1)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, uint(1), got.ID)
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_PurgeTask(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := repositorymock.NewMockRepository(gomock.NewController(t))
+	mockRepo.EXPECT().PurgeTask(gomock.Any(), uint(1)).Return(nil)
+	mockRepo.EXPECT().PurgeTask(gomock.Any(), uint(2)).Return(repository.ErrDataNotFound)
+	mockRepo.EXPECT().PurgeTask(gomock.Any(), uint(3)).Return(errors.New("repository error"))
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
+
+	assert.NoError(t, uc.PurgeTask(context.Background(), 1))
+	assert.Equal(t, usecase.NotFoundError{Resource: "trashed task", ID: uint(2)}, uc.PurgeTask(context.Background(), 2))
+	assert.Error(t, uc.PurgeTask(context.Background(), 3))
+}
+
+func TestTaskUseCaseImpl_PurgeTrash(t *testing.T) {
+	t.Parallel()
+
+	before := time.Now()
+
+	mockRepo := repositorymock.NewMockRepository(gomock.NewController(t))
+	mockRepo.EXPECT().PurgeDeletedTasks(gomock.Any(), before).Return([]uint{1, 4}, nil)
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
+
+	purged, err := uc.PurgeTrash(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 4}, purged)
+}
+
+func TestTaskUseCaseImpl_ListWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := repositorymock.NewMockRepository(gomock.NewController(t))
+	mockRepo.EXPECT().Workspaces(gomock.Any()).Return([]string{"acme", "default"}, nil)
+	mockRepo.EXPECT().Workspaces(gomock.Any()).Return(nil, errors.New("expected error"))
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(gomock.NewController(t)), noLists(gomock.NewController(t)))
+
+	workspaces, err := uc.ListWorkspaces(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"acme", "default"}, workspaces)
+
+	_, err = uc.ListWorkspaces(context.Background())
+	assert.Error(t, err)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "nil", tags: nil, want: []string{}},
+		{name: "sorted set", tags: []string{"ops", " DB ", "db", "on-call", "größe_2"}, want: []string{"db", "größe_2", "on-call", "ops"}},
+		{name: "empty tag", tags: []string{" "}, wantErr: true},
+		{name: "leading hyphen", tags: []string{"-ops"}, wantErr: true},
+		{name: "comma", tags: []string{"a,b"}, wantErr: true},
+		{name: "too long", tags: []string{strings.Repeat("a", 33)}, wantErr: true},
+		{name: "too many", tags: strings.Split("a b c d e f g h i j k l m n o p q r s t u", " "), wantErr: true},
+		{name: "duplicates count once", tags: append(strings.Split("a b c d e f g h i j k l m n o p q r s t", " "), "a"), want: strings.Split("a b c d e f g h i j k l m n o p q r s t", " ")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := normalizeTags(tt.tags)
+			if tt.wantErr {
+				assert.ErrorAs(t, err, &usecase.InvalidArgumentError{})
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/usecase"
+	"regexp"
+	"slices"
+)
+
+// WorkflowDefinition is a workflow by status names: the statuses, the initial one,
+// and for each status the statuses a task can move to from it. Codes gives the code of every status
+// that is not a built-in one; tasks store codes, so a code must never be reused for another status.
+type WorkflowDefinition struct {
+	Initial     string
+	Statuses    []string
+	Transitions map[string][]string
+	Codes       map[string]task.TaskStatus
+}
+
+// statusNamePattern is the shape of a status name. It starts with a letter, so it never reads as a code.
+var statusNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// DefaultWorkflowDefinition is the workflow used unless another is configured.
+var DefaultWorkflowDefinition = WorkflowDefinition{
+	Initial:  "incomplete",
+	Statuses: []string{"incomplete", "in_progress", "blocked", "in_review", "completed", "archived"},
+	Transitions: map[string][]string{
+		"incomplete":  {"in_progress", "blocked", "completed", "archived"},
+		"in_progress": {"incomplete", "blocked", "in_review", "completed", "archived"},
+		"blocked":     {"incomplete", "in_progress", "archived"},
+		"in_review":   {"in_progress", "completed", "archived"},
+		"completed":   {"incomplete", "archived"},
+		"archived":    {"incomplete"},
+	},
+}
+
+// NewWorkflow checks a workflow definition and builds the workflow. A built-in status keeps its code, every
+// other status needs one of its own, and the initial status and the ends of the transitions must be statuses
+// of the workflow.
+func NewWorkflow(def WorkflowDefinition) (*entities.Workflow, error) {
+	workflow := &entities.Workflow{}
+
+	for _, name := range def.Statuses {
+		status, err := statusCode(def, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := workflow.Resolve(task.StatusRef(name)); ok {
+			return nil, fmt.Errorf("duplicated status %q", name)
+		}
+
+		if workflow.Has(status) {
+			return nil, fmt.Errorf("status %q takes the code %d of another status", name, status)
+		}
+
+		workflow.Statuses = append(workflow.Statuses, entities.WorkflowStatus{Code: status, Name: name})
+	}
+
+	for name := range def.Codes {
+		if _, ok := workflow.Resolve(task.StatusRef(name)); !ok {
+			return nil, fmt.Errorf("code of %q, which is not a status of the workflow", name)
+		}
+	}
+
+	parse := func(name string) (task.TaskStatus, error) {
+		status, ok := workflow.Resolve(task.StatusRef(name))
+		if !ok {
+			return 0, fmt.Errorf("unknown status %q", name)
+		}
+
+		return status.Code, nil
+	}
+
+	initial, err := parse(def.Initial)
+	if err != nil {
+		return nil, fmt.Errorf("initial status %q is not a status of the workflow", def.Initial)
+	}
+
+	workflow.Initial = entities.WorkflowStatus{Code: initial, Name: def.Initial}
+
+	// the transitions follow the order of the statuses, so the workflow reads the same every time
+	for _, s := range workflow.Statuses {
+		transition := entities.WorkflowTransition{From: s.Code, To: []task.TaskStatus{}}
+
+		for _, name := range def.Transitions[s.Name] {
+			to, err := parse(name)
+			if err != nil {
+				return nil, fmt.Errorf("transition from %q to %q leaves the workflow", s.Name, name)
+			}
+
+			if to != s.Code && !slices.Contains(transition.To, to) {
+				transition.To = append(transition.To, to)
+			}
+		}
+
+		workflow.Transitions = append(workflow.Transitions, transition)
+	}
+
+	for name := range def.Transitions {
+		if _, err := parse(name); err != nil {
+			return nil, fmt.Errorf("transition from %q, which is not a status of the workflow", name)
+		}
+	}
+
+	return workflow, nil
+}
+
+// statusCode returns the code of a status of the definition: the code of a built-in status, or the one the
+// definition gives.
+func statusCode(def WorkflowDefinition, name string) (task.TaskStatus, error) {
+	if !statusNamePattern.MatchString(name) {
+		return 0, fmt.Errorf("invalid status name %q", name)
+	}
+
+	code, ok := def.Codes[name]
+
+	if builtin, isBuiltin := task.BuiltinStatus(name); isBuiltin {
+		if ok && code != builtin {
+			return 0, fmt.Errorf("built-in status %q keeps its code %d", name, builtin)
+		}
+
+		return builtin, nil
+	}
+
+	if !ok {
+		return 0, fmt.Errorf("status %q has no code", name)
+	}
+
+	if code.Builtin() || !code.Valid() {
+		return 0, fmt.Errorf("status %q: code %d is negative or that of a built-in status", name, code)
+	}
+
+	return code, nil
+}
+
+// resolveStatus returns the status of the workflow a reference names, the initial status for an empty one, or
+// current when it names the current status of the task, which the workflow may no longer have. This is where
+// every status a task is given is checked against the workflow.
+func (a *TaskUseCaseImpl) resolveStatus(ref task.StatusRef, current task.TaskStatus) (task.TaskStatus, error) {
+	if ref == "" {
+		return a.workflow.Initial.Code, nil
+	}
+
+	if status, ok := a.workflow.Resolve(ref); ok {
+		return status.Code, nil
+	}
+
+	if string(ref) == current.String() || ref == current.Ref() {
+		return current, nil
+	}
+
+	return 0, usecase.InvalidArgumentError{
+		Argument: "status",
+		Reason:   fmt.Sprintf("%s is not a status of the workflow", ref),
+	}
+}
+
+// GetWorkflow is responsible for returning the workflow of the task statuses.
+func (a *TaskUseCaseImpl) GetWorkflow(_ context.Context) (*entities.Workflow, error) {
+	return a.workflow, nil
+}
+
+// checkTransition checks that the workflow lets a task move from its current status to the new one.
+func (a *TaskUseCaseImpl) checkTransition(before, after *entities.Task) error {
+	if !a.workflow.Allows(before.Status, after.Status) {
+		return usecase.InvalidStatusTransitionError{
+			ID:   after.ID,
+			From: a.workflow.Name(before.Status),
+			To:   a.workflow.Name(after.Status),
+		}
+	}
+
+	return nil
+}
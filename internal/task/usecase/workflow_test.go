@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"ggltask/internal/task"
+	"ggltask/internal/task/domain/entities"
+	"ggltask/internal/task/domain/usecase"
+	"ggltask/internal/task/mock/repositorymock"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkflow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		def     WorkflowDefinition
+		wantErr bool
+	}{
+		{
+			name: "default",
+			def:  DefaultWorkflowDefinition,
+		},
+		{
+			name: "statuses without transitions",
+			def:  WorkflowDefinition{Initial: "incomplete", Statuses: []string{"incomplete", "completed"}},
+		},
+		{
+			name: "custom status",
+			def: WorkflowDefinition{
+				Initial:     "incomplete",
+				Statuses:    []string{"incomplete", "wont_fix"},
+				Transitions: map[string][]string{"incomplete": {"wont_fix"}},
+				Codes:       map[string]task.TaskStatus{"wont_fix": 10},
+			},
+		},
+		{
+			name:    "custom status without a code",
+			def:     WorkflowDefinition{Initial: "incomplete", Statuses: []string{"incomplete", "wont_fix"}},
+			wantErr: true,
+		},
+		{
+			name: "custom status with the code of a built-in one",
+			def: WorkflowDefinition{
+				Initial:  "incomplete",
+				Statuses: []string{"incomplete", "wont_fix"},
+				Codes:    map[string]task.TaskStatus{"wont_fix": task.TaskStatusArchived},
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom statuses sharing a code",
+			def: WorkflowDefinition{
+				Initial:  "incomplete",
+				Statuses: []string{"incomplete", "wont_fix", "duplicate"},
+				Codes:    map[string]task.TaskStatus{"wont_fix": 10, "duplicate": 10},
+			},
+			wantErr: true,
+		},
+		{
+			name: "built-in status with another code",
+			def: WorkflowDefinition{
+				Initial:  "incomplete",
+				Statuses: []string{"incomplete", "completed"},
+				Codes:    map[string]task.TaskStatus{"completed": 10},
+			},
+			wantErr: true,
+		},
+		{
+			name: "code of a status outside the workflow",
+			def: WorkflowDefinition{
+				Initial:  "incomplete",
+				Statuses: []string{"incomplete", "completed"},
+				Codes:    map[string]task.TaskStatus{"wont_fix": 10},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid status name",
+			def:     WorkflowDefinition{Initial: "incomplete", Statuses: []string{"incomplete", "Won't fix"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicated status",
+			def:     WorkflowDefinition{Initial: "incomplete", Statuses: []string{"incomplete", "incomplete"}},
+			wantErr: true,
+		},
+		{
+			name:    "initial status outside the workflow",
+			def:     WorkflowDefinition{Initial: "blocked", Statuses: []string{"incomplete", "completed"}},
+			wantErr: true,
+		},
+		{
+			name: "transition to a status outside the workflow",
+			def: WorkflowDefinition{
+				Initial:     "incomplete",
+				Statuses:    []string{"incomplete", "completed"},
+				Transitions: map[string][]string{"incomplete": {"archived"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "transition from a status outside the workflow",
+			def: WorkflowDefinition{
+				Initial:     "incomplete",
+				Statuses:    []string{"incomplete", "completed"},
+				Transitions: map[string][]string{"archived": {"incomplete"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewWorkflow(tt.def)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, got.Statuses, len(tt.def.Statuses))
+			assert.Len(t, got.Transitions, len(tt.def.Statuses))
+		})
+	}
+}
+
+func TestWorkflow_Allows(t *testing.T) {
+	t.Parallel()
+
+	workflow, err := NewWorkflow(WorkflowDefinition{
+		Initial:  "incomplete",
+		Statuses: []string{"incomplete", "in_progress", "completed"},
+		Transitions: map[string][]string{
+			"incomplete":  {"in_progress"},
+			"in_progress": {"completed", "incomplete"},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, workflow.Allows(task.TaskStatusIncomplete, task.TaskStatusInProgress))
+	assert.True(t, workflow.Allows(task.TaskStatusInProgress, task.TaskStatusCompleted))
+	assert.True(t, workflow.Allows(task.TaskStatusCompleted, task.TaskStatusCompleted))
+	assert.False(t, workflow.Allows(task.TaskStatusIncomplete, task.TaskStatusCompleted))
+	assert.False(t, workflow.Allows(task.TaskStatusCompleted, task.TaskStatusIncomplete))
+	assert.False(t, workflow.Allows(task.TaskStatusIncomplete, task.TaskStatusArchived))
+	// a task left in a status dropped from the workflow can move to any status of the workflow
+	assert.True(t, workflow.Allows(task.TaskStatusArchived, task.TaskStatusCompleted))
+}
+
+func TestTaskUseCaseImpl_UpdateTask_Workflow(t *testing.T) {
+	t.Parallel()
+
+	workflow, err := NewWorkflow(WorkflowDefinition{
+		Initial:  "in_progress",
+		Statuses: []string{"in_progress", "in_review", "legal_review", "completed"},
+		Transitions: map[string][]string{
+			"in_progress":  {"in_review"},
+			"in_review":    {"in_progress", "legal_review", "completed"},
+			"legal_review": {"completed"},
+		},
+		Codes: map[string]task.TaskStatus{"legal_review": 10},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		current task.TaskStatus
+		status  task.StatusRef
+		want    task.TaskStatus
+		wantErr error
+	}{
+		{
+			name:    "allowed transition by name",
+			current: task.TaskStatusInProgress,
+			status:  "in_review",
+			want:    task.TaskStatusInReview,
+		},
+		{
+			name:    "allowed transition by code",
+			current: task.TaskStatusInProgress,
+			status:  "4",
+			want:    task.TaskStatusInReview,
+		},
+		{
+			name:    "to a custom status",
+			current: task.TaskStatusInReview,
+			status:  "legal_review",
+			want:    10,
+		},
+		{
+			name:    "from a custom status",
+			current: 10,
+			status:  "completed",
+			want:    task.TaskStatusCompleted,
+		},
+		{
+			name:    "unchanged status",
+			current: task.TaskStatusInProgress,
+			status:  "in_progress",
+			want:    task.TaskStatusInProgress,
+		},
+		{
+			name:    "unchanged status the workflow no longer has",
+			current: task.TaskStatusBlocked,
+			status:  "blocked",
+			want:    task.TaskStatusBlocked,
+		},
+		{
+			name:    "omitted status",
+			current: task.TaskStatusInReview,
+			want:    task.TaskStatusInProgress,
+		},
+		{
+			name:    "illegal transition",
+			current: task.TaskStatusInProgress,
+			status:  "completed",
+			wantErr: usecase.InvalidStatusTransitionError{ID: uint(1), From: "in_progress", To: "completed"},
+		},
+		{
+			name:    "illegal transition from a custom status",
+			current: 10,
+			status:  "in_progress",
+			wantErr: usecase.InvalidStatusTransitionError{ID: uint(1), From: "legal_review", To: "in_progress"},
+		},
+		{
+			name:    "status outside the workflow",
+			current: task.TaskStatusInProgress,
+			status:  "blocked",
+			wantErr: usecase.InvalidArgumentError{Argument: "status", Reason: "blocked is not a status of the workflow"},
+		},
+		{
+			name:    "unknown code",
+			current: task.TaskStatusInProgress,
+			status:  "9",
+			wantErr: usecase.InvalidArgumentError{Argument: "status", Reason: "9 is not a status of the workflow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockRepo := repositorymock.NewMockRepository(ctrl)
+			noSubtasks(mockRepo)
+			withTasks(mockRepo, &entities.Task{ID: 1, Name: "task", Status: tt.current})
+			mockRepo.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+					return saved, nil
+				}).AnyTimes()
+
+			uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl), WithWorkflow(workflow))
+
+			got, err := uc.UpdateTask(context.Background(), usecase.UpdateTaskParams{ID: 1, Status: &tt.status})
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Status)
+		})
+	}
+}
+
+func TestTaskUseCaseImpl_CreateTask_InitialStatus(t *testing.T) {
+	t.Parallel()
+
+	workflow, err := NewWorkflow(WorkflowDefinition{Initial: "in_progress", Statuses: []string{"in_progress", "completed"}})
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+
+	mockRepo := repositorymock.NewMockRepository(ctrl)
+	mockRepo.EXPECT().CreateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, saved *entities.Task) (*entities.Task, error) {
+			saved.ID = 1
+
+			return saved, nil
+		})
+
+	uc := NewTaskUseCaseImpl(mockRepo, nopHistory(ctrl), noLists(ctrl), WithWorkflow(workflow))
+
+	got, err := uc.CreateTask(context.Background(), usecase.CreateTaskParams{Name: "task"})
+	assert.NoError(t, err)
+	assert.Equal(t, task.TaskStatusInProgress, got.Status)
+
+	gotWorkflow, err := uc.GetWorkflow(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, workflow, gotWorkflow)
+}
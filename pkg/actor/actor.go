@@ -0,0 +1,31 @@
+// Package actor carries the identity of whoever issued a request through its context.
+package actor
+
+import "context"
+
+// Anonymous is the actor of a request that did not identify itself.
+const Anonymous = "anonymous"
+
+// UnverifiedPrefix marks an actor a request named itself, with no authentication to back the name.
+const UnverifiedPrefix = "unverified:"
+
+// Unverified returns the actor of a request naming itself name without authenticating.
+func Unverified(name string) string {
+	return UnverifiedPrefix + name
+}
+
+type ctxKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, actor)
+}
+
+// FromContext returns the actor carried by ctx, or Anonymous.
+func FromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(ctxKey{}).(string); ok && actor != "" {
+		return actor
+	}
+
+	return Anonymous
+}
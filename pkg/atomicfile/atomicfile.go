@@ -0,0 +1,56 @@
+// Package atomicfile replaces files so that a crash never leaves them half written.
+package atomicfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Write replaces the file name of dir: it writes a temp file, syncs it and renames it over the old one,
+// so the file holds either its old or its new content, even after a crash.
+func Write(dir, name string, data []byte) error {
+	tmpPath := filepath.Join(dir, name+".tmp")
+
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile error: %w", err)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("write %s error: %w", name, err)
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("sync %s error: %w", name, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close %s error: %w", name, err)
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("os.Rename error: %w", err)
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir flushes the directory entry so a rename survives a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("os.Open error: %w", err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("sync dir error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix starts every API key, telling it apart from other bearer tokens.
+const APIKeyPrefix = "ggl_"
+
+// APIKeySubjectPrefix starts the subject of every API key, so that it collides neither with the subject of
+// another key nor with the subject of a token.
+const APIKeySubjectPrefix = "apikey:"
+
+// apiKeyBytes is the entropy of an API key.
+const apiKeyBytes = 32
+
+// GenerateAPIKey returns a new random API key.
+func GenerateAPIKey() (string, error) {
+	b := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("rand.Read error: %w", err)
+	}
+
+	return APIKeyPrefix + hex.EncodeToString(b), nil
+}
+
+// IsAPIKeySubject reports whether subject is the subject of an API key.
+func IsAPIKeySubject(subject string) bool {
+	return strings.HasPrefix(subject, APIKeySubjectPrefix)
+}
+
+// IsAPIKey reports whether token looks like an API key.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// HashAPIKey returns the hash under which an API key is stored, as hex. API keys are random and long,
+// so a plain SHA-256 is enough: there is nothing to guess that a slow hash would protect.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
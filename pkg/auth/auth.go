@@ -0,0 +1,90 @@
+// Package auth carries the authenticated principal of a request through its context, with the scopes
+// it was granted.
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"ggltask/pkg/workspace"
+)
+
+// The scopes a principal can be granted. Each route requires one of them.
+const (
+	// ScopeTasksRead lets a principal read tasks, lists and their history.
+	ScopeTasksRead = "tasks:read"
+	// ScopeTasksWrite lets a principal create, change and delete tasks and lists.
+	ScopeTasksWrite = "tasks:write"
+	// ScopeKeysAdmin lets a principal create, list and revoke API keys.
+	ScopeKeysAdmin = "keys:admin"
+	// ScopeAll grants every scope.
+	ScopeAll = "*"
+)
+
+// ErrInvalidCredentials is returned for credentials that do not identify a principal.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ValidScope reports whether scope is one of the known scopes.
+func ValidScope(scope string) bool {
+	switch scope {
+	case ScopeTasksRead, ScopeTasksWrite, ScopeKeysAdmin, ScopeAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// Principal is whoever a request was authenticated as. Roles are its roles in the task policy, Workspaces
+// the workspaces it may work in; a principal without workspaces works in the default workspace only.
+type Principal struct {
+	Subject    string
+	Scopes     []string
+	Roles      []string
+	Workspaces []string
+}
+
+// HasScope reports whether the principal was granted scope.
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAll)
+}
+
+// HasRole reports whether the principal was given role.
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// InWorkspace reports whether the principal may work in the workspace id.
+func (p Principal) InWorkspace(id string) bool {
+	if len(p.Workspaces) == 0 {
+		return id == workspace.Default
+	}
+
+	return slices.Contains(p.Workspaces, id) || slices.Contains(p.Workspaces, workspace.All)
+}
+
+// HomeWorkspace returns the workspace the principal works in when a request names none: the first workspace
+// it was granted, or the default workspace.
+func (p Principal) HomeWorkspace() string {
+	for _, id := range p.Workspaces {
+		if id != workspace.All {
+			return id
+		}
+	}
+
+	return workspace.Default
+}
+
+type ctxKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the principal carried by ctx, and whether there is one.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(Principal)
+
+	return p, ok
+}
@@ -9,8 +9,11 @@ type Config[CustomConfigT any] struct {
 	LogLevel  string `yaml:"logLevel" json:"logLevel"`
 	Debug     bool   `yaml:"debug" json:"debug"`
 	HTTP      struct {
-		Port     int `yaml:"port" json:"port"`
-		Timeouts struct {
+		Port int `yaml:"port" json:"port"`
+		// TrustedProxies are the addresses or CIDRs of the proxies whose X-Forwarded-For header names the
+		// client IP. Without any, the client IP is the remote address of the connection.
+		TrustedProxies []string `yaml:"trustedProxies" json:"trustedProxies"`
+		Timeouts       struct {
 			ReadTimeout       time.Duration `yaml:"readTimeout" json:"readTimeout"`
 			ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout" json:"readHeaderTimeout"`
 			WriteTimeout      time.Duration `yaml:"writeTimeout" json:"writeTimeout"`
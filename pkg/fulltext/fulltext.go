@@ -0,0 +1,116 @@
+// Package fulltext tokenizes text for search and highlights the matched tokens.
+package fulltext
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a normalized word and its byte span in the original text.
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenize splits text into runs of letters and digits, in any script, lowercased.
+func Tokenize(text string) []Token {
+	var tokens []Token
+
+	start := -1
+
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if start < 0 {
+				start = i
+			}
+
+			continue
+		}
+
+		if start >= 0 {
+			tokens = append(tokens, Token{Term: strings.ToLower(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, Token{Term: strings.ToLower(text[start:]), Start: start, End: len(text)})
+	}
+
+	return tokens
+}
+
+// Terms returns the distinct normalized terms of text, in order of first appearance.
+func Terms(text string) []string {
+	seen := make(map[string]bool)
+	terms := make([]string, 0)
+
+	for _, token := range Tokenize(text) {
+		if !seen[token.Term] {
+			seen[token.Term] = true
+			terms = append(terms, token.Term)
+		}
+	}
+
+	return terms
+}
+
+// Query is a parsed search query: every term must match a token, the last one as a prefix when Prefix is set.
+type Query struct {
+	Terms  []string
+	Prefix bool
+}
+
+// ParseQuery parses a search query. The last term is a prefix, for autocomplete,
+// unless the query ends with a separator and so the word is complete.
+func ParseQuery(q string) Query {
+	terms := Terms(q)
+
+	last, _ := utf8.DecodeLastRuneInString(q)
+
+	return Query{
+		Terms:  terms,
+		Prefix: len(terms) > 0 && (unicode.IsLetter(last) || unicode.IsNumber(last)),
+	}
+}
+
+// Matches reports whether a token term matches the i-th query term.
+func (q Query) Matches(i int, term string) bool {
+	if q.Prefix && i == len(q.Terms)-1 {
+		return strings.HasPrefix(term, q.Terms[i])
+	}
+
+	return term == q.Terms[i]
+}
+
+// Highlight returns text as an HTML fragment with every word matched by the query wrapped in <mark>,
+// whole even when only its prefix matched.
+func Highlight(text string, q Query) string {
+	var b strings.Builder
+
+	last := 0
+
+	for _, token := range Tokenize(text) {
+		for i := range q.Terms {
+			if !q.Matches(i, token.Term) {
+				continue
+			}
+
+			b.WriteString(html.EscapeString(text[last:token.Start]))
+			b.WriteString("<mark>")
+			b.WriteString(html.EscapeString(text[token.Start:token.End]))
+			b.WriteString("</mark>")
+
+			last = token.End
+
+			break
+		}
+	}
+
+	b.WriteString(html.EscapeString(text[last:]))
+
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package fulltext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []Token{
+		{Term: "café", Start: 0, End: 5},
+		{Term: "über", Start: 6, End: 11},
+		{Term: "2024", Start: 13, End: 17},
+		{Term: "買牛奶", Start: 18, End: 27},
+	}, Tokenize("Café Über, 2024 買牛奶!"))
+
+	assert.Empty(t, Tokenize(" -- "))
+}
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		q    string
+		want Query
+	}{
+		{name: "last word is a prefix", q: "Buy MI", want: Query{Terms: []string{"buy", "mi"}, Prefix: true}},
+		{name: "trailing separator completes the word", q: "buy milk ", want: Query{Terms: []string{"buy", "milk"}}},
+		{name: "repeated words are dropped", q: "milk Milk", want: Query{Terms: []string{"milk"}, Prefix: true}},
+		{name: "no word", q: "?!", want: Query{Terms: []string{}}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, ParseQuery(tt.q))
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "<mark>Buy</mark> <mark>Milk</mark> &amp; <mark>mint</mark>", Highlight("Buy Milk & mint", ParseQuery("buy mi")))
+	assert.Equal(t, "Buy Milk &amp; mint", Highlight("Buy Milk & mint", ParseQuery("tea")))
+	// a complete word does not match as a prefix
+	assert.Equal(t, "<mark>milk</mark> milkshake", Highlight("milk milkshake", ParseQuery("milk ")))
+}
@@ -0,0 +1,418 @@
+// Package jsonpatch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) documents.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPatch is returned for a patch that is not well formed.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrPathNotFound is returned when an operation refers to a location that does not exist.
+	ErrPathNotFound = errors.New("path not found")
+	// ErrTestFailed is returned when a test operation does not match the document.
+	ErrTestFailed = errors.New("test operation failed")
+)
+
+// MergePatch applies a JSON Merge Patch to a JSON document and returns the patched document.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, p any
+
+	if err := unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("decode document error: %w", err)
+	}
+
+	if err := unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("decode patch error: %w", errors.Join(ErrInvalidPatch, err))
+	}
+
+	return json.Marshal(mergePatch(target, p)) //nolint:wrapcheck
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+
+			continue
+		}
+
+		t[name] = mergePatch(t[name], value)
+	}
+
+	return t
+}
+
+// Operation is one operation of a JSON Patch.
+type Operation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from,omitempty"`
+	Value *json.RawMessage `json:"value,omitempty"`
+}
+
+// Apply applies a JSON Patch to a JSON document and returns the patched document.
+// The operations are applied in order and, when one fails, the document is left as it was.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target any
+	if err := unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("decode document error: %w", err)
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("decode patch error: %w", errors.Join(ErrInvalidPatch, err))
+	}
+
+	for i, op := range ops {
+		var err error
+		if target, err = apply(target, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(target) //nolint:wrapcheck
+}
+
+func apply(doc any, op Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("missing value: %w", ErrInvalidPatch)
+		}
+
+		var value any
+		if err := unmarshal(*op.Value, &value); err != nil {
+			return nil, fmt.Errorf("decode value error: %w", errors.Join(ErrInvalidPatch, err))
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			if _, err := get(doc, path); err != nil {
+				return nil, err
+			}
+
+			if len(path) == 0 {
+				return value, nil
+			}
+
+			if doc, err = remove(doc, path); err != nil {
+				return nil, err
+			}
+
+			return add(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+
+			if !equal(current, value) {
+				return nil, ErrTestFailed
+			}
+
+			return doc, nil
+		}
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "move" {
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, fmt.Errorf("cannot move a value into itself: %w", ErrInvalidPatch)
+			}
+
+			if doc, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			// the copy must not share containers with the original
+			value = deepCopy(value)
+		}
+
+		return add(doc, path, value)
+	default:
+		return nil, fmt.Errorf("unknown op %q: %w", op.Op, ErrInvalidPatch)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer %q does not start with /: %w", pointer, ErrInvalidPatch)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch container := doc.(type) {
+		case map[string]any:
+			value, ok := container[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+
+			doc = value
+		case []any:
+			i, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+
+			doc = container[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+
+	return doc, nil
+}
+
+// add sets the value at path and returns the document, which is replaced when path is the root.
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+
+	switch container := parent.(type) {
+	case map[string]any:
+		container[last] = value
+
+		return doc, nil
+	case []any:
+		i := len(container)
+		if last != "-" {
+			if i, err = arrayIndex(last, len(container)); err != nil {
+				return nil, err
+			}
+		}
+
+		container = append(container[:i], append([]any{value}, container[i:]...)...)
+
+		return setParent(doc, path[:len(path)-1], container)
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// remove deletes the value at path and returns the document.
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("cannot remove the whole document: %w", ErrInvalidPatch)
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+
+	switch container := parent.(type) {
+	case map[string]any:
+		if _, ok := container[last]; !ok {
+			return nil, ErrPathNotFound
+		}
+
+		delete(container, last)
+
+		return doc, nil
+	case []any:
+		i, err := arrayIndex(last, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		return setParent(doc, path[:len(path)-1], append(container[:i], container[i+1:]...))
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// setParent stores a resized array back at its path, as slices cannot grow or shrink in place.
+func setParent(doc any, path []string, array []any) (any, error) {
+	if len(path) == 0 {
+		return array, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+
+	switch container := parent.(type) {
+	case map[string]any:
+		container[last] = array
+	case []any:
+		i, err := arrayIndex(last, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		container[i] = array
+	}
+
+	return doc, nil
+}
+
+// arrayIndex parses an array index token, which must not exceed maxIndex.
+func arrayIndex(token string, maxIndex int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPathNotFound
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > maxIndex {
+		return 0, ErrPathNotFound
+	}
+
+	return i, nil
+}
+
+// equal reports whether two decoded JSON values are equal, comparing numbers by value.
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+
+		x, _, errA := big.ParseFloat(a.String(), 10, 256, big.ToNearestEven)
+		y, _, errB := big.ParseFloat(b.String(), 10, 256, big.ToNearestEven)
+
+		return errA == nil && errB == nil && x.Cmp(y) == 0
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for k, v := range a {
+			w, ok := b[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return a == b
+	}
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for k, e := range v {
+			c[k] = deepCopy(e)
+		}
+
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, e := range v {
+			c[i] = deepCopy(e)
+		}
+
+		return c
+	default:
+		return v
+	}
+}
+
+// unmarshal decodes JSON keeping numbers as json.Number, so they compare and re-encode exactly.
+func unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(v); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if dec.More() {
+		return errors.New("unexpected data after the JSON value")
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package jwt
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// jwk is a JSON Web Key (RFC 7517), holding the members of the keys this package verifies with.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// oct
+	K string `json:"k"`
+}
+
+// ParseJWKS parses a JSON Web Key Set. Keys that are not for signatures, or whose type, curve or algorithm
+// is not supported, are skipped, so that a set shared with other services still loads.
+func ParseJWKS(data []byte) ([]Key, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decode jwks error: %w", err)
+	}
+
+	keys := make([]Key, 0, len(set.Keys))
+
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		material, err := k.material()
+		if err != nil {
+			return nil, fmt.Errorf("key %d (%q): %w", i, k.Kid, err)
+		}
+
+		key := Key{ID: k.Kid, Material: material}
+		if material == nil || (k.Alg != "" && k.Alg != key.Algorithm()) {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// material returns the key material of k, or nil for an unsupported key.
+func (k jwk) material() (any, error) {
+	switch k.Kty {
+	case "oct":
+		return decodeMember("k", k.K)
+	case "RSA":
+		n, err := decodeMember("n", k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeMember("e", k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 || exponent.Int64() < 3 { //nolint:mnd
+			return nil, errors.New("invalid RSA exponent")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, nil //nolint:nilnil
+		}
+
+		x, err := decodeMember("x", k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeMember("y", k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) > 32 || len(y) > 32 { //nolint:mnd
+			return nil, errors.New("coordinates too long for P-256")
+		}
+
+		// crypto/ecdh rejects a point off the curve, which ecdsa would blindly verify with
+		point := make([]byte, 65) //nolint:mnd
+		point[0] = 4
+		copy(point[33-len(x):33], x)
+		copy(point[65-len(y):], y)
+
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid P-256 point: %w", err)
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, nil //nolint:nilnil
+	}
+}
+
+func decodeMember(name, value string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid %q member", name)
+	}
+
+	return b, nil
+}
+
+// JWKSFile is a key set read from a JWKS file. The file is checked for changes at most once per reload
+// interval when keys are looked up, so that rotated keys are picked up without a restart. A file that
+// fails to reload is logged and the keys read last are kept.
+type JWKSFile struct {
+	path     string
+	interval time.Duration
+	logger   *zerolog.Logger
+	now      func() time.Time
+
+	mu        sync.Mutex
+	keys      []Key
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewJWKSFile reads the key set at path, failing if it cannot.
+func NewJWKSFile(path string, reloadInterval time.Duration, logger *zerolog.Logger) (*JWKSFile, error) {
+	f := &JWKSFile{
+		path:     path,
+		interval: reloadInterval,
+		logger:   logger,
+		now:      time.Now,
+	}
+
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Keys implements KeySet.
+func (f *JWKSFile) Keys(kid string) ([]Key, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if now := f.now(); now.Sub(f.checkedAt) >= f.interval {
+		f.checkedAt = now
+
+		if err := f.reload(); err != nil {
+			f.logger.Error().Err(err).Str("path", f.path).Msg("reload jwks failed, keeping the previous keys")
+		}
+	}
+
+	return matchKeys(f.keys, kid), nil
+}
+
+// reload loads the file again if it was modified since it was last read.
+func (f *JWKSFile) reload() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("stat jwks error: %w", err)
+	}
+
+	if info.ModTime().Equal(f.modTime) {
+		return nil
+	}
+
+	if err := f.load(); err != nil {
+		return err
+	}
+
+	f.logger.Info().Str("path", f.path).Int("keys", len(f.keys)).Msg("jwks reloaded")
+
+	return nil
+}
+
+func (f *JWKSFile) load() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("stat jwks error: %w", err)
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("read jwks error: %w", err)
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	f.keys = keys
+	f.modTime = info.ModTime()
+	f.checkedAt = f.now()
+
+	return nil
+}
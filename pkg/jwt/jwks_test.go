@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (k testKeys) jwks(t *testing.T, extra ...map[string]string) []byte {
+	t.Helper()
+
+	keys := []map[string]string{
+		{"kty": "oct", "kid": "hmac", "alg": HS256, "k": b64(k.secret)},
+		{
+			"kty": "RSA", "kid": "rsa", "use": "sig",
+			"n": b64(k.rsa.N.Bytes()), "e": b64(big.NewInt(int64(k.rsa.E)).Bytes()),
+		},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(k.ec.X.Bytes()), "y": b64(k.ec.Y.Bytes())},
+	}
+
+	b, err := json.Marshal(map[string]any{"keys": append(keys, extra...)})
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestParseJWKS(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+
+	parsed, err := ParseJWKS(keys.jwks(t,
+		map[string]string{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		map[string]string{"kty": "EC", "kid": "p384", "crv": "P-384", "x": "AA", "y": "AA"},
+		map[string]string{"kty": "OKP", "kid": "ed25519", "crv": "Ed25519", "x": "AA"},
+		map[string]string{"kty": "oct", "kid": "hs512", "alg": "HS512", "k": "c2VjcmV0"},
+	))
+	require.NoError(t, err)
+	require.Len(t, parsed, 3)
+
+	ids := make([]string, 0, len(parsed))
+	for _, key := range parsed {
+		ids = append(ids, key.ID+":"+key.Algorithm())
+	}
+
+	assert.Equal(t, []string{"hmac:HS256", "rsa:RS256", "ec:ES256"}, ids)
+
+	v := NewVerifier(StaticKeys(parsed))
+	v.now = func() time.Time { return testNow }
+
+	for kid, alg := range map[string]string{"hmac": HS256, "rsa": RS256, "ec": ES256} {
+		_, err := v.Verify(keys.sign(t, alg, kid, validClaims()))
+		require.NoError(t, err, kid)
+	}
+}
+
+func TestParseJWKS_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range []string{
+		`not json`,
+		`{"keys":[{"kty":"RSA","n":"","e":"AQAB"}]}`,
+		`{"keys":[{"kty":"RSA","n":"AQAB","e":"AQ"}]}`,
+		`{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`,
+		`{"keys":[{"kty":"oct","k":"!"}]}`,
+	} {
+		_, err := ParseJWKS([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestJWKSFile_Reload(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	logger := zerolog.Nop()
+	now := testNow
+
+	old, rotated := newTestKeys(t), newTestKeys(t)
+	require.NoError(t, os.WriteFile(path, old.jwks(t), 0o600))
+
+	set, err := NewJWKSFile(path, time.Minute, &logger)
+	require.NoError(t, err)
+
+	set.now = func() time.Time { return now }
+	set.checkedAt = now
+
+	v := NewVerifier(set)
+	v.now = func() time.Time { return testNow }
+
+	_, err = v.Verify(old.sign(t, RS256, "rsa", validClaims()))
+	require.NoError(t, err)
+
+	// the rotated keys are only read once the reload interval is over
+	require.NoError(t, os.WriteFile(path, rotated.jwks(t), 0o600))
+	require.NoError(t, os.Chtimes(path, testNow, testNow.Add(time.Second)))
+
+	_, err = v.Verify(rotated.sign(t, RS256, "rsa", validClaims()))
+	require.ErrorIs(t, err, ErrSignature)
+
+	now = now.Add(time.Minute)
+
+	_, err = v.Verify(rotated.sign(t, RS256, "rsa", validClaims()))
+	require.NoError(t, err)
+
+	_, err = v.Verify(old.sign(t, RS256, "rsa", validClaims()))
+	require.ErrorIs(t, err, ErrSignature)
+
+	// a broken file keeps the keys read last
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	require.NoError(t, os.Chtimes(path, testNow, testNow.Add(2*time.Second)))
+
+	now = now.Add(time.Minute)
+
+	_, err = v.Verify(rotated.sign(t, ES256, "ec", validClaims()))
+	require.NoError(t, err)
+
+	_, err = NewJWKSFile(filepath.Join(t.TempDir(), "missing.json"), time.Minute, &logger)
+	require.Error(t, err)
+}
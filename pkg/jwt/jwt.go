@@ -0,0 +1,353 @@
+// Package jwt verifies JSON Web Tokens (RFC 7519) signed with HS256, RS256 or ES256 against a set of keys,
+// and checks their registered claims.
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The signing algorithms a token can be verified with.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+// DefaultRolesClaim is the claim the roles of the subject are read from.
+const DefaultRolesClaim = "roles"
+
+// DefaultWorkspacesClaim is the claim the workspaces of the subject are read from.
+const DefaultWorkspacesClaim = "workspaces"
+
+var (
+	// ErrInvalidToken is returned for a token that cannot be trusted; every other token error wraps it.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrMalformed is returned for a token that is not a well formed JWS compact serialization.
+	ErrMalformed = fmt.Errorf("%w: malformed", ErrInvalidToken)
+	// ErrUnsupportedAlgorithm is returned for a token signed with an algorithm other than HS256, RS256 or ES256.
+	ErrUnsupportedAlgorithm = fmt.Errorf("%w: unsupported algorithm", ErrInvalidToken)
+	// ErrUnknownKey is returned for a token whose key is not in the key set.
+	ErrUnknownKey = fmt.Errorf("%w: unknown key", ErrInvalidToken)
+	// ErrSignature is returned for a token whose signature does not match.
+	ErrSignature = fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	// ErrExpired is returned for a token past its exp claim, or without one.
+	ErrExpired = fmt.Errorf("%w: expired", ErrInvalidToken)
+	// ErrNotYetValid is returned for a token before its nbf claim.
+	ErrNotYetValid = fmt.Errorf("%w: not yet valid", ErrInvalidToken)
+	// ErrIssuer is returned for a token issued by someone else than the expected issuer.
+	ErrIssuer = fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	// ErrAudience is returned for a token not meant for the expected audience.
+	ErrAudience = fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+)
+
+// Claims are the claims of a verified token.
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	// Roles and Workspaces are read from the roles and workspaces claims of the verifier, Scopes from the
+	// standard scope claim.
+	Roles      []string
+	Workspaces []string
+	Scopes     []string
+}
+
+// Verifier verifies tokens against a key set.
+type Verifier struct {
+	keys            KeySet
+	issuer          string
+	audience        string
+	leeway          time.Duration
+	rolesClaim      string
+	workspacesClaim string
+	now             func() time.Time
+}
+
+// Option configures a Verifier.
+type Option func(*Verifier)
+
+// WithIssuer makes the verifier reject tokens whose iss claim is not issuer.
+func WithIssuer(issuer string) Option {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience makes the verifier reject tokens whose aud claim does not contain audience.
+func WithAudience(audience string) Option {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+// WithLeeway tolerates clocks drifting by up to leeway when checking exp and nbf.
+func WithLeeway(leeway time.Duration) Option {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
+
+// WithRolesClaim reads the roles of the subject from claim instead of DefaultRolesClaim.
+func WithRolesClaim(claim string) Option {
+	return func(v *Verifier) {
+		if claim != "" {
+			v.rolesClaim = claim
+		}
+	}
+}
+
+// WithWorkspacesClaim reads the workspaces of the subject from claim instead of DefaultWorkspacesClaim.
+func WithWorkspacesClaim(claim string) Option {
+	return func(v *Verifier) {
+		if claim != "" {
+			v.workspacesClaim = claim
+		}
+	}
+}
+
+// NewVerifier returns a verifier accepting the tokens signed by a key of keys.
+func NewVerifier(keys KeySet, opts ...Option) *Verifier {
+	v := &Verifier{
+		keys:            keys,
+		rolesClaim:      DefaultRolesClaim,
+		workspacesClaim: DefaultWorkspacesClaim,
+		now:             time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+type header struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid"`
+	Crit []string `json:"crit"`
+}
+
+// Verify checks the signature of token, then its exp, nbf, iss and aud claims, and returns its claims.
+// Errors about the token itself wrap ErrInvalidToken; any other error comes from the key set.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:mnd
+		return nil, ErrMalformed
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, err
+	}
+
+	if len(h.Crit) > 0 {
+		return nil, fmt.Errorf("%w: critical header %v", ErrMalformed, h.Crit)
+	}
+
+	if !slices.Contains([]string{HS256, RS256, ES256}, h.Alg) {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedAlgorithm, h.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	keys, err := v.keys.Keys(h.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("lookup key error: %w", err)
+	}
+
+	if err := verifySignature(keys, h.Alg, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+
+	return v.verifyClaims(parts[1])
+}
+
+// verifySignature tries every key fit for alg, so that a token cannot pick how its key is used, such as an
+// RSA public key as an HMAC secret.
+func verifySignature(keys []Key, alg string, signed, sig []byte) error {
+	tried := false
+
+	for _, key := range keys {
+		if key.Algorithm() != alg {
+			continue
+		}
+
+		tried = true
+
+		if key.verify(signed, sig) {
+			return nil
+		}
+	}
+
+	if !tried {
+		return ErrUnknownKey
+	}
+
+	return ErrSignature
+}
+
+func (k Key) verify(signed, sig []byte) bool {
+	digest := sha256.Sum256(signed)
+
+	switch material := k.Material.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, material)
+		mac.Write(signed)
+
+		return hmac.Equal(mac.Sum(nil), sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(material, crypto.SHA256, digest[:], sig) == nil
+	case *ecdsa.PublicKey:
+		if material.Curve != elliptic.P256() || len(sig) != 64 { //nolint:mnd
+			return false
+		}
+
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+
+		return ecdsa.Verify(material, digest[:], r, s)
+	default:
+		return false
+	}
+}
+
+type payload struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Scope     json.RawMessage `json:"scope"`
+}
+
+func (v *Verifier) verifyClaims(segment string) (*Claims, error) {
+	var (
+		p   payload
+		raw map[string]json.RawMessage
+	)
+
+	if err := decodeSegment(segment, &p); err != nil {
+		return nil, err
+	}
+
+	if err := decodeSegment(segment, &raw); err != nil {
+		return nil, err
+	}
+
+	audience, err := stringsClaim(p.Audience)
+	if err != nil {
+		return nil, fmt.Errorf("%w: aud: %w", ErrMalformed, err)
+	}
+
+	scopes, err := stringsClaim(p.Scope)
+	if err != nil {
+		return nil, fmt.Errorf("%w: scope: %w", ErrMalformed, err)
+	}
+
+	roles, err := stringsClaim(raw[v.rolesClaim])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrMalformed, v.rolesClaim, err)
+	}
+
+	workspaces, err := stringsClaim(raw[v.workspacesClaim])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrMalformed, v.workspacesClaim, err)
+	}
+
+	claims := &Claims{
+		Subject:    p.Subject,
+		Issuer:     p.Issuer,
+		Audience:   audience,
+		Roles:      roles,
+		Workspaces: workspaces,
+		Scopes:     scopes,
+	}
+
+	now := v.now()
+
+	if p.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: no exp claim", ErrExpired)
+	}
+
+	claims.ExpiresAt = numericDate(*p.ExpiresAt)
+	if !now.Before(claims.ExpiresAt.Add(v.leeway)) {
+		return nil, ErrExpired
+	}
+
+	if p.NotBefore != nil {
+		claims.NotBefore = numericDate(*p.NotBefore)
+		if now.Add(v.leeway).Before(claims.NotBefore) {
+			return nil, ErrNotYetValid
+		}
+	}
+
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return nil, fmt.Errorf("%w %q", ErrIssuer, claims.Issuer)
+	}
+
+	if v.audience != "" && !slices.Contains(claims.Audience, v.audience) {
+		return nil, fmt.Errorf("%w %v", ErrAudience, claims.Audience)
+	}
+
+	return claims, nil
+}
+
+// numericDate converts a NumericDate, seconds since the epoch which may have a fraction, into a time.
+func numericDate(seconds float64) time.Time {
+	whole, frac := math.Modf(seconds)
+
+	return time.Unix(int64(whole), int64(frac*float64(time.Second))).UTC()
+}
+
+// stringsClaim decodes a claim given either as an array of strings or as a single string, which is split on
+// spaces like the scope claim of RFC 8693.
+func stringsClaim(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, errors.New("want a string or an array of strings")
+	}
+
+	return strings.Fields(s), nil
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	return nil
+}
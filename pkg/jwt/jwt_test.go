@@ -0,0 +1,266 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type testKeys struct {
+	secret []byte
+	rsa    *rsa.PrivateKey
+	ec     *ecdsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return testKeys{secret: []byte("0123456789abcdef0123456789abcdef"), rsa: rsaKey, ec: ecKey}
+}
+
+// sign returns a token of claims signed by the key of keys matching alg.
+func (k testKeys) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	h := map[string]any{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		h["kid"] = kid
+	}
+
+	signed := encodeSegment(t, h) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+
+	switch alg {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case RS256:
+		var err error
+
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case ES256:
+		r, s, err := ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		require.NoError(t, err)
+
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":        "alice",
+		"iss":        "https://gateway.example.com",
+		"aud":        "ggltask",
+		"exp":        testNow.Add(time.Hour).Unix(),
+		"nbf":        testNow.Add(-time.Minute).Unix(),
+		"roles":      []string{"editor"},
+		"scope":      "tasks:read tasks:write",
+		"workspaces": []string{"acme"},
+	}
+}
+
+func withClaim(name string, value any) map[string]any {
+	claims := validClaims()
+	if value == nil {
+		delete(claims, name)
+	} else {
+		claims[name] = value
+	}
+
+	return claims
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	set, err := NewStaticKeys(
+		Key{ID: "hmac", Material: keys.secret},
+		Key{ID: "rsa", Material: &keys.rsa.PublicKey},
+		Key{ID: "ec", Material: &keys.ec.PublicKey},
+	)
+	require.NoError(t, err)
+
+	v := NewVerifier(set, WithIssuer("https://gateway.example.com"), WithAudience("ggltask"), WithLeeway(30*time.Second))
+	v.now = func() time.Time { return testNow }
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "HS256", token: keys.sign(t, HS256, "hmac", validClaims())},
+		{name: "RS256", token: keys.sign(t, RS256, "rsa", validClaims())},
+		{name: "ES256", token: keys.sign(t, ES256, "ec", validClaims())},
+		{name: "without kid", token: keys.sign(t, ES256, "", validClaims())},
+		{name: "audience array", token: keys.sign(t, RS256, "rsa", withClaim("aud", []string{"other", "ggltask"}))},
+		{name: "within leeway", token: keys.sign(t, HS256, "hmac", withClaim("exp", testNow.Add(-10*time.Second).Unix()))},
+		{name: "expired", token: keys.sign(t, HS256, "hmac", withClaim("exp", testNow.Add(-time.Minute).Unix())), wantErr: ErrExpired},
+		{name: "no exp", token: keys.sign(t, HS256, "hmac", withClaim("exp", nil)), wantErr: ErrExpired},
+		{name: "not yet valid", token: keys.sign(t, HS256, "hmac", withClaim("nbf", testNow.Add(time.Minute).Unix())), wantErr: ErrNotYetValid},
+		{name: "wrong issuer", token: keys.sign(t, HS256, "hmac", withClaim("iss", "https://evil.example.com")), wantErr: ErrIssuer},
+		{name: "wrong audience", token: keys.sign(t, HS256, "hmac", withClaim("aud", "other")), wantErr: ErrAudience},
+		{name: "no audience", token: keys.sign(t, HS256, "hmac", withClaim("aud", nil)), wantErr: ErrAudience},
+		{name: "unknown kid", token: keys.sign(t, RS256, "retired", validClaims()), wantErr: ErrUnknownKey},
+		{name: "kid of another algorithm", token: keys.sign(t, HS256, "rsa", validClaims()), wantErr: ErrUnknownKey},
+		{name: "alg none", token: encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + ".", wantErr: ErrUnsupportedAlgorithm},
+		{name: "HS512", token: encodeSegment(t, map[string]string{"alg": "HS512"}) + "." + encodeSegment(t, validClaims()) + ".c2ln", wantErr: ErrUnsupportedAlgorithm},
+		{name: "two segments", token: "a.b", wantErr: ErrMalformed},
+		{name: "bad base64", token: "!!!.b.c", wantErr: ErrMalformed},
+		{name: "bad roles", token: keys.sign(t, HS256, "hmac", withClaim("roles", 42)), wantErr: ErrMalformed},
+		{name: "bad workspaces", token: keys.sign(t, HS256, "hmac", withClaim("workspaces", 42)), wantErr: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			claims, err := v.Verify(tt.token)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.ErrorIs(t, err, ErrInvalidToken)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "alice", claims.Subject)
+			assert.Equal(t, []string{"editor"}, claims.Roles)
+			assert.Equal(t, []string{"acme"}, claims.Workspaces)
+			assert.Equal(t, []string{"tasks:read", "tasks:write"}, claims.Scopes)
+		})
+	}
+}
+
+func TestVerifier_Verify_TamperedToken(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	v := NewVerifier(StaticKeys{{Material: &keys.rsa.PublicKey}, {Material: &keys.ec.PublicKey}})
+	v.now = func() time.Time { return testNow }
+
+	for _, alg := range []string{RS256, ES256} {
+		token := keys.sign(t, alg, "", validClaims())
+		forged := keys.sign(t, alg, "", withClaim("roles", []string{"admin"}))
+
+		// the payload of one token with the signature of another
+		parts := strings.Split(token, ".")
+		tampered := parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2]
+
+		_, err := v.Verify(tampered)
+		require.ErrorIs(t, err, ErrSignature, alg)
+	}
+}
+
+func TestVerifier_Verify_RSAKeyAsHMACSecret(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	v := NewVerifier(StaticKeys{{Material: &keys.rsa.PublicKey}})
+	v.now = func() time.Time { return testNow }
+
+	// a token signed with HMAC over the public key, which is no secret, must not pass for RS256
+	der, err := x509.MarshalPKIXPublicKey(&keys.rsa.PublicKey)
+	require.NoError(t, err)
+
+	forger := testKeys{secret: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})}
+
+	_, err = v.Verify(forger.sign(t, HS256, "", validClaims()))
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestVerifier_RolesClaim(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	v := NewVerifier(StaticKeys{{Material: keys.secret}}, WithRolesClaim("https://ggltask/roles"))
+	v.now = func() time.Time { return testNow }
+
+	claims, err := v.Verify(keys.sign(t, HS256, "", withClaim("https://ggltask/roles", "viewer admin")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"viewer", "admin"}, claims.Roles)
+	assert.Equal(t, testNow.Add(time.Hour), claims.ExpiresAt)
+}
+
+func TestVerifier_WorkspacesClaim(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+	v := NewVerifier(StaticKeys{{Material: keys.secret}}, WithWorkspacesClaim("https://ggltask/workspaces"))
+	v.now = func() time.Time { return testNow }
+
+	claims, err := v.Verify(keys.sign(t, HS256, "", withClaim("https://ggltask/workspaces", "acme globex")))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "globex"}, claims.Workspaces)
+}
+
+func TestParsePublicKeyPEM(t *testing.T) {
+	t.Parallel()
+
+	keys := newTestKeys(t)
+
+	pkix, err := x509.MarshalPKIXPublicKey(&keys.ec.PublicKey)
+	require.NoError(t, err)
+
+	key, err := ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}))
+	require.NoError(t, err)
+	assert.True(t, keys.ec.PublicKey.Equal(key))
+
+	pkcs1 := x509.MarshalPKCS1PublicKey(&keys.rsa.PublicKey)
+
+	key, err = ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: pkcs1}))
+	require.NoError(t, err)
+	assert.True(t, keys.rsa.PublicKey.Equal(key))
+
+	_, err = ParsePublicKeyPEM([]byte("not pem"))
+	require.Error(t, err)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	pkix, err = x509.MarshalPKIXPublicKey(&p384.PublicKey)
+	require.NoError(t, err)
+
+	_, err = ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}))
+	require.Error(t, err)
+}
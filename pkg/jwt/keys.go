@@ -0,0 +1,139 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Key is a verification key. Its material is the []byte secret of an HS256 key, the *rsa.PublicKey of
+// an RS256 key or the *ecdsa.PublicKey on P-256 of an ES256 key.
+type Key struct {
+	// ID matches the kid header of the tokens signed by the key; a key without ID verifies any token.
+	ID       string
+	Material any
+}
+
+// Algorithm returns the algorithm the key verifies, or "" for an unsupported material.
+func (k Key) Algorithm() string {
+	switch material := k.Material.(type) {
+	case []byte:
+		return HS256
+	case *rsa.PublicKey:
+		return RS256
+	case *ecdsa.PublicKey:
+		if material.Curve == elliptic.P256() {
+			return ES256
+		}
+
+		return ""
+	default:
+		return ""
+	}
+}
+
+// KeySet looks up the keys a token may have been signed with.
+type KeySet interface {
+	// Keys returns the keys matching kid, the key ID of a token, which is empty when the token has none.
+	Keys(kid string) ([]Key, error)
+}
+
+// StaticKeys is a fixed key set.
+type StaticKeys []Key
+
+// NewStaticKeys returns a key set of keys, checking that each has a supported material.
+func NewStaticKeys(keys ...Key) (StaticKeys, error) {
+	for _, key := range keys {
+		if key.Algorithm() == "" {
+			return nil, fmt.Errorf("key %q: unsupported key material %T", key.ID, key.Material)
+		}
+	}
+
+	return StaticKeys(keys), nil
+}
+
+// Keys implements KeySet.
+func (s StaticKeys) Keys(kid string) ([]Key, error) {
+	return matchKeys(s, kid), nil
+}
+
+// matchKeys returns the keys matching kid: every key when kid is empty, else the keys with that ID or
+// without one.
+func matchKeys(keys []Key, kid string) []Key {
+	if kid == "" {
+		return keys
+	}
+
+	matched := make([]Key, 0, 1)
+
+	for _, key := range keys {
+		if key.ID == "" || key.ID == kid {
+			matched = append(matched, key)
+		}
+	}
+
+	return matched
+}
+
+// KeySets chains key sets, returning the keys found in all of them.
+type KeySets []KeySet
+
+// Keys implements KeySet.
+func (s KeySets) Keys(kid string) ([]Key, error) {
+	var keys []Key
+
+	for _, set := range s {
+		found, err := set.Keys(kid)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		keys = append(keys, found...)
+	}
+
+	return keys, nil
+}
+
+// ParsePublicKeyPEM parses an RSA or P-256 ECDSA public key from a PEM "PUBLIC KEY" or "RSA PUBLIC KEY"
+// block, or from the first certificate of a PEM "CERTIFICATE" block.
+func ParsePublicKeyPEM(data []byte) (any, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var (
+		key any
+		err error
+	)
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parse public key error: %w", err)
+	}
+
+	if (Key{Material: key}).Algorithm() == "" {
+		return nil, fmt.Errorf("unsupported public key %T", key)
+	}
+
+	return key, nil
+}
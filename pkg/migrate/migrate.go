@@ -0,0 +1,288 @@
+// Package migrate applies versioned up/down SQL migrations and tracks them in a schema version table.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const versionTable = "schema_migrations"
+
+// ErrSchemaBehind is returned by CheckCurrent when migrations are pending.
+var ErrSchemaBehind = errors.New("database schema is behind, run `migrate up`")
+
+// ErrInvalidMigration is returned when a migration file is missing or misnamed.
+var ErrInvalidMigration = errors.New("invalid migration")
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one schema change, read from a `<version>_<name>.up.sql` and `<version>_<name>.down.sql` pair.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration together with whether it has been applied.
+type Status struct {
+	Migration
+	Applied bool
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New loads the migrations found at the root of fsys.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads and orders the migrations found at the root of fsys.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("fs.ReadDir error: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("file %s: %w", entry.Name(), ErrInvalidMigration)
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("file %s: %w", entry.Name(), ErrInvalidMigration)
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("fs.ReadFile error: %w", err)
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		}
+
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("version %d has two names %s and %s: %w", version, m.Name, match[2], ErrInvalidMigration)
+		}
+
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("version %d needs both up and down files: %w", m.Version, ErrInvalidMigration)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest returns the version of the newest known migration.
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status reports every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		statuses = append(statuses, Status{Migration: migration, Applied: applied[migration.Version]})
+	}
+
+	return statuses, nil
+}
+
+// CheckCurrent returns ErrSchemaBehind if any migration has not been applied.
+func (m *Migrator) CheckCurrent(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		if !s.Applied {
+			return fmt.Errorf("migration %d_%s is pending: %w", s.Version, s.Name, ErrSchemaBehind)
+		}
+	}
+
+	return nil
+}
+
+// Up applies every pending migration in version order and returns the ones applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]Migration, 0, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		record := fmt.Sprintf(
+			"INSERT INTO %s (version, name) VALUES (%d, '%s')",
+			versionTable, migration.Version, migration.Name,
+		)
+		if err := m.exec(ctx, migration.Up, record); err != nil {
+			return done, fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the last `steps` applied migrations, newest first, and returns the ones rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]Migration, 0, steps)
+
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if !applied[migration.Version] {
+			continue
+		}
+
+		record := fmt.Sprintf("DELETE FROM %s WHERE version = %d", versionTable, migration.Version)
+		if err := m.exec(ctx, migration.Down, record); err != nil {
+			return done, fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// exec runs the statements of a migration and the version bookkeeping statement in one transaction.
+// Note that MySQL commits DDL implicitly, so a failing MySQL migration may be partially applied.
+func (m *Migrator) exec(ctx context.Context, script, record string) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("db.BeginTx error: %w", err)
+	}
+
+	for _, stmt := range append(splitStatements(script), record) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			_ = tx.Rollback()
+
+			return fmt.Errorf("exec %q error: %w", stmt, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tx.Commit error: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+versionTable+
+		" (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+	if err != nil {
+		return fmt.Errorf("create %s error: %w", versionTable, err)
+	}
+
+	return nil
+}
+
+// appliedVersions returns the applied versions, creating the version table on first use.
+func (m *Migrator) appliedVersions(ctx context.Context) (map[uint]bool, error) {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version FROM "+versionTable)
+	if err != nil {
+		return nil, fmt.Errorf("select %s error: %w", versionTable, err)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint]bool)
+
+	for rows.Next() {
+		var version uint
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("rows.Scan error: %w", err)
+		}
+
+		applied[version] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err error: %w", err)
+	}
+
+	return applied, nil
+}
+
+// splitStatements splits a script on semicolons that end a line, so a file may hold several statements.
+func splitStatements(script string) []string {
+	var stmts []string
+
+	for _, part := range strings.Split(script, ";\n") {
+		stmt := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(part), ";"))
+		if stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	return stmts
+}
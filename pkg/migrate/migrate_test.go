@@ -0,0 +1,114 @@
+package migrate
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFS = fstest.MapFS{
+	"0002_add_index.up.sql":      {Data: []byte("CREATE INDEX a ON t (a);\nCREATE INDEX b ON t (b);\n")},
+	"0002_add_index.down.sql":    {Data: []byte("DROP INDEX b;\nDROP INDEX a;\n")},
+	"0001_create_table.up.sql":   {Data: []byte("CREATE TABLE t (a INT, b INT);\n")},
+	"0001_create_table.down.sql": {Data: []byte("DROP TABLE t;\n")},
+}
+
+const ensureTableQuery = "CREATE TABLE IF NOT EXISTS schema_migrations"
+
+func newMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	m, err := New(db, testFS)
+	require.NoError(t, err)
+
+	return m, mock
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...uint) {
+	mock.ExpectExec(regexp.QuoteMeta(ensureTableQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version"})
+	for _, v := range versions {
+		rows.AddRow(v)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version FROM schema_migrations")).WillReturnRows(rows)
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := Load(testFS)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, uint(1), migrations[0].Version)
+	assert.Equal(t, "create_table", migrations[0].Name)
+	assert.Equal(t, uint(2), migrations[1].Version)
+
+	_, err = Load(fstest.MapFS{"0001_only_up.up.sql": {Data: []byte("SELECT 1;")}})
+	assert.ErrorIs(t, err, ErrInvalidMigration)
+
+	_, err = Load(fstest.MapFS{"readme.md": {Data: []byte("")}})
+	assert.ErrorIs(t, err, ErrInvalidMigration)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	t.Parallel()
+
+	m, mock := newMigrator(t)
+	expectApplied(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX a ON t (a)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX b ON t (b)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name) VALUES (2, 'add_index')")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	applied, err := m.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, uint(2), applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	t.Parallel()
+
+	m, mock := newMigrator(t)
+	expectApplied(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP INDEX b")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DROP INDEX a")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = 2")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	reverted, err := m.Down(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, uint(2), reverted[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_CheckCurrent(t *testing.T) {
+	t.Parallel()
+
+	m, mock := newMigrator(t)
+	expectApplied(mock, 1)
+	assert.ErrorIs(t, m.CheckCurrent(context.Background()), ErrSchemaBehind)
+
+	expectApplied(mock, 1, 2)
+	assert.NoError(t, m.CheckCurrent(context.Background()))
+	assert.Equal(t, uint(2), m.Latest())
+}
@@ -0,0 +1,224 @@
+// Package ratelimit limits the rate of requests per client with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket: Requests tokens refill every Period, up to Burst tokens, and each request takes
+// one. A limit without requests does not limit anything.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	// Burst is the size of the bucket, Requests when zero.
+	Burst int
+}
+
+// Unlimited reports whether the limit lets every request through.
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Requests
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result is the state of a bucket after a request took a token from it, or failed to.
+type Result struct {
+	Allowed bool
+	// Limit is the size of the bucket and Remaining the tokens left in it.
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until a denied request would be allowed, zero for an allowed one.
+	RetryAfter time.Duration
+}
+
+type bucketKey struct {
+	scope string
+	key   string
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens earned since the bucket was last used.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens = math.Min(float64(b.limit.burst()), b.tokens+float64(elapsed)/float64(b.limit.interval()))
+	b.last = now
+}
+
+// untilFull is the time until the bucket is full again.
+func (b *bucket) untilFull() time.Duration {
+	return time.Duration((float64(b.limit.burst()) - b.tokens) * float64(b.limit.interval()))
+}
+
+// Limiter holds the buckets of every client, by scope, such as a route group, and by key, such as a client
+// IP. A bucket left alone until it is full again is no different from a new one, so the buckets of idle
+// clients are dropped every cleanup interval once Start is called.
+type Limiter struct {
+	cleanupInterval time.Duration
+	now             func() time.Time
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewLimiter(cleanupInterval time.Duration) *Limiter {
+	return &Limiter{
+		cleanupInterval: cleanupInterval,
+		now:             time.Now,
+		buckets:         make(map[bucketKey]*bucket),
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+// Allow takes a token from the bucket of key in scope, creating a full bucket of limit for a new client.
+func (l *Limiter) Allow(scope, key string, limit Limit) Result {
+	return l.use(scope, key, limit, true)
+}
+
+// Check reports whether Allow would let a request through, without taking a token or creating a bucket:
+// a client without a bucket has a full one.
+func (l *Limiter) Check(scope, key string, limit Limit) Result {
+	return l.use(scope, key, limit, false)
+}
+
+// use takes a token from the bucket of key in scope when take is set, and returns the state of the bucket.
+func (l *Limiter) use(scope, key string, limit Limit, take bool) Result {
+	if limit.Unlimited() {
+		return Result{Allowed: true}
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	k := bucketKey{scope: scope, key: key}
+
+	b, ok := l.buckets[k]
+	if !ok || b.limit != limit {
+		b = &bucket{limit: limit, tokens: float64(limit.burst()), last: now}
+
+		if take {
+			l.buckets[k] = b
+		}
+	}
+
+	b.refill(now)
+
+	result := Result{Limit: limit.burst()}
+
+	switch {
+	case b.tokens < 1:
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(limit.interval()))
+	case take:
+		b.tokens--
+		result.Allowed = true
+	default:
+		result.Allowed = true
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = b.untilFull()
+
+	return result
+}
+
+// Cleanup drops the buckets that are full again and returns how many it dropped.
+func (l *Limiter) Cleanup() int {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	dropped := 0
+
+	for k, b := range l.buckets {
+		if b.refill(now); b.untilFull() <= 0 {
+			delete(l.buckets, k)
+			dropped++
+		}
+	}
+
+	return dropped
+}
+
+// Len returns the number of buckets held.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.buckets)
+}
+
+// Start runs Cleanup every cleanup interval, until Shutdown is called.
+func (l *Limiter) Start(ctx context.Context) {
+	l.startOnce.Do(func() {
+		go func() {
+			defer close(l.done)
+
+			ticker := time.NewTicker(l.cleanupInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-l.stop:
+					return
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					l.Cleanup()
+				}
+			}
+		}()
+	})
+}
+
+// Shutdown stops the cleanup loop and waits for it to return.
+func (l *Limiter) Shutdown(ctx context.Context) error {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+
+	// a limiter that was never started has no loop to wait for
+	l.startOnce.Do(func() {
+		close(l.done)
+	})
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("rate limiter shutdown error: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(now *time.Time) *Limiter {
+	l := NewLimiter(time.Minute)
+	l.now = func() time.Time { return *now }
+
+	return l
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	limit := Limit{Requests: 2, Period: time.Second, Burst: 3}
+
+	for i := 2; i >= 0; i-- {
+		got := l.Allow("tasks", "10.0.0.1", limit)
+		require.True(t, got.Allowed)
+		assert.Equal(t, 3, got.Limit)
+		assert.Equal(t, i, got.Remaining)
+		assert.Equal(t, time.Duration(3-i)*500*time.Millisecond, got.Reset)
+	}
+
+	got := l.Allow("tasks", "10.0.0.1", limit)
+	assert.Equal(t, Result{Limit: 3, Reset: 1500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}, got)
+
+	// other clients and other scopes have buckets of their own
+	assert.True(t, l.Allow("tasks", "10.0.0.2", limit).Allowed)
+	assert.True(t, l.Allow("admin", "10.0.0.1", limit).Allowed)
+
+	now = now.Add(200 * time.Millisecond)
+	got = l.Allow("tasks", "10.0.0.1", limit)
+	assert.False(t, got.Allowed)
+	assert.Equal(t, 300*time.Millisecond, got.RetryAfter)
+
+	now = now.Add(300 * time.Millisecond)
+	assert.True(t, l.Allow("tasks", "10.0.0.1", limit).Allowed)
+	assert.False(t, l.Allow("tasks", "10.0.0.1", limit).Allowed)
+
+	// a bucket never holds more than its burst
+	now = now.Add(time.Hour)
+	assert.Equal(t, 2, l.Allow("tasks", "10.0.0.1", limit).Remaining)
+}
+
+func TestLimiter_Allow_Unlimited(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	l := newTestLimiter(&now)
+
+	for range 100 {
+		assert.True(t, l.Allow("tasks", "10.0.0.1", Limit{}).Allowed)
+	}
+
+	assert.Zero(t, l.Len())
+}
+
+func TestLimiter_Allow_LimitChanged(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	l := newTestLimiter(&now)
+
+	assert.True(t, l.Allow("tasks", "10.0.0.1", Limit{Requests: 1, Period: time.Minute}).Allowed)
+	assert.False(t, l.Allow("tasks", "10.0.0.1", Limit{Requests: 1, Period: time.Minute}).Allowed)
+	// a bucket whose limit was changed starts over, full
+	assert.True(t, l.Allow("tasks", "10.0.0.1", Limit{Requests: 5, Period: time.Minute}).Allowed)
+}
+
+func TestLimiter_Check(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	limit := Limit{Requests: 1, Period: time.Second, Burst: 2}
+
+	// a new client has a full bucket, and checking it creates none
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 2}, l.Check("auth", "10.0.0.1", limit))
+	assert.Zero(t, l.Len())
+
+	l.Allow("auth", "10.0.0.1", limit)
+	l.Allow("auth", "10.0.0.1", limit)
+
+	got := l.Check("auth", "10.0.0.1", limit)
+	assert.Equal(t, Result{Limit: 2, Reset: 2 * time.Second, RetryAfter: time.Second}, got)
+
+	// checking takes no token
+	now = now.Add(time.Second)
+	assert.True(t, l.Check("auth", "10.0.0.1", limit).Allowed)
+	assert.True(t, l.Check("auth", "10.0.0.1", limit).Allowed)
+	assert.True(t, l.Allow("auth", "10.0.0.1", limit).Allowed)
+	assert.False(t, l.Check("auth", "10.0.0.1", limit).Allowed)
+}
+
+func TestLimiter_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(&now)
+	limit := Limit{Requests: 10, Period: 10 * time.Second}
+
+	for range 8 {
+		l.Allow("tasks", "idle", limit)
+	}
+
+	now = now.Add(5 * time.Second)
+	for range 5 {
+		l.Allow("tasks", "busy", limit)
+	}
+
+	assert.Equal(t, 0, l.Cleanup(), "no bucket is full yet")
+
+	now = now.Add(3 * time.Second)
+	assert.Equal(t, 1, l.Cleanup())
+	assert.Equal(t, 1, l.Len())
+
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, 1, l.Cleanup())
+	assert.Zero(t, l.Len())
+}
+
+func TestLimiter_StartShutdown(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(time.Millisecond)
+	l.Allow("tasks", "10.0.0.1", Limit{Requests: 1000, Period: time.Second})
+	l.Start(context.Background())
+
+	assert.Eventually(t, func() bool { return l.Len() == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, l.Shutdown(context.Background()))
+	require.NoError(t, l.Shutdown(context.Background()))
+
+	// a limiter that never started shuts down right away
+	require.NoError(t, NewLimiter(time.Minute).Shutdown(context.Background()))
+}
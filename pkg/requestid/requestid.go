@@ -0,0 +1,41 @@
+// Package requestid carries the id of a request through its context, so that its log lines and the errors
+// returned to the client can be correlated.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+// idPattern is the shape of a request id sent by a client: letters, digits, `.`, `_`, `:` and `-`, at most
+// 128 characters, enough for a UUID or a trace id and too little to flood the logs.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type ctxKey struct{}
+
+// Valid reports whether id is a well-formed request id.
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// New returns a random request id of 32 hex characters.
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // never fails, see crypto/rand.Read
+
+	return hex.EncodeToString(b[:])
+}
+
+// WithRequestID returns a copy of ctx carrying the request id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request id carried by ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+
+	return id
+}
@@ -0,0 +1,368 @@
+// Package rrule parses and expands the subset of the recurrence rules of RFC 5545 made of
+// FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL. Weeks start on Monday.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule is returned for a rule that is not well formed or uses an unsupported part.
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxInterval bounds INTERVAL, and maxEmptyPeriods the consecutive periods without an occurrence
+// expanded before a series is considered over, as a rule such as `FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30`
+// started in February never occurs again.
+const (
+	maxInterval     = 1000
+	maxEmptyPeriods = 1000
+)
+
+const (
+	untilDateLayout     = "20060102"
+	untilDateTimeLayout = "20060102T150405Z"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a parsed recurrence rule. The first occurrence of a series is its start, which counts
+// towards COUNT like the following ones; the others keep the wall-clock time of the start in its location.
+type Rule struct {
+	Freq Frequency
+	// Interval is the number of periods between two periods with occurrences, at least 1.
+	Interval int
+	// ByDay holds the weekdays of the occurrences, ordered from Monday.
+	ByDay []time.Weekday
+	// ByMonthDay holds the days of the month of the occurrences; a negative day counts from the end of the month.
+	ByMonthDay []int
+	// Count is the number of occurrences of the series, 0 for no limit.
+	Count int
+	// Until is the last time an occurrence can take place, the zero time for no limit.
+	Until time.Time
+
+	// untilDate is set when UNTIL is a date, so the whole day is included in the location of the series
+	untilDate bool
+}
+
+// Parse parses a rule such as `FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE`, optionally prefixed with `RRULE:`.
+// Names and values are case-insensitive. UNTIL is either a date, `20240131`, or a UTC time, `20240131T090000Z`.
+func Parse(s string) (*Rule, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "RRULE:")
+
+	r := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("malformed part %q: %w", part, ErrInvalidRule)
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("repeated part %s: %w", name, ErrInvalidRule)
+		}
+
+		seen[name] = true
+
+		if err := r.parsePart(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("missing FREQ: %w", ErrInvalidRule)
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot both be set: %w", ErrInvalidRule)
+	}
+
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return nil, fmt.Errorf("BYMONTHDAY does not apply to a WEEKLY rule: %w", ErrInvalidRule)
+	}
+
+	return r, nil
+}
+
+func (r *Rule) parsePart(name, value string) error {
+	switch name {
+	case "FREQ":
+		switch freq := Frequency(value); freq {
+		case Daily, Weekly, Monthly, Yearly:
+			r.Freq = freq
+		default:
+			return fmt.Errorf("unsupported FREQ %s: %w", value, ErrInvalidRule)
+		}
+	case "INTERVAL":
+		interval, err := strconv.Atoi(value)
+		if err != nil || interval < 1 || interval > maxInterval {
+			return fmt.Errorf("INTERVAL must be 1 to %d: %w", maxInterval, ErrInvalidRule)
+		}
+
+		r.Interval = interval
+	case "COUNT":
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 1 {
+			return fmt.Errorf("COUNT must be a positive integer: %w", ErrInvalidRule)
+		}
+
+		r.Count = count
+	case "UNTIL":
+		if until, err := time.Parse(untilDateLayout, value); err == nil {
+			r.Until, r.untilDate = until, true
+
+			return nil
+		}
+
+		until, err := time.Parse(untilDateTimeLayout, value)
+		if err != nil {
+			return fmt.Errorf("UNTIL must be a date or a UTC time: %w", ErrInvalidRule)
+		}
+
+		r.Until = until
+	case "BYDAY":
+		for _, day := range strings.Split(value, ",") {
+			weekday, ok := weekdays[day]
+			if !ok {
+				return fmt.Errorf("unsupported BYDAY %s: %w", day, ErrInvalidRule)
+			}
+
+			if !slices.Contains(r.ByDay, weekday) {
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		}
+
+		slices.SortFunc(r.ByDay, func(a, b time.Weekday) int {
+			return daysFromMonday(a) - daysFromMonday(b)
+		})
+	case "BYMONTHDAY":
+		for _, day := range strings.Split(value, ",") {
+			monthDay, err := strconv.Atoi(day)
+			if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+				return fmt.Errorf("BYMONTHDAY must be 1 to 31 or -31 to -1: %w", ErrInvalidRule)
+			}
+
+			if !slices.Contains(r.ByMonthDay, monthDay) {
+				r.ByMonthDay = append(r.ByMonthDay, monthDay)
+			}
+		}
+
+		slices.Sort(r.ByMonthDay)
+	default:
+		return fmt.Errorf("unsupported part %s: %w", name, ErrInvalidRule)
+	}
+
+	return nil
+}
+
+// String returns the rule in its canonical form, its parts in a fixed order and INTERVAL left out when 1.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			days = append(days, strings.ToUpper(weekday.String()[:2]))
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, monthDay := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(monthDay))
+		}
+
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if r.untilDate {
+		parts = append(parts, "UNTIL="+r.Until.Format(untilDateLayout))
+	} else if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilDateTimeLayout))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// After returns at most n occurrences of the series starting at start that take place after the given time, in order.
+func (r *Rule) After(start, after time.Time, n int) []time.Time {
+	occurrences := make([]time.Time, 0, n)
+	if n <= 0 {
+		return occurrences
+	}
+
+	r.each(start, func(t time.Time) bool {
+		if t.After(after) {
+			occurrences = append(occurrences, t)
+		}
+
+		return len(occurrences) < n
+	})
+
+	return occurrences
+}
+
+// each calls fn with the occurrences of the series in order, until fn returns false or the series ends.
+func (r *Rule) each(start time.Time, fn func(time.Time) bool) {
+	count := 0
+
+	emit := func(t time.Time) bool {
+		if r.pastUntil(t) {
+			return false
+		}
+
+		count++
+
+		return fn(t) && (r.Count == 0 || count < r.Count)
+	}
+
+	if !emit(start) {
+		return
+	}
+
+	for period, empty := 0, 0; empty < maxEmptyPeriods; period++ {
+		found := false
+
+		for _, t := range r.expand(start, period) {
+			if !t.After(start) {
+				continue
+			}
+
+			found = true
+
+			if !emit(t) {
+				return
+			}
+		}
+
+		if found {
+			empty = 0
+		} else {
+			empty++
+		}
+	}
+}
+
+// expand returns the candidate occurrences of a period of the series, the period of the start being 0.
+func (r *Rule) expand(start time.Time, period int) []time.Time {
+	year, month, day := start.Date()
+	n := period * r.Interval
+
+	// the first day and the length of the period, in days
+	var (
+		first  time.Time
+		length int
+	)
+
+	switch r.Freq {
+	case Daily:
+		first, length = date(year, month, day+n), 1
+	case Weekly:
+		first, length = date(year, month, day-daysFromMonday(start.Weekday())+7*n), 7
+	case Monthly:
+		first = date(year, month+time.Month(n), 1)
+		length = date(first.Year(), first.Month()+1, 0).Day()
+	default:
+		first = date(year+n, time.January, 1)
+		length = date(first.Year(), time.December, 31).YearDay()
+	}
+
+	hour, minute, sec := start.Clock()
+	candidates := make([]time.Time, 0)
+
+	for i := 0; i < length; i++ {
+		t := time.Date(first.Year(), first.Month(), first.Day()+i, hour, minute, sec, start.Nanosecond(), start.Location())
+		if r.matches(t, start) {
+			candidates = append(candidates, t)
+		}
+	}
+
+	return candidates
+}
+
+// matches reports whether a day of a period is an occurrence. Without BYDAY and BYMONTHDAY,
+// the occurrences fall on the weekday, the day of the month or the day of the year of the start.
+func (r *Rule) matches(t, start time.Time) bool {
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		switch r.Freq {
+		case Daily:
+			return true
+		case Weekly:
+			return t.Weekday() == start.Weekday()
+		case Monthly:
+			return t.Day() == start.Day()
+		default:
+			return t.Month() == start.Month() && t.Day() == start.Day()
+		}
+	}
+
+	if len(r.ByDay) > 0 && !slices.Contains(r.ByDay, t.Weekday()) {
+		return false
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		last := date(t.Year(), t.Month()+1, 0).Day()
+
+		return slices.ContainsFunc(r.ByMonthDay, func(monthDay int) bool {
+			return monthDay == t.Day() || last+monthDay+1 == t.Day()
+		})
+	}
+
+	return true
+}
+
+// pastUntil reports whether a time is after UNTIL; an UNTIL date includes that day in the location of the time.
+func (r *Rule) pastUntil(t time.Time) bool {
+	if r.Until.IsZero() {
+		return false
+	}
+
+	if r.untilDate {
+		year, month, day := t.Date()
+
+		return date(year, month, day).After(r.Until)
+	}
+
+	return t.After(r.Until)
+}
+
+// date returns midnight UTC of a date, normalizing it as time.Date does.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func daysFromMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,191 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{rule: "RRULE:freq=weekly;byday=we,mo,we;interval=2", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=-1,15;COUNT=3", want: "FREQ=MONTHLY;BYMONTHDAY=-1,15;COUNT=3"},
+		{rule: "FREQ=YEARLY;INTERVAL=1;UNTIL=20301231", want: "FREQ=YEARLY;UNTIL=20301231"},
+		{rule: "FREQ=DAILY;UNTIL=20300101T090000Z", want: "FREQ=DAILY;UNTIL=20300101T090000Z"},
+		{rule: "", wantErr: true},
+		{rule: "INTERVAL=2", wantErr: true},
+		{rule: "FREQ=HOURLY", wantErr: true},
+		{rule: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=-1", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=2;UNTIL=20300101", wantErr: true},
+		{rule: "FREQ=DAILY;UNTIL=2030-01-01", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYDAY=1MO", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{rule: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.rule, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.rule)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRule)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestRule_After(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Monday 15 January 2024, 09:00 UTC
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 9, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		n     int
+		want  []time.Time
+	}{
+		{
+			name: "daily",
+			rule: "FREQ=DAILY",
+			want: []time.Time{day(1, 16), day(1, 17), day(1, 18)},
+		},
+		{
+			name: "every other day on weekdays",
+			rule: "FREQ=DAILY;INTERVAL=2;BYDAY=MO,TU,WE,TH,FR",
+			want: []time.Time{day(1, 17), day(1, 19), day(1, 23)},
+		},
+		{
+			name: "weekly on the weekday of the start",
+			rule: "FREQ=WEEKLY",
+			want: []time.Time{day(1, 22), day(1, 29), day(2, 5)},
+		},
+		{
+			name: "every other week on several days",
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			n:    4,
+			want: []time.Time{day(1, 19), day(1, 29), day(2, 2), day(2, 12)},
+		},
+		{
+			name:  "monthly on the last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: day(1, 31),
+			want:  []time.Time{day(2, 29), day(3, 31), day(4, 30)},
+		},
+		{
+			name:  "monthly skips the months without the day of the start",
+			rule:  "FREQ=MONTHLY",
+			start: day(1, 31),
+			want:  []time.Time{day(3, 31), day(5, 31), day(7, 31)},
+		},
+		{
+			name: "monthly on a weekday",
+			rule: "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			n:    2,
+			want: []time.Time{day(9, 13), day(12, 13)},
+		},
+		{
+			name:  "yearly on a leap day",
+			rule:  "FREQ=YEARLY",
+			start: day(2, 29),
+			n:     1,
+			want:  []time.Time{time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "count includes the start",
+			rule: "FREQ=DAILY;COUNT=3",
+			n:    5,
+			want: []time.Time{day(1, 16), day(1, 17)},
+		},
+		{
+			name: "until date includes the whole day",
+			rule: "FREQ=DAILY;UNTIL=20240117",
+			n:    5,
+			want: []time.Time{day(1, 16), day(1, 17)},
+		},
+		{
+			name: "until time",
+			rule: "FREQ=DAILY;UNTIL=20240117T085959Z",
+			n:    5,
+			want: []time.Time{day(1, 16)},
+		},
+		{
+			name:  "after a later time",
+			rule:  "FREQ=WEEKLY;BYDAY=MO",
+			after: day(3, 1),
+			n:     2,
+			want:  []time.Time{day(3, 4), day(3, 11)},
+		},
+		{
+			name:  "keeps the wall-clock time across a daylight saving change",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2024, 3, 9, 9, 0, 0, 0, newYork),
+			n:     2,
+			want:  []time.Time{time.Date(2024, 3, 10, 9, 0, 0, 0, newYork), time.Date(2024, 3, 11, 9, 0, 0, 0, newYork)},
+		},
+		{
+			name:  "a series that never occurs again ends",
+			rule:  "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30",
+			start: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC),
+			want:  []time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+
+			if tt.start.IsZero() {
+				tt.start = start
+			}
+
+			if tt.after.IsZero() {
+				tt.after = tt.start
+			}
+
+			if tt.n == 0 {
+				tt.n = 3
+			}
+
+			got := rule.After(tt.start, tt.after, tt.n)
+			assert.Equal(t, len(tt.want), len(got))
+
+			for i := range got {
+				assert.True(t, tt.want[i].Equal(got[i]), "occurrence %d: want %v, got %v", i, tt.want[i], got[i])
+			}
+		})
+	}
+}
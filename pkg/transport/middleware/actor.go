@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"ggltask/pkg/actor"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader is the request header naming the actor of the request.
+const ActorHeader = "X-Actor"
+
+// GinActor is a middleware that adds the actor named by the X-Actor header to the context of the request,
+// marked unverified, as any client can send any name. Authentication replaces it with the subject of the
+// principal, so the header only names the actor of a request without credentials, such as with auth disabled.
+func GinActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if name := c.GetHeader(ActorHeader); name != "" {
+			c.Request = c.Request.WithContext(actor.WithActor(c.Request.Context(), actor.Unverified(name)))
+		}
+
+		c.Next()
+	}
+}
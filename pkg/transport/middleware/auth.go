@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"ggltask/pkg/actor"
+	"ggltask/pkg/auth"
+	"ggltask/pkg/workspace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// AuthorizationHeader is the request header carrying the credentials of the request.
+const AuthorizationHeader = "Authorization"
+
+// APIKeyAuthenticator resolves an API key into the principal it was issued to. It returns
+// auth.ErrInvalidCredentials for a key that is unknown or revoked.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error)
+}
+
+// GinAPIKeyAuth is a middleware that authenticates the API key sent as `Authorization: Bearer <key>`,
+// adding its principal to the context of the request and making it the actor. A request with an
+// unknown or revoked key is rejected with 401. Requests without an API key pass through
+// unauthenticated, and GinRequireScope turns them away.
+func GinAPIKeyAuth(authenticator APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := bearerToken(c.GetHeader(AuthorizationHeader))
+		if !ok || !auth.IsAPIKey(key) {
+			c.Next()
+
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		principal, err := authenticator.AuthenticateAPIKey(ctx, key)
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			abortUnauthenticated(c, "invalid api key")
+
+			return
+		}
+
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("authenticate api key failed")
+			abortWithError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Internal Server Error")
+
+			return
+		}
+
+		authenticated(c, principal)
+	}
+}
+
+// GinNoAuth is a middleware that grants every scope and workspace and roles to every request, for deployments
+// that turn authentication off. The actor stays whatever the request named.
+func GinNoAuth(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := auth.Principal{Scopes: []string{auth.ScopeAll}, Roles: roles, Workspaces: []string{workspace.All}}
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// GinRequireScope is a middleware that lets through only the requests whose principal was granted
+// scope. Unauthenticated requests are rejected with 401, the others lacking the scope with 403.
+func GinRequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			abortUnauthenticated(c, "authentication required")
+
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			abortWithError(c, http.StatusForbidden, "FORBIDDEN", "missing scope "+scope)
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authenticated adds principal to the context of the request, makes it the actor and adds its subject and
+// roles to the context logger, then runs the next handlers.
+func authenticated(c *gin.Context, principal auth.Principal) {
+	ctx := actor.WithActor(auth.WithPrincipal(c.Request.Context(), principal), principal.Subject)
+
+	logger := zerolog.Ctx(ctx).With().Str("subject", principal.Subject).Strs("roles", principal.Roles).Logger()
+	ctx = logger.WithContext(ctx)
+
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+}
+
+func abortUnauthenticated(c *gin.Context, msg string) {
+	c.Header("WWW-Authenticate", "Bearer")
+	abortWithError(c, http.StatusUnauthorized, "UNAUTHENTICATED", msg)
+}
+
+// bearerToken returns the token of an `Authorization: Bearer <token>` header.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
@@ -1,16 +1,25 @@
 package middleware
 
 import (
+	"ggltask/pkg/requestid"
+
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 )
 
-// GinContextLogger is a middleware that adds a logger to the context of the request.
+// GinContextLogger is a middleware that adds a logger to the context of the request, carrying the request id,
+// the method, the route and the client IP of the request. It must run after GinRequestID; the authentication
+// middlewares add the principal.
 func GinContextLogger(log *zerolog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 
-		logger := log.With().Logger()
+		logger := log.With().
+			Str("request_id", requestid.FromContext(ctx)).
+			Str("method", c.Request.Method).
+			Str("route", c.FullPath()).
+			Str("client_ip", c.ClientIP()).
+			Logger()
 		c.Request = c.Request.WithContext(logger.WithContext(ctx))
 
 		c.Next()
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"ggltask/pkg/auth"
+	"ggltask/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// TokenVerifier verifies a JWT and returns its claims. It returns an error wrapping jwt.ErrInvalidToken for a
+// token that cannot be trusted.
+type TokenVerifier interface {
+	Verify(token string) (*jwt.Claims, error)
+}
+
+// GinJWTAuth is a middleware that authenticates the JWT sent as `Authorization: Bearer <token>`, adding a
+// principal with the subject, roles, scopes and workspaces of the token to the context of the request and making the
+// subject the actor. A request with a token that fails verification, or whose subject is missing or that of
+// an API key, is rejected with 401. Requests already authenticated, or carrying no token or an API key, pass through.
+func GinJWTAuth(verifier TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		token, ok := bearerToken(c.GetHeader(AuthorizationHeader))
+		if _, authenticated := auth.FromContext(ctx); authenticated || !ok || auth.IsAPIKey(token) {
+			c.Next()
+
+			return
+		}
+
+		claims, err := verifier.Verify(token)
+		if errors.Is(err, jwt.ErrInvalidToken) {
+			zerolog.Ctx(ctx).Info().Err(err).Msg("jwt rejected")
+			abortUnauthenticated(c, "invalid token")
+
+			return
+		}
+
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("verify jwt failed")
+			abortWithError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Internal Server Error")
+
+			return
+		}
+
+		if claims.Subject == "" {
+			abortUnauthenticated(c, "token has no subject")
+
+			return
+		}
+
+		// the subjects of API keys are reserved, so that no token can act as, or own the tasks of, a key
+		if auth.IsAPIKeySubject(claims.Subject) {
+			abortUnauthenticated(c, "token subject is reserved")
+
+			return
+		}
+
+		authenticated(c, auth.Principal{
+			Subject:    claims.Subject,
+			Scopes:     claims.Scopes,
+			Roles:      claims.Roles,
+			Workspaces: claims.Workspaces,
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ggltask/pkg/auth"
+	"ggltask/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The clients a rate limit can be kept for.
+const (
+	// RateLimitKeyIP limits every client IP.
+	RateLimitKeyIP = "ip"
+	// RateLimitKeyAPIKey limits every API key, and the requests without one by client IP.
+	RateLimitKeyAPIKey = "api_key"
+	// RateLimitKeySubject limits every authenticated subject, of an API key or a JWT, and the requests
+	// without one by client IP.
+	RateLimitKeySubject = "subject"
+)
+
+// RateLimitKey returns the client a request is counted against.
+type RateLimitKey func(c *gin.Context) string
+
+// NewRateLimitKey returns the RateLimitKey of kind: RateLimitKeyIP, RateLimitKeyAPIKey or RateLimitKeySubject.
+// Keys of different kinds never collide, so the requests falling back to the client IP have buckets of their own.
+func NewRateLimitKey(kind string) (RateLimitKey, error) {
+	switch kind {
+	case RateLimitKeyIP:
+		return func(c *gin.Context) string {
+			return "ip:" + c.ClientIP()
+		}, nil
+	case RateLimitKeyAPIKey:
+		return func(c *gin.Context) string {
+			// the hash, so that the limiter holds no key a memory dump would leak
+			if key, ok := bearerToken(c.GetHeader(AuthorizationHeader)); ok && auth.IsAPIKey(key) {
+				return "key:" + auth.HashAPIKey(key)
+			}
+
+			return "ip:" + c.ClientIP()
+		}, nil
+	case RateLimitKeySubject:
+		return func(c *gin.Context) string {
+			if principal, ok := auth.FromContext(c.Request.Context()); ok && principal.Subject != "" {
+				return "sub:" + principal.Subject
+			}
+
+			return "ip:" + c.ClientIP()
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit key %q", kind)
+	}
+}
+
+// GinRateLimit is a middleware that limits the requests of every client of a route group, named scope, with a
+// token bucket each: one of read for GET, HEAD and OPTIONS requests, one of write for the others. Every limited
+// response carries the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, and a request over
+// the limit is rejected with 429 and a Retry-After header. With a subject key, it must run after authentication.
+func GinRateLimit(limiter *ratelimit.Limiter, scope string, read, write ratelimit.Limit, key RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bucketScope, limit := scope+":write", write
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			bucketScope, limit = scope+":read", read
+		}
+
+		if limit.Unlimited() {
+			c.Next()
+
+			return
+		}
+
+		result := limiter.Allow(bucketScope, key(c), limit)
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			abortTooManyRequests(c, result)
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GinAuthRateLimit is a middleware that limits the failed authentications of every client IP with a token
+// bucket of limit: every 401 response takes a token, and a client left without any is rejected with 429 and
+// a Retry-After header before its credentials are checked. It must run before authentication, so that
+// guessing keys or tokens is throttled whatever the route. The client IP is the remote address unless the
+// router trusts the proxy in front of it, so X-Forwarded-For cannot pass a client for another.
+func GinAuthRateLimit(limiter *ratelimit.Limiter, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit.Unlimited() {
+			c.Next()
+
+			return
+		}
+
+		key := "ip:" + c.ClientIP()
+
+		if result := limiter.Check("auth", key, limit); !result.Allowed {
+			abortTooManyRequests(c, result)
+
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() == http.StatusUnauthorized {
+			limiter.Allow("auth", key, limit)
+		}
+	}
+}
+
+// abortTooManyRequests rejects a request over the limit, telling the client when to retry.
+func abortTooManyRequests(c *gin.Context, result ratelimit.Result) {
+	retryAfter := ceilSeconds(result.RetryAfter)
+	msg := fmt.Sprintf("rate limit exceeded, retry in %ds", retryAfter)
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	abortWithError(c, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", msg)
+}
+
+// ceilSeconds rounds d up to whole seconds, as the headers count them.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
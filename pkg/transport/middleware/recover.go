@@ -10,6 +10,7 @@ import (
 )
 
 // GinRecover is a middleware that recovers from panics and logs the error.
+// The client gets a 500 error body carrying the request id, unless the response is already written.
 func GinRecover() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, err any) {
 		const size = 64 << 10
@@ -26,8 +27,12 @@ func GinRecover() gin.HandlerFunc {
 			"stack":   string(buf),
 		}).Msg("middleware.recover catch panic")
 
-		c.Writer.WriteHeader(http.StatusInternalServerError)
+		if c.Writer.Written() {
+			c.Abort()
 
-		c.Next()
+			return
+		}
+
+		abortWithError(c, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "Internal Server Error")
 	})
 }
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"ggltask/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the request and response header carrying the id of the request.
+const RequestIDHeader = "X-Request-ID"
+
+// GinRequestID is a middleware that adds the id of the request to its context and echoes it in the
+// X-Request-ID response header. The id sent by the client is kept when well-formed; otherwise, or without
+// one, a new id is generated.
+func GinRequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(requestid.WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+	}
+}
+
+// abortWithError rejects the request with status and an error body carrying code, msg and the request id.
+func abortWithError(c *gin.Context, status int, code, msg string) {
+	body := gin.H{
+		"error_code":    code,
+		"error_message": msg,
+	}
+
+	if id := requestid.FromContext(c.Request.Context()); id != "" {
+		body["request_id"] = id
+	}
+
+	c.AbortWithStatusJSON(status, body)
+}
@@ -12,6 +12,7 @@ import (
 )
 
 // GinTimeout is a middleware that sets a timeout for the request.
+// A request running out of time without a response gets a 504 error body carrying the request id.
 func GinTimeout(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
@@ -23,7 +24,10 @@ func GinTimeout(timeout time.Duration) gin.HandlerFunc {
 			cancelCtx()
 
 			if errors.Is(newCtx.Err(), context.DeadlineExceeded) {
-				c.Writer.WriteHeader(http.StatusGatewayTimeout)
+				if !c.Writer.Written() {
+					abortWithError(c, http.StatusGatewayTimeout, "GATEWAY_TIMEOUT", "Gateway Timeout")
+				}
+
 				zerolog.Ctx(newCtx).Error().Fields(map[string]any{
 					"method":  c.Request.Method,
 					"url":     fmt.Sprintf("%s%s", c.Request.Host, c.Request.RequestURI),
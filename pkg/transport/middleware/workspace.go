@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"ggltask/pkg/auth"
+	"ggltask/pkg/workspace"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkspaceHeader is the request header naming the workspace of the request.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// GinWorkspace is a middleware that adds the workspace of the request to its context. It must run after the
+// authentication middlewares: an authenticated request works in the workspace named by the X-Workspace-ID
+// header, or without the header in the home workspace of its principal, and one naming a workspace its
+// principal was not granted is rejected with 403. An unauthenticated request works in the workspace named
+// by the header, or in the default workspace. A malformed workspace is rejected with 400.
+func GinWorkspace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		id := c.GetHeader(WorkspaceHeader)
+		if id != "" && !workspace.Valid(id) {
+			abortWithError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid workspace id")
+
+			return
+		}
+
+		if principal, ok := auth.FromContext(ctx); ok {
+			if id == "" {
+				id = principal.HomeWorkspace()
+			}
+
+			if !principal.InWorkspace(id) {
+				abortWithError(c, http.StatusForbidden, "FORBIDDEN", "workspace "+id+" is not allowed")
+
+				return
+			}
+		}
+
+		if id != "" {
+			c.Request = c.Request.WithContext(workspace.WithWorkspace(ctx, id))
+		}
+
+		c.Next()
+	}
+}
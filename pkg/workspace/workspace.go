@@ -0,0 +1,43 @@
+// Package workspace carries the workspace a request works in through its context.
+// Every repository partitions its data by the workspace of the context it is called with.
+package workspace
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the workspace of a request that did not name one.
+const Default = "default"
+
+// All grants a principal every workspace.
+const All = "*"
+
+// idPattern is the shape of a workspace id: lowercase letters, digits, `-` and `_`, at most 64 characters.
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+type ctxKey struct{}
+
+// Valid reports whether id is a well-formed workspace id.
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// ValidGrant reports whether id may be granted to a principal: a well-formed workspace id, or All.
+func ValidGrant(id string) bool {
+	return id == All || Valid(id)
+}
+
+// WithWorkspace returns a copy of ctx carrying the workspace.
+func WithWorkspace(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the workspace carried by ctx, or Default.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	return Default
+}